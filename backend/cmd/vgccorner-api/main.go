@@ -1,9 +1,17 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
+	"os/signal"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/dtsong/vgccorner/backend/internal/db"
 	"github.com/dtsong/vgccorner/backend/internal/httpapi"
@@ -26,15 +34,82 @@ func main() {
 			logger.Errorf("failed to close database: %v", err)
 		}
 	}()
+	database.ConfigurePool(
+		getEnvInt("DB_MAX_OPEN_CONNS", 0),
+		getEnvInt("DB_MAX_IDLE_CONNS", 0),
+		getEnvDuration("DB_CONN_MAX_LIFETIME", 0),
+	)
+	database.EnableSlowQueryLog(logger, time.Duration(getEnvInt("SLOW_QUERY_MS", 0))*time.Millisecond)
+
+	// Multi-instance deployments share cache invalidations over LISTEN/NOTIFY
+	listenCtx, stopListening := context.WithCancel(context.Background())
+	defer stopListening()
+	if getEnvBool("DB_NOTIFY_CHANGES", false) {
+		database.EnableChangeNotifications()
+		err := database.ListenForChanges(listenCtx, func(err error) {
+			logger.Errorf("battle change listener: %v", err)
+		})
+		if err != nil {
+			logger.Errorf("change notifications disabled: %v", err)
+		}
+	}
 
 	addr := getAddr()
 	logger.Infof("starting vgccorner-api on %s", addr)
 
-	router := httpapi.NewRouter(logger, database)
+	api := httpapi.NewServer(logger, database,
+		httpapi.WithAnalysisConcurrency(
+			getEnvInt("MAX_CONCURRENT_ANALYSES", runtime.NumCPU()),
+			getEnvInt("MAX_QUEUED_ANALYSES", 4*runtime.NumCPU()),
+		),
+		httpapi.WithSummaryCacheSize(getEnvInt("SUMMARY_CACHE_SIZE", 128)),
+		httpapi.WithAdminToken(getEnv("ADMIN_TOKEN", "")),
+		httpapi.WithStatsMapLimit(getEnvInt("STATS_MAP_LIMIT", 0)),
+		httpapi.WithResponseNaming(getEnv("RESPONSE_NAMING", "camel")),
+		httpapi.WithMetaWorker(metaWorkerInterval()),
+		httpapi.WithCORS(getEnvList("CORS_ALLOWED_ORIGINS"), getEnvDuration("CORS_MAX_AGE", 10*time.Minute)),
+		httpapi.WithWebhook(getEnv("WEBHOOK_URL", ""), getEnv("WEBHOOK_SECRET", "")),
+		httpapi.WithOnClose(func() error {
+			stopListening()
+			return nil
+		}),
+	)
+	defer func() {
+		if err := api.Close(); err != nil {
+			logger.Errorf("failed to close server: %v", err)
+		}
+	}()
+	server := newHTTPServer(addr, api)
+
+	// Stop accepting requests on SIGINT/SIGTERM and let in-flight ones finish
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	shutdownDone := make(chan struct{})
+	go func() {
+		defer close(shutdownDone)
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(),
+			getEnvDuration("SERVER_SHUTDOWN_TIMEOUT", 30*time.Second))
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			logger.Errorf("graceful shutdown failed: %v", err)
+		}
+	}()
 
-	if err := http.ListenAndServe(addr, router); err != nil {
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 		logger.Fatalf("server failed: %v", err)
 	}
+	<-shutdownDone
+	logger.Infof("server stopped")
+}
+
+// metaWorkerInterval returns how often to precompute meta stats, or 0 when
+// META_WORKER_ENABLED is off.
+func metaWorkerInterval() time.Duration {
+	if !getEnvBool("META_WORKER_ENABLED", false) {
+		return 0
+	}
+	return getEnvDuration("META_WORKER_INTERVAL", 15*time.Minute)
 }
 
 func getAddr() string {
@@ -45,6 +120,21 @@ func getAddr() string {
 	return ":8080"
 }
 
+// newHTTPServer builds the API server with timeouts and header limits that can
+// be tuned via env. Read/write timeouts are generous so large log uploads and
+// slow analysis still complete.
+func newHTTPServer(addr string, handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: getEnvDuration("SERVER_READ_HEADER_TIMEOUT", 10*time.Second),
+		ReadTimeout:       getEnvDuration("SERVER_READ_TIMEOUT", 60*time.Second),
+		WriteTimeout:      getEnvDuration("SERVER_WRITE_TIMEOUT", 120*time.Second),
+		IdleTimeout:       getEnvDuration("SERVER_IDLE_TIMEOUT", 120*time.Second),
+		MaxHeaderBytes:    getEnvInt("SERVER_MAX_HEADER_BYTES", 1<<20),
+	}
+}
+
 func getDBConnString() string {
 	host := getEnv("DB_HOST", "localhost")
 	port := getEnv("DB_PORT", "5432")
@@ -63,3 +153,51 @@ func getEnv(key, defaultVal string) string {
 	}
 	return defaultVal
 }
+
+func getEnvDuration(key string, defaultVal time.Duration) time.Duration {
+	if v := os.Getenv(key); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			return d
+		}
+	}
+	return defaultVal
+}
+
+func getEnvBool(key string, defaultVal bool) bool {
+	if v := os.Getenv(key); v != "" {
+		if b, err := strconv.ParseBool(v); err == nil {
+			return b
+		}
+	}
+	return defaultVal
+}
+
+// getEnvList splits a comma-separated env var, dropping empty entries.
+func getEnvList(key string) []string {
+	var list []string
+	for _, v := range strings.Split(os.Getenv(key), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+// envLogger reports env vars that are set but unusable.
+var envLogger = observability.NewLogger()
+
+// getEnvInt reads a non-negative int. Zero is kept, since it means disabled
+// or unlimited for several settings; anything else that doesn't parse logs a
+// warning and falls back to defaultVal.
+func getEnvInt(key string, defaultVal int) int {
+	v := os.Getenv(key)
+	if v == "" {
+		return defaultVal
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		envLogger.Warnf("ignoring %s=%q: expected a non-negative integer, using %d", key, v, defaultVal)
+		return defaultVal
+	}
+	return n
+}
@@ -1,9 +1,12 @@
 package main
 
 import (
+	"net/http"
 	"os"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestGetAddr(t *testing.T) {
@@ -230,3 +233,130 @@ func TestGetEnv(t *testing.T) {
 		})
 	}
 }
+
+func TestGetEnvList(t *testing.T) {
+	t.Setenv("TEST_LIST", " https://a.example, ,https://b.example,")
+	got := getEnvList("TEST_LIST")
+	if !reflect.DeepEqual(got, []string{"https://a.example", "https://b.example"}) {
+		t.Errorf("expected two trimmed origins, got %q", got)
+	}
+
+	t.Setenv("TEST_LIST", "")
+	if got := getEnvList("TEST_LIST"); len(got) != 0 {
+		t.Errorf("expected no entries, got %q", got)
+	}
+}
+
+func TestGetEnvInt(t *testing.T) {
+	tests := []struct {
+		name     string
+		envValue string
+		expected int
+	}{
+		{name: "unset uses default", envValue: "", expected: 128},
+		{name: "positive value", envValue: "64", expected: 64},
+		{name: "zero is kept", envValue: "0", expected: 0},
+		{name: "negative falls back", envValue: "-1", expected: 128},
+		{name: "unparseable falls back", envValue: "lots", expected: 128},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TEST_INT", tt.envValue)
+			if got := getEnvInt("TEST_INT", 128); got != tt.expected {
+				t.Errorf("expected %d, got %d", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestNewHTTPServer(t *testing.T) {
+	tests := []struct {
+		name     string
+		setupEnv map[string]string
+		check    func(t *testing.T, srv *http.Server)
+	}{
+		{
+			name:     "defaults",
+			setupEnv: map[string]string{},
+			check: func(t *testing.T, srv *http.Server) {
+				if srv.ReadHeaderTimeout != 10*time.Second {
+					t.Errorf("expected ReadHeaderTimeout 10s, got %v", srv.ReadHeaderTimeout)
+				}
+				if srv.ReadTimeout != 60*time.Second {
+					t.Errorf("expected ReadTimeout 60s, got %v", srv.ReadTimeout)
+				}
+				if srv.WriteTimeout != 120*time.Second {
+					t.Errorf("expected WriteTimeout 120s, got %v", srv.WriteTimeout)
+				}
+				if srv.IdleTimeout != 120*time.Second {
+					t.Errorf("expected IdleTimeout 120s, got %v", srv.IdleTimeout)
+				}
+				if srv.MaxHeaderBytes != 1<<20 {
+					t.Errorf("expected MaxHeaderBytes %d, got %d", 1<<20, srv.MaxHeaderBytes)
+				}
+			},
+		},
+		{
+			name: "values from env",
+			setupEnv: map[string]string{
+				"SERVER_READ_HEADER_TIMEOUT": "5s",
+				"SERVER_READ_TIMEOUT":        "2m",
+				"SERVER_WRITE_TIMEOUT":       "3m",
+				"SERVER_IDLE_TIMEOUT":        "90s",
+				"SERVER_MAX_HEADER_BYTES":    "65536",
+			},
+			check: func(t *testing.T, srv *http.Server) {
+				if srv.ReadHeaderTimeout != 5*time.Second {
+					t.Errorf("expected ReadHeaderTimeout 5s, got %v", srv.ReadHeaderTimeout)
+				}
+				if srv.ReadTimeout != 2*time.Minute {
+					t.Errorf("expected ReadTimeout 2m, got %v", srv.ReadTimeout)
+				}
+				if srv.WriteTimeout != 3*time.Minute {
+					t.Errorf("expected WriteTimeout 3m, got %v", srv.WriteTimeout)
+				}
+				if srv.IdleTimeout != 90*time.Second {
+					t.Errorf("expected IdleTimeout 90s, got %v", srv.IdleTimeout)
+				}
+				if srv.MaxHeaderBytes != 65536 {
+					t.Errorf("expected MaxHeaderBytes 65536, got %d", srv.MaxHeaderBytes)
+				}
+			},
+		},
+		{
+			name: "invalid values fall back to defaults",
+			setupEnv: map[string]string{
+				"SERVER_READ_TIMEOUT":     "soon",
+				"SERVER_MAX_HEADER_BYTES": "-1",
+			},
+			check: func(t *testing.T, srv *http.Server) {
+				if srv.ReadTimeout != 60*time.Second {
+					t.Errorf("expected ReadTimeout 60s, got %v", srv.ReadTimeout)
+				}
+				if srv.MaxHeaderBytes != 1<<20 {
+					t.Errorf("expected MaxHeaderBytes %d, got %d", 1<<20, srv.MaxHeaderBytes)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.setupEnv {
+				t.Setenv(k, v)
+			}
+
+			handler := http.NewServeMux()
+			srv := newHTTPServer(":8080", handler)
+
+			if srv.Addr != ":8080" {
+				t.Errorf("expected addr :8080, got %q", srv.Addr)
+			}
+			if srv.Handler != handler {
+				t.Error("expected handler to be set")
+			}
+			tt.check(t, srv)
+		})
+	}
+}
@@ -0,0 +1,100 @@
+// Command vgccorner-cli runs the battle log parser locally, without the API
+// server or a database:
+//
+//	vgccorner-cli parse [--format=json|summary] [file]
+//
+// The log is read from file, or from stdin when file is omitted or "-".
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/dtsong/vgccorner/backend/internal/analysis"
+)
+
+// Exit codes: parse and I/O failures are distinguished from bad usage.
+const (
+	exitOK    = 0
+	exitError = 1
+	exitUsage = 2
+)
+
+const usage = `Usage: vgccorner-cli parse [--format=json|summary] [file]
+
+Parses a Pokémon Showdown battle log from file, or from stdin when file is
+omitted or "-", and prints the battle summary.
+`
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run executes a command line and returns the process exit code.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] != "parse" {
+		fmt.Fprint(stderr, usage)
+		return exitUsage
+	}
+
+	fs := flag.NewFlagSet("parse", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprint(stderr, usage)
+		fs.PrintDefaults()
+	}
+	format := fs.String("format", "json", `output format: "json" for the full summary or "summary" for a short recap`)
+	if err := fs.Parse(args[1:]); err != nil {
+		return exitUsage
+	}
+	if fs.NArg() > 1 || (*format != "json" && *format != "summary") {
+		fs.Usage()
+		return exitUsage
+	}
+
+	in := stdin
+	if path := fs.Arg(0); path != "" && path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			fmt.Fprintf(stderr, "vgccorner-cli: %v\n", err)
+			return exitError
+		}
+		defer func() { _ = f.Close() }()
+		in = f
+	}
+
+	if err := parseLog(in, *format, stdout); err != nil {
+		fmt.Fprintf(stderr, "vgccorner-cli: %v\n", err)
+		return exitError
+	}
+	return exitOK
+}
+
+// parseLog parses the log read from r and writes it to w as indented JSON or,
+// for the "summary" format, as the plain-text recap the API serves.
+func parseLog(r io.Reader, format string, w io.Writer) error {
+	battleLog, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("failed to read battle log: %w", err)
+	}
+	if len(battleLog) == 0 {
+		return errors.New("battle log is empty")
+	}
+
+	summary, err := analysis.ParseShowdownLog(string(battleLog))
+	if err != nil {
+		return fmt.Errorf("failed to parse battle log: %w", err)
+	}
+
+	if format == "summary" {
+		_, err = io.WriteString(w, analysis.Recap(summary))
+		return err
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(summary)
+}
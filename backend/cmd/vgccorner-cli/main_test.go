@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+)
+
+const sampleLog = "../../internal/analysis/testdata/vgc_bo3_game1.log"
+
+func TestRunParseJSON(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"parse", sampleLog}, nil, &stdout, &stderr); code != exitOK {
+		t.Fatalf("exit code = %d, want %d (stderr: %s)", code, exitOK, stderr.String())
+	}
+
+	var summary struct {
+		Winner  string `json:"winner"`
+		Player1 struct {
+			Name string `json:"name"`
+		} `json:"player1"`
+		Turns []any `json:"turns"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &summary); err != nil {
+		t.Fatalf("output is not valid JSON: %v", err)
+	}
+	if summary.Winner != "player1" || summary.Player1.Name != "Alice" {
+		t.Errorf("winner = %q (player1 %q), want player1 (Alice)", summary.Winner, summary.Player1.Name)
+	}
+	if len(summary.Turns) != 9 {
+		t.Errorf("got %d turns, want 9", len(summary.Turns))
+	}
+	if !strings.Contains(stdout.String(), "\n  \"") {
+		t.Error("expected indented JSON output")
+	}
+}
+
+func TestRunParseSummaryFromStdin(t *testing.T) {
+	log, err := os.ReadFile(sampleLog)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"parse", "--format=summary"}, bytes.NewReader(log), &stdout, &stderr)
+	if code != exitOK {
+		t.Fatalf("exit code = %d, want %d (stderr: %s)", code, exitOK, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "Winner: Alice") {
+		t.Errorf("recap missing winner:\n%s", stdout.String())
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{"no subcommand", nil, exitUsage},
+		{"unknown subcommand", []string{"analyze"}, exitUsage},
+		{"unknown format", []string{"parse", "--format=xml", sampleLog}, exitUsage},
+		{"missing file", []string{"parse", "testdata/missing.log"}, exitError},
+		{"empty stdin", []string{"parse"}, exitError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if code := run(tt.args, strings.NewReader(""), &stdout, &stderr); code != tt.want {
+				t.Errorf("exit code = %d, want %d", code, tt.want)
+			}
+			if stderr.Len() == 0 {
+				t.Error("expected a message on stderr")
+			}
+			if stdout.Len() != 0 {
+				t.Errorf("expected no output, got %q", stdout.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package analysis
+
+import (
+	"fmt"
+	"strings"
+)
+
+// moldBreakerAbilities ignore the target's ability for their holder's moves.
+var moldBreakerAbilities = map[string]bool{
+	"mold breaker": true,
+	"turboblaze":   true,
+	"teravolt":     true,
+}
+
+const neutralizingGas = "Neutralizing Gas"
+
+// abilityTracker follows abilities that suppress others, so abilities that
+// can't be active aren't recorded as activating.
+type abilityTracker struct {
+	gas          map[string]bool   // pokemonKey -> on the field with Neutralizing Gas
+	moldBreakers map[string]string // pokemonKey -> Mold Breaker, Turboblaze, or Teravolt
+}
+
+func newAbilityTracker() *abilityTracker {
+	return &abilityTracker{
+		gas:          make(map[string]bool),
+		moldBreakers: make(map[string]string),
+	}
+}
+
+// gasActive reports whether Neutralizing Gas from anyone but ref is in effect.
+func (a *abilityTracker) gasActive(ref string) bool {
+	for key := range a.gas {
+		if key != pokemonKey(ref) {
+			return true
+		}
+	}
+	return false
+}
+
+// startTurn notes every suppression effect still in place on a new turn.
+func (a *abilityTracker) startTurn(turn *Turn) {
+	for key := range a.gas {
+		noteSuppression(turn, neutralizingGas, key)
+	}
+	for key, ability := range a.moldBreakers {
+		noteSuppression(turn, ability, key)
+	}
+}
+
+// process handles the lines that change which abilities are active:
+//
+//	|-ability|p1a: Weezing|Neutralizing Gas
+//	|-end|p1a: Weezing|ability: Neutralizing Gas
+//	|switch|p1a: Kingambit|...   (whatever was in that slot is gone)
+//
+// and records weather and terrain set by an ability as that ability activating.
+func (a *abilityTracker) process(turn *Turn, parts []string) {
+	if len(parts) < 3 {
+		return
+	}
+	key := pokemonKey(parts[2])
+
+	switch parts[1] {
+	case "switch", "drag", "faint":
+		delete(a.gas, key)
+		delete(a.moldBreakers, key)
+
+	case "-end":
+		if len(parts) > 3 && strings.EqualFold(strings.TrimSpace(parts[3]), "ability: "+neutralizingGas) {
+			delete(a.gas, key)
+		}
+
+	case "-ability":
+		if len(parts) < 4 {
+			return
+		}
+		ability := strings.TrimSpace(parts[3])
+		switch {
+		case strings.EqualFold(ability, neutralizingGas):
+			a.gas[key] = true
+			noteSuppression(turn, neutralizingGas, key)
+		case moldBreakerAbilities[strings.ToLower(ability)]:
+			a.moldBreakers[key] = ability
+			noteSuppression(turn, ability, key)
+		case a.gasActive(parts[2]):
+			// Suppressed abilities can't activate; don't record one as if it had
+			return
+		}
+		recordActivation(turn, parts[2], ability)
+
+	case "-weather", "-fieldstart":
+		if ref, ability, ok := fieldAbility(parts); ok {
+			recordActivation(turn, ref, ability)
+		}
+	}
+}
+
+func recordActivation(turn *Turn, ref, ability string) {
+	if turn != nil {
+		turn.Abilities = append(turn.Abilities, AbilityActivation{
+			Player:  extractPlayerIDFromRef(ref),
+			Pokemon: extractNickname(ref),
+			Ability: ability,
+		})
+	}
+}
+
+// fieldAbility returns the Pokémon and ability behind a weather or terrain an
+// ability set, usually on switch-in:
+//
+//	|-weather|RainDance|[from] ability: Drizzle|[of] p2a: Pelipper
+//	|-fieldstart|move: Electric Terrain|[from] ability: Electric Surge|[of] p1a: Pincurchin
+func fieldAbility(parts []string) (ref, ability string, ok bool) {
+	if len(parts) < 3 {
+		return "", "", false
+	}
+	ability, ref = fromAbility(parts), ofSource(parts)
+	return ref, ability, ability != "" && ref != ""
+}
+
+// recordRevealedAbility notes the ability a Pokémon showed, from |-ability| or
+// from a weather or terrain it set. A Trace holder reveals Trace rather than
+// the ability it copied.
+func recordRevealedAbility(summary *BattleSummary, tracker *StateTracker, parts []string) {
+	var ref, ability string
+	switch {
+	case len(parts) > 3 && parts[1] == "-ability":
+		ref, ability = parts[2], strings.TrimSpace(parts[3])
+		if from := fromAbility(parts); from != "" {
+			ability = from
+		}
+	case len(parts) > 2 && (parts[1] == "-weather" || parts[1] == "-fieldstart"):
+		ref, ability, _ = fieldAbility(parts)
+	}
+	if ref == "" || ability == "" || tracker.IsTransformed(ref) {
+		return
+	}
+	summary.seat(extractRawPlayerID(ref)).RevealedAbilities[tracker.SpeciesFor(ref)] = ability
+}
+
+// noteSuppression adds "Neutralizing Gas (Weezing)" style notes, once per turn.
+func noteSuppression(turn *Turn, ability, key string) {
+	if turn == nil {
+		return
+	}
+	note := fmt.Sprintf("%s (%s)", ability, extractNickname(key))
+	if !contains(turn.SuppressedAbilities, note) {
+		turn.SuppressedAbilities = append(turn.SuppressedAbilities, note)
+	}
+}
+
+// withoutAbilitySource drops "[from] ability:" annotations from a line.
+func withoutAbilitySource(parts []string) []string {
+	kept := make([]string, 0, len(parts))
+	for i, part := range parts {
+		if i >= 2 && strings.HasPrefix(part, "[from] ability:") {
+			continue
+		}
+		kept = append(kept, part)
+	}
+	return kept
+}
@@ -0,0 +1,66 @@
+package analysis
+
+import "testing"
+
+const neutralizingGasLog = `|player|p1|Alice|1|
+|player|p2|Bob|2|
+|start
+|switch|p1a: Amoonguss|Amoonguss, L50, F|100/100
+|switch|p2a: Kingambit|Kingambit, L50, M|100/100
+|turn|1
+|switch|p1a: Weezing|Weezing-Galar, L50, M|100/100
+|-ability|p1a: Weezing|Neutralizing Gas
+|move|p2a: Kingambit|Sucker Punch|p1a: Weezing
+|-fail|p2a: Kingambit
+|upkeep
+|turn|2
+|switch|p2a: Incineroar|Incineroar, L50, M|100/100
+|-ability|p2a: Incineroar|Intimidate|boost
+|upkeep
+|turn|3
+|switch|p1a: Amoonguss|Amoonguss, L50, F|100/100
+|-end|p1a: Weezing|ability: Neutralizing Gas
+|switch|p2a: Kingambit|Kingambit, L50, M|100/100
+|-ability|p2a: Kingambit|Pressure
+|upkeep
+|turn|4
+|win|Alice
+`
+
+func TestParseShowdownLogNeutralizingGas(t *testing.T) {
+	for name, parse := range map[string]func(string) (*BattleSummary, error){
+		"basic":    ParseShowdownLog,
+		"enhanced": ParseEnhancedShowdownLog,
+	} {
+		t.Run(name, func(t *testing.T) {
+			summary, err := parse(neutralizingGasLog)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(summary.Turns) < 4 {
+				t.Fatalf("expected at least 4 turns, got %d", len(summary.Turns))
+			}
+
+			// Still in effect when turn 3 starts, gone by turn 4
+			gas := "Neutralizing Gas (Weezing)"
+			for i, want := range []bool{true, true, true, false} {
+				if got := contains(summary.Turns[i].SuppressedAbilities, gas); got != want {
+					t.Errorf("turn %d: expected gas noted %v, got %v", i+1, want, summary.Turns[i].SuppressedAbilities)
+				}
+			}
+
+			// Intimidate can't activate under Neutralizing Gas
+			for _, a := range summary.Turns[1].Abilities {
+				if a.Ability == "Intimidate" {
+					t.Errorf("expected Intimidate not to be recorded under Neutralizing Gas, got %+v", a)
+				}
+			}
+
+			// Once Weezing leaves, abilities activate again
+			abilities := summary.Turns[2].Abilities
+			if len(abilities) != 1 || abilities[0].Ability != "Pressure" || abilities[0].Player != "player2" {
+				t.Errorf("expected Pressure recorded on turn 3, got %+v", abilities)
+			}
+		})
+	}
+}
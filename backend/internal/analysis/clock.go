@@ -0,0 +1,57 @@
+package analysis
+
+import (
+	"regexp"
+	"strconv"
+)
+
+// inactiveWarning matches timer warnings such as "Alice has 30 seconds left."
+// and "Alice has 10 seconds left this turn."
+var inactiveWarning = regexp.MustCompile(`^(.+) has (\d+) seconds? left`)
+
+// recordInactive records an |inactive| timer warning as a ClockEvent and
+// lowers the player's MinTimeLeft. Other |inactive| notices (the timer being
+// turned on, reconnects) are ignored.
+func recordInactive(summary *BattleSummary, turn int, parts []string) {
+	if len(parts) < 3 {
+		return
+	}
+	match := inactiveWarning.FindStringSubmatch(parts[2])
+	if match == nil {
+		return
+	}
+	player := playerByName(summary, match[1])
+	if player == "" {
+		return
+	}
+	seconds, err := strconv.Atoi(match[2])
+	if err != nil {
+		return
+	}
+
+	summary.ClockEvents = append(summary.ClockEvents, ClockEvent{
+		Turn:        turn,
+		Player:      player,
+		SecondsLeft: seconds,
+	})
+	if least, ok := summary.Stats.MinTimeLeft[player]; !ok || seconds < least {
+		summary.Stats.MinTimeLeft[player] = seconds
+	}
+}
+
+// recordInactiveOff records the battle timer being turned off, after which
+// nobody is under clock pressure.
+func recordInactiveOff(summary *BattleSummary, turn int) {
+	summary.ClockEvents = append(summary.ClockEvents, ClockEvent{Turn: turn, TimerOff: true})
+}
+
+// playerByName returns the slot ("player1".."player4") of the seated player
+// with the given username, or "" if nobody matches.
+func playerByName(summary *BattleSummary, name string) string {
+	for _, slot := range summary.slots() {
+		if summary.PlayerBySlot(slot).Name == name {
+			return playerKey(slot)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,49 @@
+package analysis
+
+import "testing"
+
+func TestParseShowdownLogClockEvents(t *testing.T) {
+	log := `|player|p1|Alice Smith|1|
+|player|p2|Bob|2|
+|inactive|Battle timer is ON: inactive players will automatically lose when time's up. (requested by Bob)
+|start
+|switch|p1a: Pikachu|Pikachu, L50, M|100/100
+|switch|p2a: Blastoise|Blastoise, L50, M|100/100
+|turn|1
+|inactive|Alice Smith has 120 seconds left.
+|move|p1a: Pikachu|Thunderbolt|p2a: Blastoise
+|-damage|p2a: Blastoise|40/100
+|upkeep
+|turn|2
+|inactive|Alice Smith has 30 seconds left this turn.
+|inactive|Bob has 60 seconds left.
+|inactive|Alice Smith has 10 seconds left.
+|inactiveoff|Battle timer is now OFF.
+|move|p1a: Pikachu|Thunderbolt|p2a: Blastoise
+|-damage|p2a: Blastoise|0 fnt
+|faint|p2a: Blastoise
+|win|Alice Smith
+`
+	summary, err := ParseShowdownLog(log)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := summary.Stats.MinTimeLeft["player1"]; got != 10 {
+		t.Errorf("expected Alice's lowest time to be 10s, got %d", got)
+	}
+	if got := summary.Stats.MinTimeLeft["player2"]; got != 60 {
+		t.Errorf("expected Bob's lowest time to be 60s, got %d", got)
+	}
+
+	if len(summary.ClockEvents) != 5 {
+		t.Fatalf("expected 4 warnings and the timer turning off, got %+v", summary.ClockEvents)
+	}
+	first := summary.ClockEvents[0]
+	if first.Turn != 1 || first.Player != "player1" || first.SecondsLeft != 120 {
+		t.Errorf("unexpected first warning: %+v", first)
+	}
+	if last := summary.ClockEvents[4]; !last.TimerOff || last.Turn != 2 {
+		t.Errorf("expected the timer to be turned off on turn 2, got %+v", last)
+	}
+}
@@ -0,0 +1,65 @@
+package analysis
+
+import "sort"
+
+// BattleComparison summarizes how two battles differ, for reviewing games
+// side by side. A and B refer to the order the battles were passed in.
+type BattleComparison struct {
+	TurnsA        int            `json:"turnsA"`
+	TurnsB        int            `json:"turnsB"`
+	TurnDelta     int            `json:"turnDelta"` // TurnsB - TurnsA
+	WinnerA       string         `json:"winnerA"`   // "player1", "player2", or "" for draws and unfinished battles
+	WinnerB       string         `json:"winnerB"`
+	SameWinner    bool           `json:"sameWinner"`
+	SharedPokemon []string       `json:"sharedPokemon"` // Species on either team in both battles, sorted
+	DamageDealtA  map[string]int `json:"damageDealtA"`  // "player1"/"player2" -> move damage dealt, in HP percent
+	DamageDealtB  map[string]int `json:"damageDealtB"`
+}
+
+// CompareBattles diffs two parsed battles.
+func CompareBattles(a, b *BattleSummary) BattleComparison {
+	comparison := BattleComparison{
+		TurnsA:        len(a.Turns),
+		TurnsB:        len(b.Turns),
+		WinnerA:       a.Winner,
+		WinnerB:       b.Winner,
+		SharedPokemon: []string{},
+		DamageDealtA:  damageDealtByPlayer(a),
+		DamageDealtB:  damageDealtByPlayer(b),
+	}
+	comparison.TurnDelta = comparison.TurnsB - comparison.TurnsA
+	comparison.SameWinner = a.Winner != "" && a.Winner == b.Winner
+
+	inA := make(map[string]bool)
+	for _, player := range a.allPlayers() {
+		for _, poke := range player.Team {
+			inA[poke.Name] = true
+		}
+	}
+	seen := make(map[string]bool)
+	for _, player := range b.allPlayers() {
+		for _, poke := range player.Team {
+			if inA[poke.Name] && !seen[poke.Name] {
+				seen[poke.Name] = true
+				comparison.SharedPokemon = append(comparison.SharedPokemon, poke.Name)
+			}
+		}
+	}
+	sort.Strings(comparison.SharedPokemon)
+
+	return comparison
+}
+
+// damageDealtByPlayer totals the damage each side's moves dealt, from the
+// per-action impact the enhanced parser records.
+func damageDealtByPlayer(s *BattleSummary) map[string]int {
+	dealt := map[string]int{"player1": 0, "player2": 0}
+	for _, turn := range s.Turns {
+		for _, action := range turn.Actions {
+			if action.Impact != nil {
+				dealt[action.Player] += action.Impact.DamageDealt
+			}
+		}
+	}
+	return dealt
+}
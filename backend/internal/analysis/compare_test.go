@@ -0,0 +1,42 @@
+package analysis
+
+import "testing"
+
+func TestCompareBattles(t *testing.T) {
+	a := &BattleSummary{
+		Winner:  "player1",
+		Player1: Player{Team: []Pokémon{{Name: "Pikachu"}, {Name: "Incineroar"}}},
+		Player2: Player{Team: []Pokémon{{Name: "Blastoise"}}},
+		Turns: []Turn{
+			{TurnNumber: 1, Actions: []Action{{Player: "player1", Impact: &MoveImpact{DamageDealt: 40}}}},
+			{TurnNumber: 2},
+		},
+	}
+	b := &BattleSummary{
+		Winner:  "player1",
+		Player1: Player{Team: []Pokémon{{Name: "Incineroar"}, {Name: "Blastoise"}}},
+		Player2: Player{Team: []Pokémon{{Name: "Gengar"}}},
+		Turns: []Turn{
+			{TurnNumber: 1, Actions: []Action{{Player: "player2", Impact: &MoveImpact{DamageDealt: 25}}}},
+			{TurnNumber: 2},
+			{TurnNumber: 3},
+			{TurnNumber: 4},
+			{TurnNumber: 5},
+		},
+	}
+
+	diff := CompareBattles(a, b)
+
+	if diff.TurnDelta != 3 {
+		t.Errorf("expected turn delta 3, got %d", diff.TurnDelta)
+	}
+	if !diff.SameWinner {
+		t.Error("expected the same winner")
+	}
+	if len(diff.SharedPokemon) != 2 || diff.SharedPokemon[0] != "Blastoise" || diff.SharedPokemon[1] != "Incineroar" {
+		t.Errorf("expected shared [Blastoise Incineroar], got %v", diff.SharedPokemon)
+	}
+	if diff.DamageDealtA["player1"] != 40 || diff.DamageDealtB["player2"] != 25 {
+		t.Errorf("unexpected damage totals: %v / %v", diff.DamageDealtA, diff.DamageDealtB)
+	}
+}
@@ -0,0 +1,77 @@
+package analysis
+
+import "strings"
+
+// delayedMoves land two turns after they're used, at the end of the turn.
+var delayedMoves = map[string]bool{"future sight": true, "doom desire": true}
+
+// delayedDamage matches a Future Sight or Doom Desire hit to the Pokémon that
+// used it, which may have switched out or fainted since.
+type delayedDamage struct {
+	pending map[string]string // Targeted field position ("p2a") -> user ref
+	landing string            // User of the delayed move landing right now
+	move    string            // Name of the landing move
+}
+
+func newDelayedDamage() *delayedDamage {
+	return &delayedDamage{pending: make(map[string]string)}
+}
+
+// start handles |-start|USER|move: Future Sight, aimed at lastMove's target.
+// The hit lands on whoever holds that position when it resolves.
+func (d *delayedDamage) start(lastMove, parts []string) {
+	if len(parts) < 4 || !delayedMoves[residualKey(parts[3])] {
+		return
+	}
+	if len(lastMove) < 5 || pokemonKey(lastMove[2]) != pokemonKey(parts[2]) {
+		return
+	}
+	d.pending[fieldPosition(lastMove[4])] = parts[2]
+}
+
+// end handles |-end|TARGET|move: Future Sight, announcing the hit. It reports
+// whether the line was a delayed move landing.
+func (d *delayedDamage) end(parts []string) bool {
+	if len(parts) < 4 || !delayedMoves[residualKey(parts[3])] {
+		return false
+	}
+	position := fieldPosition(parts[2])
+	d.landing = d.pending[position]
+	d.move = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(parts[3]), "move:"))
+	delete(d.pending, position)
+	return true
+}
+
+// hit returns the user of the delayed move whose damage this -damage line
+// is, consuming the landing.
+func (d *delayedDamage) hit(parts []string) (user, move string, ok bool) {
+	if d.landing == "" {
+		return "", "", false
+	}
+	if _, passive := passiveDamageSource(parts); passive {
+		return "", "", false
+	}
+	user, move = d.landing, d.move
+	d.landing, d.move = "", ""
+	return user, move, true
+}
+
+// fieldPosition returns the position part of a reference, "p2a" for
+// "p2a: Garchomp".
+func fieldPosition(ref string) string {
+	if idx := strings.Index(ref, ":"); idx >= 0 {
+		return strings.TrimSpace(ref[:idx])
+	}
+	return strings.TrimSpace(ref)
+}
+
+// markDelayed tags the turn's latest residual event as a delayed move's hit.
+func markDelayed(turn *Turn, tracker *StateTracker, user, move string) {
+	if turn == nil || len(turn.Residual) == 0 {
+		return
+	}
+	event := &turn.Residual[len(turn.Residual)-1]
+	event.Delayed = true
+	event.Source = move
+	event.User = tracker.SpeciesFor(user)
+}
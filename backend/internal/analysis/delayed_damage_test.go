@@ -0,0 +1,69 @@
+package analysis
+
+import "testing"
+
+const futureSightLog = `|player|p1|Alice|1|
+|player|p2|Bob|2|
+|start
+|switch|p1a: Slowking|Slowking-Galar, L50, M|100/100
+|switch|p2a: Garchomp|Garchomp, L50, M|100/100
+|turn|1
+|move|p1a: Slowking|Future Sight|p2a: Garchomp
+|-start|p1a: Slowking|move: Future Sight
+|move|p2a: Garchomp|Swords Dance|p2a: Garchomp
+|-boost|p2a: Garchomp|atk|2
+|upkeep
+|turn|2
+|switch|p1a: Pikachu|Pikachu, L50, M|100/100
+|move|p2a: Garchomp|Protect|p2a: Garchomp
+|-singleturn|p2a: Garchomp|Protect
+|upkeep
+|turn|3
+|move|p2a: Garchomp|Earthquake|p1a: Pikachu
+|-supereffective|p1a: Pikachu
+|-damage|p1a: Pikachu|20/100
+|-end|p2a: Garchomp|move: Future Sight
+|-damage|p2a: Garchomp|0 fnt
+|faint|p2a: Garchomp
+|upkeep
+|win|Alice
+`
+
+func TestParseShowdownLogFutureSight(t *testing.T) {
+	for name, parse := range map[string]func(string) (*BattleSummary, error){
+		"basic":    ParseShowdownLog,
+		"enhanced": ParseEnhancedShowdownLog,
+	} {
+		summary, err := parse(futureSightLog)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+
+		if got := summary.Player1.KOs["Slowking-Galar"]; got != 1 {
+			t.Errorf("%s: expected the KO credited to Slowking, got %v", name, summary.Player1.KOs)
+		}
+		if got := summary.Player1.KOs["Pikachu"]; got != 0 {
+			t.Errorf("%s: expected no KO for Pikachu, got %d", name, got)
+		}
+
+		residual := summary.Turns[2].Residual
+		if len(residual) != 1 {
+			t.Fatalf("%s: expected the Future Sight hit as turn 3's residual, got %+v", name, residual)
+		}
+		hit := residual[0]
+		if !hit.Delayed || hit.User != "Slowking-Galar" || hit.Source != "Future Sight" || hit.Pokemon != "Garchomp" {
+			t.Errorf("%s: unexpected delayed hit %+v", name, hit)
+		}
+	}
+
+	summary, _ := ParseShowdownLog(futureSightLog)
+	if got := summary.Player1.IndirectDamage["Slowking-Galar"]; got != 100 {
+		t.Errorf("expected Slowking to be credited 100%% damage, got %v", got)
+	}
+
+	enhanced, _ := ParseEnhancedShowdownLog(futureSightLog)
+	earthquake := enhanced.Turns[2].Actions[0].Impact
+	if earthquake.DamageDealt != 80 || len(earthquake.Fainted) != 0 {
+		t.Errorf("expected Earthquake's impact to exclude Future Sight, got %d damage and faints %v", earthquake.DamageDealt, earthquake.Fainted)
+	}
+}
@@ -0,0 +1,68 @@
+package analysis
+
+import "fmt"
+
+// doubleSwitchSwing is how far a double switch must tip the position score
+// over the following turn to count as a won read.
+const doubleSwitchSwing = 10
+
+// firstActions records what each player did first in a turn's action phase,
+// "move" or "switch". Leads and replacements after |upkeep| aren't choices
+// and are ignored.
+type firstActions map[string]string
+
+func (f firstActions) record(boundary turnBoundary, action Action) {
+	if boundary.phase != phaseActions {
+		return
+	}
+	if _, ok := f[action.Player]; !ok {
+		f[action.Player] = action.ActionType
+	}
+}
+
+// doubleSwitch reports whether every player opened the turn by switching.
+// Switches move before any attack, so both sides switching means each read
+// the other to stay in.
+func (f firstActions) doubleSwitch() bool {
+	if len(f) < 2 {
+		return false
+	}
+	for _, actionType := range f {
+		if actionType != "switch" {
+			return false
+		}
+	}
+	return true
+}
+
+// detectDoubleSwitchReads adds a key moment for each double switch that
+// swings the position score toward one side by the following turn, naming
+// what that side brought in.
+func detectDoubleSwitchReads(summary *BattleSummary) {
+	for i := 0; i+1 < len(summary.Turns); i++ {
+		turn, next := summary.Turns[i], summary.Turns[i+1]
+		if !turn.DoubleSwitch || turn.PositionScore == nil || next.PositionScore == nil {
+			continue
+		}
+
+		p1Delta := next.PositionScore.Player1Score - turn.PositionScore.Player1Score
+		p2Delta := next.PositionScore.Player2Score - turn.PositionScore.Player2Score
+		swing := p1Delta - p2Delta
+		if absFloat(swing) < doubleSwitchSwing {
+			continue
+		}
+
+		winner := "player1"
+		if swing < 0 {
+			winner = "player2"
+		}
+
+		for _, action := range turn.Actions {
+			if action.ActionType == "switch" && action.Player == winner {
+				addKeyMoment(summary, turn.TurnNumber, "double_switch",
+					fmt.Sprintf("%s won the double switch by bringing in %s", summary.PlayerBySlot(winner).Name, action.SwitchTo), 5)
+				break
+			}
+		}
+	}
+}
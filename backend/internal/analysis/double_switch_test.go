@@ -0,0 +1,90 @@
+package analysis
+
+import (
+	"strings"
+	"testing"
+)
+
+const doubleSwitchLog = `|player|p1|Alice|1|
+|player|p2|Bob|2|
+|poke|p1|Garchomp, L50, M|
+|poke|p1|Corviknight, L50, F|
+|poke|p2|Rotom-Wash, L50|
+|poke|p2|Tyranitar, L50, M|
+|start
+|switch|p1a: Garchomp|Garchomp, L50, M|100/100
+|switch|p2a: Rotom|Rotom-Wash, L50|100/100
+|turn|1
+|switch|p1a: Corviknight|Corviknight, L50, F|100/100
+|switch|p2a: Tyranitar|Tyranitar, L50, M|100/100
+|upkeep
+|turn|2
+|move|p1a: Corviknight|Body Press|p2a: Tyranitar
+|-supereffective|p2a: Tyranitar
+|-damage|p2a: Tyranitar|30/100
+|move|p2a: Tyranitar|Rock Slide|p1a: Corviknight
+|-resisted|p1a: Corviknight
+|-damage|p1a: Corviknight|90/100
+|upkeep
+|turn|3
+|switch|p2a: Rotom|Rotom-Wash, L50|100/100
+|move|p1a: Corviknight|Body Press|p2a: Rotom
+|-damage|p2a: Rotom|70/100
+|upkeep
+|win|Alice
+`
+
+func TestParseShowdownLogDoubleSwitch(t *testing.T) {
+	for name, parse := range map[string]func(string) (*BattleSummary, error){
+		"basic":    ParseShowdownLog,
+		"enhanced": ParseEnhancedShowdownLog,
+	} {
+		t.Run(name, func(t *testing.T) {
+			summary, err := parse(doubleSwitchLog)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(summary.Turns) != 3 {
+				t.Fatalf("expected 3 turns, got %d", len(summary.Turns))
+			}
+
+			if !summary.Turns[0].DoubleSwitch {
+				t.Error("expected turn 1 to be a double switch")
+			}
+			if summary.Turns[1].DoubleSwitch {
+				t.Error("expected turn 2, with only moves, not to be a double switch")
+			}
+			if summary.Turns[2].DoubleSwitch {
+				t.Error("expected turn 3, with one switch and one move, not to be a double switch")
+			}
+
+			var read *KeyMoment
+			for i, moment := range summary.KeyMoments {
+				if moment.Type == "double_switch" {
+					read = &summary.KeyMoments[i]
+				}
+			}
+			if read == nil {
+				t.Fatalf("expected a double switch key moment, got %+v", summary.KeyMoments)
+			}
+			if read.TurnNumber != 1 || !strings.Contains(read.Description, "Alice") || !strings.Contains(read.Description, "Corviknight") {
+				t.Errorf("expected Alice's Corviknight to win the turn 1 read, got %+v", read)
+			}
+		})
+	}
+}
+
+func TestFirstActionsIgnoresReplacements(t *testing.T) {
+	firsts := firstActions{}
+	boundary := turnBoundary{}
+	boundary.startTurn()
+	firsts.record(boundary, Action{Player: "player1", ActionType: "switch"})
+
+	// player2's Pokémon fainted before acting and was replaced after upkeep
+	boundary.upkeep()
+	firsts.record(boundary, Action{Player: "player2", ActionType: "switch"})
+
+	if firsts.doubleSwitch() {
+		t.Error("expected a replacement not to count as a switch choice")
+	}
+}
@@ -0,0 +1,59 @@
+package analysis
+
+// Outcomes recorded in Stats.TypeEffectiveness.
+const (
+	effectSuper    = "super-effective"
+	effectNeutral  = "neutral"
+	effectResisted = "resisted"
+	effectImmune   = "immune"
+)
+
+// typeMatchups classifies each opposing target of the current move by how the
+// move's type landed. Showdown prints -supereffective and -resisted before the
+// -damage line they describe, so a target damaged without one was hit
+// neutrally; each target is only counted once, however many hits it takes.
+type typeMatchups struct {
+	user     string // Side of the move's user, "p1" or "p2"
+	moveType string // "" while no typed move is being resolved
+	counted  map[string]bool
+}
+
+func newTypeMatchups() *typeMatchups {
+	return &typeMatchups{counted: make(map[string]bool)}
+}
+
+// move starts a new move by ref with the given type.
+func (m *typeMatchups) move(ref, moveType string) {
+	m.user = extractRawPlayerID(ref)
+	m.moveType = moveType
+	clear(m.counted)
+}
+
+// reset stops attributing lines to the last move, e.g. at a turn boundary.
+func (m *typeMatchups) reset() {
+	m.moveType = ""
+}
+
+// record counts the outcome against target unless it was already counted for
+// this move. Hits on the user's own side (Earthquake next to an ally) are
+// deliberate and not counted.
+func (m *typeMatchups) record(summary *BattleSummary, target, outcome string) {
+	key := pokemonKey(target)
+	if m.moveType == "" || m.counted[key] || extractRawPlayerID(target) == m.user {
+		return
+	}
+	m.counted[key] = true
+
+	counts := summary.Stats.TypeEffectiveness[m.moveType]
+	switch outcome {
+	case effectSuper:
+		counts.SuperEffective++
+	case effectResisted:
+		counts.Resisted++
+	case effectImmune:
+		counts.Immune++
+	default:
+		counts.Neutral++
+	}
+	summary.Stats.TypeEffectiveness[m.moveType] = counts
+}
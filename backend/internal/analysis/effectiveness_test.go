@@ -0,0 +1,79 @@
+package analysis
+
+import "testing"
+
+const typeMatchupLog = `|player|p1|Alice|1|
+|player|p2|Bob|2|
+|gen|9
+|gametype|doubles
+|start
+|switch|p1a: Pika|Pikachu, L50, M|100/100
+|switch|p1b: Garchomp|Garchomp, L50, M|100/100
+|switch|p2a: Gyarados|Gyarados, L50, M|100/100
+|switch|p2b: Breloom|Breloom, L50, M|100/100
+|turn|1
+|move|p1a: Pika|Thunderbolt|p2a: Gyarados
+|-supereffective|p2a: Gyarados
+|-damage|p2a: Gyarados|10/100
+|move|p1b: Garchomp|Earthquake|p2b: Breloom|[spread] p1a,p2a,p2b
+|-immune|p1a: Pika
+|-immune|p2a: Gyarados
+|-resisted|p2b: Breloom
+|-damage|p2b: Breloom|80/100
+|move|p2b: Breloom|Bullet Seed|p1b: Garchomp
+|-resisted|p1b: Garchomp
+|-damage|p1b: Garchomp|95/100
+|-damage|p1b: Garchomp|90/100
+|-damage|p1b: Garchomp|85/100
+|-hitcount|p1b: Garchomp|3
+|move|p2a: Gyarados|Thunder Wave|p1b: Garchomp
+|-immune|p1b: Garchomp
+|upkeep
+|turn|2
+|move|p1b: Garchomp|Dragon Claw|p2b: Breloom
+|-damage|p2b: Breloom|40/100
+|upkeep
+|win|Alice
+`
+
+func TestParseShowdownLogTypeEffectiveness(t *testing.T) {
+	summary, err := ParseShowdownLog(typeMatchupLog)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := map[string]TypeEffectiveness{
+		// Earthquake hitting the ally Pikachu isn't counted
+		"Electric": {SuperEffective: 1},
+		"Ground":   {Resisted: 1, Immune: 1},
+		// Each Bullet Seed hit is part of one resisted move
+		"Grass":  {Resisted: 1},
+		"Dragon": {Neutral: 1},
+		// Status moves like Thunder Wave aren't in the move dataset
+	}
+	got := summary.Stats.TypeEffectiveness
+	if len(got) != len(want) {
+		t.Errorf("expected %d attacking types, got %+v", len(want), got)
+	}
+	for moveType, counts := range want {
+		if got[moveType] != counts {
+			t.Errorf("%s: expected %+v, got %+v", moveType, counts, got[moveType])
+		}
+	}
+}
+
+func TestAggregateTypeEffectiveness(t *testing.T) {
+	summary, _ := ParseShowdownLog(typeMatchupLog)
+
+	stats := AggregateTypeEffectiveness("gen9vgc", []*BattleSummary{summary, summary})
+	if stats.BattleCount != 2 {
+		t.Errorf("expected battle count 2, got %d", stats.BattleCount)
+	}
+	if ground := stats.Types["Ground"]; ground != (TypeEffectiveness{Resisted: 2, Immune: 2}) {
+		t.Errorf("expected Ground totals to double, got %+v", ground)
+	}
+
+	if empty := AggregateTypeEffectiveness("gen9vgc", nil); empty.Types == nil {
+		t.Error("expected an empty, non-nil types map")
+	}
+}
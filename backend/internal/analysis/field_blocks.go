@@ -0,0 +1,94 @@
+package analysis
+
+import "strings"
+
+// fieldBlockers are field and side effects that announce blocking a move
+// with |-activate|<target>|move: <effect>.
+var fieldBlockers = map[string]string{
+	"electric terrain": "Electric Terrain", // Sleep on grounded Pokémon
+	"misty terrain":    "Misty Terrain",    // Non-volatile status on grounded Pokémon
+	"psychic terrain":  "Psychic Terrain",  // Priority moves against grounded Pokémon
+	"safeguard":        "Safeguard",        // Status on the protected side
+}
+
+// blockSource returns what stopped a move from an -activate or -fail line,
+// e.g. "Electric Terrain" for |-activate|p2a: Rillaboom|move: Electric Terrain
+// or "Sweet Veil" for |-fail|p2a: Rillaboom|slp|[from] ability: Sweet Veil.
+// It returns "" for lines that don't describe a block.
+func blockSource(parts []string) string {
+	if len(parts) < 4 {
+		return ""
+	}
+	switch parts[1] {
+	case "-activate":
+		effect := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(parts[3]), "move:"))
+		return fieldBlockers[strings.ToLower(effect)]
+	case "-fail":
+		for _, part := range parts[3:] {
+			if strings.HasPrefix(part, "[from]") {
+				source := strings.TrimSpace(strings.TrimPrefix(part, "[from]"))
+				source = strings.TrimPrefix(source, "ability:")
+				source = strings.TrimPrefix(source, "move:")
+				return strings.TrimSpace(source)
+			}
+		}
+	}
+	return ""
+}
+
+// recordFieldBlock tags the move on lastMove with what blocked it when an
+// -activate or -fail line shows its target was shielded by terrain,
+// Safeguard, or an ability.
+func recordFieldBlock(turn *Turn, lastMove, parts []string) {
+	if turn == nil || len(lastMove) < 4 || len(parts) < 3 {
+		return
+	}
+	if pokemonKey(parts[2]) == pokemonKey(lastMove[2]) {
+		// The user's own move failing (a repeated Protect) isn't a block
+		return
+	}
+	source := blockSource(parts)
+	if source == "" {
+		return
+	}
+	blockLastMove(turn, lastMove, source)
+}
+
+// clauseBlock returns the clause named by a mid-battle enforcement message,
+// e.g. "Sleep Clause Mod" for |-message|Sleep Clause Mod activated., or "".
+// Clauses enforced at team validation (OHKO Clause) never announce anything.
+func clauseBlock(parts []string) string {
+	if len(parts) < 3 || parts[1] != "-message" {
+		return ""
+	}
+	message := strings.TrimSuffix(strings.TrimSpace(parts[2]), ".")
+	clause, ok := strings.CutSuffix(message, " activated")
+	if !ok || !strings.Contains(clause, "Clause") {
+		return ""
+	}
+	return clause
+}
+
+// recordClauseBlock tags the move on lastMove with the clause that stopped
+// it, such as Sleep Clause Mod refusing to put a second opposing Pokémon to
+// sleep, which otherwise reads as a move failing for no reason.
+func recordClauseBlock(turn *Turn, lastMove, parts []string) {
+	if turn == nil || len(lastMove) < 4 {
+		return
+	}
+	if clause := clauseBlock(parts); clause != "" {
+		blockLastMove(turn, lastMove, clause)
+	}
+}
+
+// blockLastMove sets BlockedBy on the turn's latest move by lastMove's user.
+func blockLastMove(turn *Turn, lastMove []string, source string) {
+	player := extractPlayerIDFromRef(lastMove[2])
+	for i := len(turn.Actions) - 1; i >= 0; i-- {
+		action := &turn.Actions[i]
+		if action.ActionType == "move" && action.Player == player {
+			action.BlockedBy = source
+			return
+		}
+	}
+}
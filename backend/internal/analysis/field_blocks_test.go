@@ -0,0 +1,139 @@
+package analysis
+
+import "testing"
+
+const electricTerrainLog = `|player|p1|Alice|1|
+|player|p2|Bob|2|
+|start
+|switch|p1a: Amoonguss|Amoonguss, L50, F|100/100
+|switch|p2a: Pincurchin|Pincurchin, L50, M|100/100
+|-fieldstart|move: Electric Terrain|[from] ability: Electric Surge|[of] p2a: Pincurchin
+|turn|1
+|move|p1a: Amoonguss|Spore|p2a: Pincurchin
+|-activate|p2a: Pincurchin|move: Electric Terrain
+|move|p2a: Pincurchin|Protect|p2a: Pincurchin
+|-fail|p2a: Pincurchin
+|upkeep
+|turn|2
+|win|Alice
+`
+
+func TestParseShowdownLogBlockedByTerrain(t *testing.T) {
+	for name, parse := range map[string]func(string) (*BattleSummary, error){
+		"basic":    ParseShowdownLog,
+		"enhanced": ParseEnhancedShowdownLog,
+	} {
+		t.Run(name, func(t *testing.T) {
+			summary, err := parse(electricTerrainLog)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(summary.Turns) == 0 {
+				t.Fatal("expected turns")
+			}
+
+			for _, action := range summary.Turns[0].Actions {
+				if action.Move == nil {
+					continue
+				}
+				switch action.Move.Name {
+				case "Spore":
+					if action.BlockedBy != "Electric Terrain" {
+						t.Errorf("expected Spore to be blocked by Electric Terrain, got %q", action.BlockedBy)
+					}
+				case "Protect":
+					if action.BlockedBy != "" {
+						t.Errorf("expected a failed Protect not to be a block, got %q", action.BlockedBy)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestBlockSource(t *testing.T) {
+	tests := []struct {
+		line string
+		want string
+	}{
+		{"|-activate|p2a: Rillaboom|move: Misty Terrain", "Misty Terrain"},
+		{"|-fail|p2a: Rillaboom|slp|[from] ability: Sweet Veil|[of] p2b: Ribombee", "Sweet Veil"},
+		{"|-activate|p2a: Rillaboom|move: Protect", ""},
+		{"|-fail|p2a: Rillaboom", ""},
+	}
+
+	for _, tt := range tests {
+		if got := blockSource(splitLogLine(tt.line)); got != tt.want {
+			t.Errorf("blockSource(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+}
+
+const sleepClauseLog = `|player|p1|Alice|1|
+|player|p2|Bob|2|
+|rule|Sleep Clause Mod: Limit one foe put to sleep
+|start
+|switch|p1a: Amoonguss|Amoonguss, L50, F|100/100
+|switch|p1b: Incineroar|Incineroar, L50, M|100/100
+|switch|p2a: Kingambit|Kingambit, L50, M|100/100
+|switch|p2b: Rillaboom|Rillaboom, L50, M|100/100
+|turn|1
+|move|p1a: Amoonguss|Spore|p2a: Kingambit
+|-status|p2a: Kingambit|slp
+|move|p1b: Incineroar|Fake Out|p2b: Rillaboom
+|-damage|p2b: Rillaboom|90/100
+|upkeep
+|turn|2
+|move|p1a: Amoonguss|Spore|p2b: Rillaboom
+|-message|Sleep Clause Mod activated.
+|-hint|Sleep Clause Mod prevents players from putting more than one of their opponent's Pokémon to sleep at a time
+|upkeep
+|turn|3
+|win|Alice
+`
+
+func TestParseShowdownLogBlockedBySleepClause(t *testing.T) {
+	for name, parse := range map[string]func(string) (*BattleSummary, error){
+		"basic":    ParseShowdownLog,
+		"enhanced": ParseEnhancedShowdownLog,
+	} {
+		t.Run(name, func(t *testing.T) {
+			summary, err := parse(sleepClauseLog)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(summary.Turns) < 2 {
+				t.Fatalf("expected 2 turns, got %d", len(summary.Turns))
+			}
+
+			for _, action := range summary.Turns[0].Actions {
+				if action.BlockedBy != "" {
+					t.Errorf("expected no blocks on turn 1, got %s blocked by %q", action.Move.Name, action.BlockedBy)
+				}
+			}
+			blocked := summary.Turns[1].Actions
+			if len(blocked) != 1 || blocked[0].BlockedBy != "Sleep Clause Mod" {
+				t.Errorf("expected the second Spore to be blocked by Sleep Clause Mod, got %+v", blocked)
+			}
+		})
+	}
+}
+
+func TestClauseBlock(t *testing.T) {
+	tests := []struct {
+		line string
+		want string
+	}{
+		{"|-message|Sleep Clause Mod activated.", "Sleep Clause Mod"},
+		{"|-message|Freeze Clause Mod activated.", "Freeze Clause Mod"},
+		{"|-message|Bob forfeited.", ""},
+		{"|-message|Baton Pass activated.", ""},
+		{"|-activate|p2a: Rillaboom|move: Misty Terrain", ""},
+	}
+
+	for _, tt := range tests {
+		if got := clauseBlock(splitLogLine(tt.line)); got != tt.want {
+			t.Errorf("clauseBlock(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package analysis
+
+import "strings"
+
+// fromAbility returns the ability named by a "[from] ability:" annotation, e.g.
+// "Levitate" for |-immune|p2a: Rotom|[from] ability: Levitate, or "" if the
+// line has no ability source (a type immunity, a move-set weather, ...).
+func fromAbility(parts []string) string {
+	for _, part := range parts[2:] {
+		if strings.HasPrefix(part, "[from] ability:") {
+			return strings.TrimSpace(strings.TrimPrefix(part, "[from] ability:"))
+		}
+	}
+	return ""
+}
+
+// recordImmunity counts a move blocked by an opposing Pokémon's immunity
+// against the move's user and tags that move's action.
+func recordImmunity(summary *BattleSummary, turn *Turn, lastMove, parts []string) {
+	if len(lastMove) < 4 || len(parts) < 3 {
+		return
+	}
+	if extractRawPlayerID(lastMove[2]) == extractRawPlayerID(parts[2]) {
+		// Hitting your own ally (Earthquake next to a Flying type) is deliberate
+		return
+	}
+
+	player := extractPlayerIDFromRef(lastMove[2])
+	summary.Stats.ImmuneCount[player]++
+	ability := fromAbility(parts)
+	if ability != "" {
+		summary.Stats.AbilityImmuneCount[player]++
+	}
+
+	if turn == nil {
+		return
+	}
+	for i := len(turn.Actions) - 1; i >= 0; i-- {
+		action := &turn.Actions[i]
+		if action.ActionType == "move" && action.Player == player {
+			action.Immune = true
+			if ability != "" {
+				action.Details = "blocked by " + ability
+			}
+			return
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package analysis
+
+import "testing"
+
+func TestParseShowdownLogImmunities(t *testing.T) {
+	log := `|player|p1|Alice|1|
+|player|p2|Bob|2|
+|gametype|doubles
+|start
+|switch|p1a: Garchomp|Garchomp, L50, M|100/100
+|switch|p1b: Talonflame|Talonflame, L50, M|100/100
+|switch|p2a: Rotom|Rotom-Wash, L50|100/100
+|switch|p2b: Corviknight|Corviknight, L50, M|100/100
+|turn|1
+|move|p1a: Garchomp|Earthquake|p2a: Rotom|[spread] p1b,p2a,p2b
+|-immune|p1b: Talonflame
+|-immune|p2a: Rotom|[from] ability: Levitate
+|-immune|p2b: Corviknight
+|upkeep
+|turn|2
+|win|Alice
+`
+	for name, parse := range map[string]func(string) (*BattleSummary, error){
+		"basic":    ParseShowdownLog,
+		"enhanced": ParseEnhancedShowdownLog,
+	} {
+		summary, err := parse(log)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+
+		if got := summary.Stats.ImmuneCount["player1"]; got != 2 {
+			t.Errorf("%s: expected 2 immunities against player1 (ally hit excluded), got %d", name, got)
+		}
+		if got := summary.Stats.AbilityImmuneCount["player1"]; got != 1 {
+			t.Errorf("%s: expected 1 ability immunity (Levitate), got %d", name, got)
+		}
+		if !summary.Turns[0].Actions[0].Immune {
+			t.Errorf("%s: expected the Earthquake action to be tagged immune", name)
+		}
+	}
+}
+
+func TestImmunityAbility(t *testing.T) {
+	if got := fromAbility([]string{"", "-immune", "p2a: Rotom", "[from] ability: Levitate"}); got != "Levitate" {
+		t.Errorf("expected Levitate, got %q", got)
+	}
+	if got := fromAbility([]string{"", "-immune", "p2a: Corviknight"}); got != "" {
+		t.Errorf("expected type immunity, got %q", got)
+	}
+}
@@ -0,0 +1,65 @@
+package analysis
+
+import "strings"
+
+// itemRemovalMoves are moves that take an opponent's item away, as they
+// appear in an -enditem line's "[from] move:" annotation.
+var itemRemovalMoves = map[string]bool{
+	"knock off":     true,
+	"thief":         true,
+	"covet":         true,
+	"incinerate":    true,
+	"corrosive gas": true,
+	"bug bite":      true,
+	"pluck":         true,
+}
+
+// recordItemRemoval records an item forcibly removed by an opposing move:
+//
+//	|-enditem|p2a: Amoonguss|Leftovers|[from] move: Knock Off|[of] p1a: Kingambit
+//	|-enditem|p2a: Amoonguss|Sitrus Berry|[from] stealeat|[move] Bug Bite|[of] p1a: Kingambit
+//
+// Consumed berries, popped Focus Sashes and other plain -enditem lines have no
+// removing move and are ignored.
+func recordItemRemoval(summary *BattleSummary, turn int, parts []string) {
+	if len(parts) < 5 {
+		return
+	}
+	move := itemRemovalMove(parts[4:])
+	of := ofSource(parts)
+	if move == "" || of == "" || extractRawPlayerID(of) == extractRawPlayerID(parts[2]) {
+		return
+	}
+
+	removal := ItemRemoved{
+		Turn:     turn,
+		Player:   extractPlayerIDFromRef(parts[2]),
+		Pokemon:  extractNickname(parts[2]),
+		Item:     strings.TrimSpace(parts[3]),
+		Move:     move,
+		By:       extractNickname(of),
+		ByPlayer: extractPlayerIDFromRef(of),
+	}
+	summary.ItemsRemoved = append(summary.ItemsRemoved, removal)
+	summary.Stats.ItemsKnockedOff[removal.ByPlayer]++
+}
+
+// itemRemovalMove returns the removing move named by "[from] move: X" or, for
+// Bug Bite and Pluck, "[move] X"; "" if neither names a removal move.
+func itemRemovalMove(annotations []string) string {
+	for _, part := range annotations {
+		var move string
+		switch {
+		case strings.HasPrefix(part, "[from] move:"):
+			move = strings.TrimSpace(strings.TrimPrefix(part, "[from] move:"))
+		case strings.HasPrefix(part, "[move]"):
+			move = strings.TrimSpace(strings.TrimPrefix(part, "[move]"))
+		default:
+			continue
+		}
+		if itemRemovalMoves[strings.ToLower(move)] {
+			return move
+		}
+	}
+	return ""
+}
@@ -0,0 +1,69 @@
+package analysis
+
+import "testing"
+
+const knockOffLog = `|player|p1|Alice|1|
+|player|p2|Bob|2|
+|start
+|switch|p1a: Kingambit|Kingambit, L50, M|100/100
+|switch|p2a: Amoonguss|Amoonguss, L50, F|100/100
+|turn|1
+|move|p1a: Kingambit|Knock Off|p2a: Amoonguss
+|-damage|p2a: Amoonguss|60/100
+|-enditem|p2a: Amoonguss|Rocky Helmet|[from] move: Knock Off|[of] p1a: Kingambit
+|move|p2a: Amoonguss|Spore|p1a: Kingambit
+|-status|p1a: Kingambit|slp
+|-enditem|p1a: Kingambit|Lum Berry|[eat]
+|upkeep
+|turn|2
+|win|Alice
+`
+
+func TestParseShowdownLogKnockOff(t *testing.T) {
+	summary, err := ParseShowdownLog(knockOffLog)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := summary.Stats.ItemsKnockedOff["player1"]; got != 1 {
+		t.Errorf("expected player1 to have knocked off 1 item, got %d", got)
+	}
+	if got := summary.Stats.ItemsKnockedOff["player2"]; got != 0 {
+		t.Errorf("expected a consumed berry not to count, got %d", got)
+	}
+
+	if len(summary.ItemsRemoved) != 1 {
+		t.Fatalf("expected 1 removed item, got %d", len(summary.ItemsRemoved))
+	}
+	want := ItemRemoved{
+		Turn:     1,
+		Player:   "player2",
+		Pokemon:  "Amoonguss",
+		Item:     "Rocky Helmet",
+		Move:     "Knock Off",
+		By:       "Kingambit",
+		ByPlayer: "player1",
+	}
+	if summary.ItemsRemoved[0] != want {
+		t.Errorf("expected %+v, got %+v", want, summary.ItemsRemoved[0])
+	}
+}
+
+func TestItemRemovalMove(t *testing.T) {
+	tests := []struct {
+		line string
+		want string
+	}{
+		{"|-enditem|p2a: X|Leftovers|[silent]|[from] move: Thief|[of] p1a: Y", "Thief"},
+		{"|-enditem|p2a: X|Sitrus Berry|[from] stealeat|[move] Bug Bite|[of] p1a: Y", "Bug Bite"},
+		{"|-enditem|p2a: X|Focus Sash", ""},
+		{"|-enditem|p2a: X|Air Balloon|[from] move: Tackle", ""},
+	}
+
+	for _, tt := range tests {
+		parts := splitLogLine(tt.line)
+		if got := itemRemovalMove(parts[4:]); got != tt.want {
+			t.Errorf("itemRemovalMove(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+}
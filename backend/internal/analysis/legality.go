@@ -0,0 +1,34 @@
+package analysis
+
+import "fmt"
+
+// CheckLegality returns every way the teams break the format's rules: a
+// species shown twice under Species Clause, or more restricted Pokémon than
+// the format allows. Only Pokémon shown at team preview are checked.
+func CheckLegality(summary *BattleSummary) []string {
+	violations := []string{}
+	if summary == nil {
+		return violations
+	}
+
+	speciesClause := summary.HasClause("Species Clause")
+	allowed, hasAllowance := RestrictedAllowance(summary.Format)
+	restricted := RestrictedCount(summary)
+
+	for _, slot := range summary.slots() {
+		player := playerKey(slot)
+		if speciesClause {
+			seen := make(map[string]bool)
+			for _, poke := range summary.PlayerBySlot(slot).Team {
+				if seen[poke.Name] {
+					violations = append(violations, fmt.Sprintf("%s brought more than one %s (Species Clause)", player, poke.Name))
+				}
+				seen[poke.Name] = true
+			}
+		}
+		if hasAllowance && restricted[player] > allowed {
+			violations = append(violations, fmt.Sprintf("%s brought %d restricted Pokémon but %s allows %d", player, restricted[player], summary.Format, allowed))
+		}
+	}
+	return violations
+}
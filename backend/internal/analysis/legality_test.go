@@ -0,0 +1,44 @@
+package analysis
+
+import "testing"
+
+func TestCheckLegality(t *testing.T) {
+	summary, err := ParseShowdownLog(sampleBattleLog())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if violations := CheckLegality(summary); len(violations) != 0 {
+		t.Errorf("expected a clean log to have no violations, got %v", violations)
+	}
+
+	log := `|player|p1|Alice|1|
+|player|p2|Bob|2|
+|tier|[Gen 9] VGC 2025 Reg H (Bo3)
+|rule|Species Clause: Limit one of each Pokémon
+|poke|p1|Calyrex-Shadow, L50|
+|poke|p1|Incineroar, L50, M|
+|poke|p1|Incineroar, L50, F|
+|poke|p2|Amoonguss, L50, F|
+|start
+|turn|1
+|win|Alice
+`
+	summary, err = ParseShowdownLog(log)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	violations := CheckLegality(summary)
+	expected := []string{
+		"player1 brought more than one Incineroar (Species Clause)",
+		"player1 brought 1 restricted Pokémon but [Gen 9] VGC 2025 Reg H (Bo3) allows 0",
+	}
+	if len(violations) != len(expected) {
+		t.Fatalf("expected %d violations, got %v", len(expected), violations)
+	}
+	for i, want := range expected {
+		if violations[i] != want {
+			t.Errorf("violation %d: expected %q, got %q", i, want, violations[i])
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package analysis
+
+import "strings"
+
+// opaqueFieldCounts lists protocol commands whose trailing payload is free-form
+// text or HTML, mapped to the number of fields to split into. Pipes inside the
+// payload must never be read as field separators.
+var opaqueFieldCounts = map[string]int{
+	"html":        3, // |html|<payload>
+	"raw":         3, // |raw|<payload>
+	"uhtml":       4, // |uhtml|<name>|<payload>
+	"uhtmlchange": 4, // |uhtmlchange|<name>|<payload>
+}
+
+// splitLogLines splits a raw log into protocol lines. HTML payloads that span
+// several physical lines are folded back into the |html| or |raw| line they
+// started on, so embedded newlines can't start spurious protocol lines.
+func splitLogLines(logContent string) []string {
+	rawLines := strings.Split(logContent, "\n")
+	lines := make([]string, 0, len(rawLines))
+
+	for i := 0; i < len(rawLines); i++ {
+		line := rawLines[i]
+		if isOpaqueLine(line) {
+			for i+1 < len(rawLines) && continuesPayload(line, rawLines[i+1]) {
+				i++
+				line += "\n" + rawLines[i]
+			}
+		}
+		lines = append(lines, line)
+	}
+
+	return lines
+}
+
+// splitLogLine splits a protocol line into its "|"-separated fields, keeping
+// opaque payloads as a single field.
+func splitLogLine(line string) []string {
+	return splitLogLineInto(nil, line)
+}
+
+// splitLogFields splits every protocol line of lines at once, carving the
+// fields out of one shared backing array instead of allocating per line.
+// Entries for non-protocol lines are nil.
+func splitLogFields(lines []string) [][]string {
+	total := 0
+	for _, line := range lines {
+		if strings.HasPrefix(line, "|") {
+			total += strings.Count(line, "|") + 1
+		}
+	}
+
+	fields := make([][]string, len(lines))
+	buf := make([]string, 0, total)
+	for i, line := range lines {
+		if !strings.HasPrefix(line, "|") {
+			continue
+		}
+		start := len(buf)
+		buf = splitLogLineInto(buf, line)
+		// Cap each line at its own fields so an append can't spill into the next
+		fields[i] = buf[start:len(buf):len(buf)]
+	}
+	return fields
+}
+
+// splitLogLineInto appends the fields of line to dst.
+func splitLogLineInto(dst []string, line string) []string {
+	n := -1
+	if limit, ok := opaqueFieldCounts[logCommand(line)]; ok {
+		n = limit
+	}
+	if dst == nil {
+		return strings.SplitN(line, "|", n)
+	}
+	for n != 1 {
+		field, rest, found := strings.Cut(line, "|")
+		if !found {
+			break
+		}
+		dst = append(dst, field)
+		line = rest
+		n--
+	}
+	return append(dst, line)
+}
+
+// logCommand returns the command of a protocol line, the text between its
+// first two pipes: "move" for |move|p1a: Pikachu|Thunderbolt.
+func logCommand(line string) string {
+	rest, ok := strings.CutPrefix(line, "|")
+	if !ok {
+		return ""
+	}
+	command, _, _ := strings.Cut(rest, "|")
+	return command
+}
+
+// countTurns returns how many |turn| lines there are, for pre-sizing.
+func countTurns(lines []string) int {
+	turns := 0
+	for _, line := range lines {
+		if strings.HasPrefix(line, "|turn|") {
+			turns++
+		}
+	}
+	return turns
+}
+
+func isOpaqueLine(line string) bool {
+	_, ok := opaqueFieldCounts[logCommand(line)]
+	return ok
+}
+
+// continuesPayload reports whether next is a continuation of an opaque line's
+// payload: either it isn't a protocol line at all, or the payload so far ends
+// inside an unterminated tag or quoted attribute.
+func continuesPayload(payload, next string) bool {
+	if next == "" {
+		return false
+	}
+	if !strings.HasPrefix(next, "|") {
+		return true
+	}
+	return strings.LastIndex(payload, "<") > strings.LastIndex(payload, ">")
+}
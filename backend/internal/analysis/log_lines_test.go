@@ -0,0 +1,98 @@
+package analysis
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseShowdownLogHTMLWithPipes(t *testing.T) {
+	baseline, _ := ParseShowdownLog(sampleBattleLog())
+
+	htmlLines := `|html|<div class="broadcast">|turn|99| |move|p1a: Pikachu|Tackle|p2a: Blastoise</div>
+|raw|<table><tr><td title="a
+|turn|100
+|switch|p1a: Charizard|Charizard, L50, M|100/100">x</td></tr>
+</table>
+|uhtml|bestof|<h2>Game 1 |turn|101</h2>
+`
+	log := strings.Replace(sampleBattleLog(), "|start\n", "|start\n"+htmlLines, 1)
+
+	summary, err := ParseShowdownLog(log)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(summary.Turns) != len(baseline.Turns) {
+		t.Errorf("expected %d turns, got %d", len(baseline.Turns), len(summary.Turns))
+	}
+	for _, turn := range summary.Turns {
+		if turn.TurnNumber >= 99 {
+			t.Errorf("unexpected turn %d parsed from HTML payload", turn.TurnNumber)
+		}
+	}
+	if summary.Stats.Switch != baseline.Stats.Switch {
+		t.Errorf("expected %d switches, got %d", baseline.Stats.Switch, summary.Stats.Switch)
+	}
+	if len(summary.Player1.Leads) != 1 || summary.Player1.Leads[0] != "Pikachu" {
+		t.Errorf("expected Pikachu as the only lead, got %v", summary.Player1.Leads)
+	}
+
+	enhanced, _ := ParseEnhancedShowdownLog(log)
+	if len(enhanced.Turns) != len(baseline.Turns) {
+		t.Errorf("enhanced: expected %d turns, got %d", len(baseline.Turns), len(enhanced.Turns))
+	}
+}
+
+func TestSplitLogLine(t *testing.T) {
+	tests := []struct {
+		line     string
+		expected []string
+	}{
+		{"|move|p1a: Pikachu|Thunderbolt|p2a: Blastoise", []string{"", "move", "p1a: Pikachu", "Thunderbolt", "p2a: Blastoise"}},
+		{"|html|<b>a|b</b>", []string{"", "html", "<b>a|b</b>"}},
+		{"|uhtml|bestof|<h2>a|b</h2>", []string{"", "uhtml", "bestof", "<h2>a|b</h2>"}},
+		{"|", []string{"", ""}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			parts := splitLogLine(tt.line)
+			if len(parts) != len(tt.expected) {
+				t.Fatalf("expected %v, got %v", tt.expected, parts)
+			}
+			for i := range parts {
+				if parts[i] != tt.expected[i] {
+					t.Errorf("field %d: expected %q, got %q", i, tt.expected[i], parts[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSplitLogFieldsMatchesSplitLogLine(t *testing.T) {
+	lines := []string{
+		"|move|p1a: Pikachu|Thunderbolt|p2a: Blastoise",
+		"|html|<b>a|b</b>",
+		"not a protocol line",
+		"|uhtml|bestof|<h2>a|b</h2>",
+		"|",
+		"",
+	}
+
+	fields := splitLogFields(lines)
+	for i, line := range lines {
+		if !strings.HasPrefix(line, "|") {
+			if fields[i] != nil {
+				t.Errorf("line %d: expected no fields, got %v", i, fields[i])
+			}
+			continue
+		}
+		want := splitLogLine(line)
+		if strings.Join(fields[i], "|") != strings.Join(want, "|") || len(fields[i]) != len(want) {
+			t.Errorf("line %d: expected %v, got %v", i, want, fields[i])
+		}
+		if cap(fields[i]) != len(fields[i]) {
+			t.Errorf("line %d: expected fields capped at their length, got cap %d", i, cap(fields[i]))
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package analysis
+
+import "strings"
+
+// recordLostTurn records a Pokémon that couldn't act. The reasons that come
+// down to luck are kept as logged: "flinch", "par" (full paralysis), "slp" and
+// "frz". Others (recharging, Truant, Taunt, Disable, no PP, ...) become the
+// lowercased effect name, e.g. "recharge", "truant", "taunt":
+//
+//	|cant|p1a: Pikachu|par
+//	|cant|p2a: Incineroar|flinch
+//	|cant|p1a: Amoonguss|move: Taunt|Spore
+//
+// The optional last part is the move it was prevented from using.
+func recordLostTurn(summary *BattleSummary, tracker *StateTracker, turn int, parts []string) {
+	if len(parts) < 4 {
+		return
+	}
+
+	lost := LostTurn{
+		Turn:    turn,
+		Player:  extractPlayerIDFromRef(parts[2]),
+		Pokemon: extractNickname(parts[2]),
+		Species: tracker.SpeciesFor(parts[2]),
+		Reason:  lostTurnReason(parts[3]),
+	}
+	if len(parts) > 4 {
+		lost.Move = strings.TrimSpace(parts[4])
+	}
+	summary.LostTurns = append(summary.LostTurns, lost)
+	summary.Stats.LostTurns[lost.Player]++
+}
+
+// lostTurnReason normalizes a |cant| reason: "par", "slp", "frz" and "flinch"
+// pass through, and effects such as "move: Taunt" or "ability: Truant" become
+// "taunt" and "truant".
+func lostTurnReason(reason string) string {
+	reason = strings.TrimSpace(reason)
+	if _, effect, ok := strings.Cut(reason, ":"); ok {
+		reason = effect
+	}
+	return strings.ToLower(strings.TrimSpace(reason))
+}
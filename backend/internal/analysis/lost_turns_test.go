@@ -0,0 +1,70 @@
+package analysis
+
+import "testing"
+
+func TestParseShowdownLogLostTurns(t *testing.T) {
+	summary, err := ParseShowdownLog(`|player|p1|Alice|1|
+|player|p2|Bob|2|
+|gametype|doubles
+|start
+|switch|p1a: Sparky|Pikachu, L50, M|100/100
+|switch|p1b: Incineroar|Incineroar, L50, M|100/100
+|switch|p2a: Amoonguss|Amoonguss, L50, F|100/100
+|switch|p2b: Kingambit|Kingambit, L50, M|100/100
+|turn|1
+|move|p1b: Incineroar|Fake Out|p2b: Kingambit
+|-damage|p2b: Kingambit|95/100
+|cant|p2b: Kingambit|flinch
+|move|p2a: Amoonguss|Spore|p1a: Sparky
+|-status|p1a: Sparky|slp
+|upkeep
+|turn|2
+|cant|p1a: Sparky|slp
+|-status|p1b: Incineroar|par
+|cant|p1b: Incineroar|par
+|cant|p2a: Amoonguss|move: Taunt|Spore
+|upkeep
+|turn|3
+|win|Bob
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []LostTurn{
+		{Turn: 1, Player: "player2", Pokemon: "Kingambit", Species: "Kingambit", Reason: "flinch"},
+		{Turn: 2, Player: "player1", Pokemon: "Sparky", Species: "Pikachu", Reason: "slp"},
+		{Turn: 2, Player: "player1", Pokemon: "Incineroar", Species: "Incineroar", Reason: "par"},
+		{Turn: 2, Player: "player2", Pokemon: "Amoonguss", Species: "Amoonguss", Reason: "taunt", Move: "Spore"},
+	}
+	if len(summary.LostTurns) != len(want) {
+		t.Fatalf("expected %d lost turns, got %+v", len(want), summary.LostTurns)
+	}
+	for i, w := range want {
+		if summary.LostTurns[i] != w {
+			t.Errorf("lost turn %d: expected %+v, got %+v", i, w, summary.LostTurns[i])
+		}
+	}
+
+	if summary.Stats.LostTurns["player1"] != 2 || summary.Stats.LostTurns["player2"] != 2 {
+		t.Errorf("expected 2 lost turns per player, got %v", summary.Stats.LostTurns)
+	}
+}
+
+func TestLostTurnReason(t *testing.T) {
+	tests := map[string]string{
+		"par":             "par",
+		"slp":             "slp",
+		"frz":             "frz",
+		"flinch":          "flinch",
+		"recharge":        "recharge",
+		"ability: Truant": "truant",
+		"move: Imprison":  "imprison",
+		" Disable ":       "disable",
+	}
+	for reason, want := range tests {
+		if got := lostTurnReason(reason); got != want {
+			t.Errorf("lostTurnReason(%q): expected %q, got %q", reason, want, got)
+		}
+	}
+}
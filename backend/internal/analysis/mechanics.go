@@ -0,0 +1,44 @@
+package analysis
+
+// generationRange is the inclusive span of generations a mechanic exists in.
+type generationRange struct {
+	first, last int
+}
+
+// mechanicGenerations lists generation-specific battle mechanics.
+var mechanicGenerations = map[string]generationRange{
+	"mega":         {6, 7},
+	"zmove":        {7, 7},
+	"dynamax":      {8, 8},
+	"terastallize": {9, 9},
+}
+
+// parsedMechanics are the mechanics the parser tracks.
+var parsedMechanics = map[string]bool{
+	"terastallize": true,
+}
+
+// fullMechanicSupport reports whether the parser tracks every mechanic that
+// exists in the given generation.
+func fullMechanicSupport(generation int) bool {
+	for mechanic, span := range mechanicGenerations {
+		if generation >= span.first && generation <= span.last && !parsedMechanics[mechanic] {
+			return false
+		}
+	}
+	return true
+}
+
+// mechanicAvailable reports whether a mechanic's protocol lines should be
+// expected in a battle of the given generation. An unknown generation (0)
+// accepts every mechanic rather than dropping data.
+func mechanicAvailable(generation int, mechanic string) bool {
+	if generation == 0 {
+		return true
+	}
+	span, ok := mechanicGenerations[mechanic]
+	if !ok {
+		return true
+	}
+	return generation >= span.first && generation <= span.last
+}
@@ -0,0 +1,128 @@
+package analysis
+
+import "sort"
+
+// MetaStats aggregates usage across many battles of a format.
+type MetaStats struct {
+	Format      string       `json:"format"`
+	BattleCount int          `json:"battleCount"`
+	TopPokemon  []UsageCount `json:"topPokemon"` // Team preview appearances
+	TopMoves    []UsageCount `json:"topMoves"`   // Revealed moves, once per Pokémon per battle
+	Leads       []UsageCount `json:"leads"`      // Pokémon sent out before turn 1
+}
+
+// UsageCount is a name with the number of times it was seen.
+type UsageCount struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// AggregateMeta builds meta statistics from parsed battles, keeping the top
+// entries of each list. Lists are empty (never nil) when nothing matched.
+func AggregateMeta(format string, summaries []*BattleSummary, top int) MetaStats {
+	pokemon := make(map[string]int)
+	moves := make(map[string]int)
+	leads := make(map[string]int)
+
+	for _, summary := range summaries {
+		if summary == nil {
+			continue
+		}
+		for _, player := range summary.allPlayers() {
+			for _, poke := range player.Team {
+				pokemon[poke.Name]++
+			}
+			for _, revealed := range player.RevealedMoves {
+				for _, move := range revealed {
+					moves[move]++
+				}
+			}
+			for _, lead := range player.Leads {
+				leads[lead]++
+			}
+		}
+	}
+
+	return MetaStats{
+		Format:      format,
+		BattleCount: len(summaries),
+		TopPokemon:  topUsage(pokemon, top),
+		TopMoves:    topUsage(moves, top),
+		Leads:       topUsage(leads, top),
+	}
+}
+
+// TypeMatchupStats aggregates how each attacking type landed across many
+// battles of a format, for a type-effectiveness heatmap.
+type TypeMatchupStats struct {
+	Format      string                       `json:"format"`
+	BattleCount int                          `json:"battleCount"`
+	Types       map[string]TypeEffectiveness `json:"types"` // Attacking type -> hits by outcome
+}
+
+// AggregateTypeEffectiveness sums Stats.TypeEffectiveness over parsed battles.
+// Types is empty (never nil) when nothing matched.
+func AggregateTypeEffectiveness(format string, summaries []*BattleSummary) TypeMatchupStats {
+	types := make(map[string]TypeEffectiveness)
+	for _, summary := range summaries {
+		if summary == nil {
+			continue
+		}
+		for moveType, counts := range summary.Stats.TypeEffectiveness {
+			total := types[moveType]
+			total.SuperEffective += counts.SuperEffective
+			total.Neutral += counts.Neutral
+			total.Resisted += counts.Resisted
+			total.Immune += counts.Immune
+			types[moveType] = total
+		}
+	}
+
+	return TypeMatchupStats{
+		Format:      format,
+		BattleCount: len(summaries),
+		Types:       types,
+	}
+}
+
+// topUsage sorts counts descending (ties by name) and keeps at most top entries.
+func topUsage(counts map[string]int, top int) []UsageCount {
+	usage := make([]UsageCount, 0, len(counts))
+	for name, count := range counts {
+		usage = append(usage, UsageCount{Name: name, Count: count})
+	}
+
+	sort.Slice(usage, func(i, j int) bool {
+		if usage[i].Count != usage[j].Count {
+			return usage[i].Count > usage[j].Count
+		}
+		return usage[i].Name < usage[j].Name
+	})
+
+	if top > 0 && len(usage) > top {
+		usage = usage[:top]
+	}
+	return usage
+}
+
+// OtherCount is the TopCounts key that sums every entry past the top ones.
+const OtherCount = "other"
+
+// TopCounts keeps the top entries of counts by count (ties by name) and sums
+// the rest under OtherCount. counts is not modified; with top <= 0 or no
+// more than top entries it is copied as is.
+func TopCounts(counts map[string]int, top int) map[string]int {
+	usage := topUsage(counts, 0)
+	if top <= 0 || len(usage) <= top {
+		top = len(usage)
+	}
+
+	trimmed := make(map[string]int, top+1)
+	for _, entry := range usage[:top] {
+		trimmed[entry.Name] = entry.Count
+	}
+	for _, entry := range usage[top:] {
+		trimmed[OtherCount] += entry.Count
+	}
+	return trimmed
+}
@@ -0,0 +1,55 @@
+package analysis
+
+import "testing"
+
+func TestAggregateMetaOrdering(t *testing.T) {
+	summary, _ := ParseShowdownLog(sampleBattleLog())
+
+	meta := AggregateMeta("VGC", []*BattleSummary{summary, summary}, 2)
+
+	if meta.BattleCount != 2 {
+		t.Errorf("expected 2 battles, got %d", meta.BattleCount)
+	}
+	if len(meta.TopMoves) != 2 {
+		t.Fatalf("expected top moves capped at 2, got %d", len(meta.TopMoves))
+	}
+	if meta.TopMoves[0].Count < meta.TopMoves[1].Count {
+		t.Errorf("expected moves sorted by count, got %v", meta.TopMoves)
+	}
+	if len(meta.Leads) != 2 || meta.Leads[0].Count != 2 {
+		t.Errorf("expected each lead counted twice, got %v", meta.Leads)
+	}
+}
+
+func TestAggregateMetaEmpty(t *testing.T) {
+	meta := AggregateMeta("VGC", nil, 10)
+
+	if meta.TopPokemon == nil || meta.TopMoves == nil || meta.Leads == nil {
+		t.Error("expected empty, non-nil usage lists")
+	}
+}
+
+func TestTopCounts(t *testing.T) {
+	counts := map[string]int{
+		"protect": 6, "fakeout": 4, "tailwind": 4, "earthquake": 2, "icywind": 1, "thunderbolt": 1,
+	}
+
+	top := TopCounts(counts, 3)
+
+	want := map[string]int{"protect": 6, "fakeout": 4, "tailwind": 4, OtherCount: 4}
+	if len(top) != len(want) {
+		t.Fatalf("expected %v, got %v", want, top)
+	}
+	for name, count := range want {
+		if top[name] != count {
+			t.Errorf("expected %s = %d, got %d", name, count, top[name])
+		}
+	}
+	if len(counts) != 6 {
+		t.Error("expected the full map to be left intact")
+	}
+
+	if all := TopCounts(counts, 10); len(all) != 6 || all[OtherCount] != 0 {
+		t.Errorf("expected no other bucket when everything fits, got %v", all)
+	}
+}
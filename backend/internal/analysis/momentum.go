@@ -0,0 +1,23 @@
+package analysis
+
+// MomentumPoint is one turn on a momentum chart. Score is Player1's position
+// score minus Player2's: positive favors player1, negative favors player2.
+type MomentumPoint struct {
+	Turn  int     `json:"turn"`
+	Score float64 `json:"score"`
+}
+
+// MomentumTimeline returns the position-score swing after each turn, for
+// charting. Turns without a position score chart as even. The result is
+// empty, never nil, for battles with no turns.
+func (s *BattleSummary) MomentumTimeline() []MomentumPoint {
+	timeline := make([]MomentumPoint, 0, len(s.Turns))
+	for _, turn := range s.Turns {
+		point := MomentumPoint{Turn: turn.TurnNumber}
+		if turn.PositionScore != nil {
+			point.Score = turn.PositionScore.Player1Score - turn.PositionScore.Player2Score
+		}
+		timeline = append(timeline, point)
+	}
+	return timeline
+}
@@ -0,0 +1,19 @@
+package analysis
+
+import "testing"
+
+func TestMomentumTimelineEmpty(t *testing.T) {
+	timeline := (&BattleSummary{}).MomentumTimeline()
+	if timeline == nil || len(timeline) != 0 {
+		t.Errorf("expected an empty, non-nil timeline, got %v", timeline)
+	}
+
+	summary := &BattleSummary{Turns: []Turn{
+		{TurnNumber: 1, PositionScore: &PositionScore{Player1Score: 70, Player2Score: 40}},
+		{TurnNumber: 2},
+	}}
+	timeline = summary.MomentumTimeline()
+	if len(timeline) != 2 || timeline[0].Score != 30 || timeline[1].Score != 0 {
+		t.Errorf("expected scores [30 0], got %v", timeline)
+	}
+}
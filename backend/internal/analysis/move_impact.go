@@ -8,6 +8,7 @@ func EnhanceActionWithImpact(action *Action, moveName string, events []string) {
 		action.Impact = &MoveImpact{
 			Fainted:     []string{},
 			StatChanges: []StatChange{},
+			Targets:     []TargetHit{},
 		}
 	}
 
@@ -31,12 +32,16 @@ func EnhanceActionWithImpact(action *Action, moveName string, events []string) {
 		action.Impact.SpeedControl = "paralysis"
 	}
 
+	// Each hit of a multi-hit move starts from the HP the previous one left
+	hpLeft := make(map[string]int)
+	hits := hitCounter{}
+
 	// Parse events to extract impact
 	for _, event := range events {
 		if !strings.HasPrefix(event, "|") {
 			continue
 		}
-		parts := strings.Split(event, "|")
+		parts := splitLogLine(event)
 		if len(parts) < 2 {
 			continue
 		}
@@ -45,14 +50,25 @@ func EnhanceActionWithImpact(action *Action, moveName string, events []string) {
 
 		switch eventType {
 		case "-damage":
-			// Damage dealt - parse HP change
-			if len(parts) >= 4 {
+			// Damage dealt - parse HP change. [from] damage (weather,
+			// status, hazards, items) isn't dealt by this move.
+			if _, passive := passiveDamageSource(parts); len(parts) >= 4 && !passive {
 				hpBefore, hpAfter := parseHPChange(parts)
+				key := pokemonKey(parts[2])
+				if hp, ok := hpLeft[key]; ok {
+					hpBefore = hp
+				}
+				hpLeft[key] = hpAfter
+				hits.hit(action, parts[2])
 				if hpBefore > hpAfter {
 					action.Impact.DamageDealt += (hpBefore - hpAfter)
+					addTargetDamage(action.Impact, extractNickname(parts[2]), hpBefore-hpAfter)
 				}
 			}
 
+		case "-hitcount":
+			recordHitCount(action, parts)
+
 		case "-heal":
 			// Healing done
 			if len(parts) >= 4 {
@@ -94,6 +110,8 @@ func EnhanceActionWithImpact(action *Action, moveName string, events []string) {
 			// Immune
 			action.Impact.Effectiveness = "immune"
 			action.Result = "immune"
+			action.Immune = true
+			action.Impact.ImmuneAbility = fromAbility(parts)
 
 		case "-miss":
 			// Move missed
@@ -152,15 +170,50 @@ func EnhanceActionWithImpact(action *Action, moveName string, events []string) {
 	action.Details = generateActionDetails(action)
 }
 
-// parseHPChange extracts HP before and after from an HP string
+// addTargetDamage adds damage to the target's record, so every hit of a
+// multi-hit move lands in one.
+func addTargetDamage(impact *MoveImpact, pokemon string, damage int) {
+	for i := range impact.Targets {
+		if impact.Targets[i].Pokemon == pokemon {
+			impact.Targets[i].Damage += damage
+			return
+		}
+	}
+	impact.Targets = append(impact.Targets, TargetHit{Pokemon: pokemon, Damage: damage})
+}
+
+// markSpreadHits flags every damage record of a move that hit two or more
+// targets, whose damage was reduced by the doubles spread modifier.
+func markSpreadHits(impact *MoveImpact, moveParts []string) {
+	if impact == nil || spreadTargets(moveParts) < 2 {
+		return
+	}
+	for i := range impact.Targets {
+		impact.Targets[i].SpreadHit = true
+	}
+}
+
+// spreadTargets counts the Pokémon listed in a |move| line's [spread]
+// annotation, e.g. 2 for "[spread] p2a,p2b".
+func spreadTargets(parts []string) int {
+	if len(parts) < 5 {
+		return 0
+	}
+	for _, part := range parts[4:] {
+		if targets, ok := strings.CutPrefix(part, "[spread]"); ok {
+			return len(strings.Split(strings.TrimSpace(targets), ","))
+		}
+	}
+	return 0
+}
+
+// parseHPChange extracts HP% before and after from an HP string
 func parseHPChange(parts []string) (int, int) {
 	if len(parts) < 4 {
 		return 0, 0
 	}
-	// HP format: "100/100" or "50/100"
-	hpStr := parts[3]
-	hp, _ := parseHP(hpStr)
-	return 100, hp // Simplified - in real impl, track actual HP
+	// HP format: "50/100", or "145/207" in logs without percent HP display
+	return 100, hpPercent(parts[3]) // Simplified - in real impl, track actual HP
 }
 
 // generateActionDetails creates a human-readable description of the action's impact
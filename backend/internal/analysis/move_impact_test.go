@@ -0,0 +1,131 @@
+package analysis
+
+import "testing"
+
+func TestParseEnhancedShowdownLogSpreadHits(t *testing.T) {
+	log := `|player|p1|Alice|1|
+|player|p2|Bob|2|
+|gametype|doubles
+|start
+|switch|p1a: Garchomp|Garchomp, L50, M|100/100
+|switch|p1b: Talonflame|Talonflame, L50, M|100/100
+|switch|p2a: Heatran|Heatran, L50, M|100/100
+|switch|p2b: Amoonguss|Amoonguss, L50, M|100/100
+|turn|1
+|move|p1b: Talonflame|Brave Bird|p2b: Amoonguss
+|-supereffective|p2b: Amoonguss
+|-damage|p2b: Amoonguss|20/100
+|move|p1a: Garchomp|Earthquake|p2a: Heatran|[spread] p2a,p2b
+|-supereffective|p2a: Heatran
+|-damage|p2a: Heatran|10/100
+|-damage|p2b: Amoonguss|5/100
+|upkeep
+|turn|2
+|win|Alice
+`
+	summary, err := ParseEnhancedShowdownLog(log)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	actions := summary.Turns[0].Actions
+	if len(actions) != 2 {
+		t.Fatalf("expected 2 actions, got %d", len(actions))
+	}
+
+	single := actions[0].Impact.Targets
+	if len(single) != 1 || single[0].SpreadHit {
+		t.Errorf("expected one unflagged hit from Brave Bird, got %+v", single)
+	}
+
+	spread := actions[1].Impact.Targets
+	if len(spread) != 2 {
+		t.Fatalf("expected Earthquake to record 2 hits, got %+v", spread)
+	}
+	for _, hit := range spread {
+		if !hit.SpreadHit {
+			t.Errorf("expected %s's hit to be flagged as spread", hit.Pokemon)
+		}
+	}
+	if spread[0].Pokemon != "Heatran" || spread[0].Damage != 90 {
+		t.Errorf("expected Heatran to take 90, got %+v", spread[0])
+	}
+}
+
+func TestSpreadTargets(t *testing.T) {
+	tests := []struct {
+		line string
+		want int
+	}{
+		{"|move|p1a: Garchomp|Earthquake|p2a: Heatran|[spread] p1b,p2a,p2b", 3},
+		{"|move|p1a: Garchomp|Rock Slide|p2a: Heatran|[spread] p2a", 1},
+		{"|move|p1a: Garchomp|Dragon Claw|p2a: Heatran", 0},
+	}
+	for _, tt := range tests {
+		if got := spreadTargets(splitLogLine(tt.line)); got != tt.want {
+			t.Errorf("%s: expected %d targets, got %d", tt.line, tt.want, got)
+		}
+	}
+}
+
+func TestParseShowdownLogMultiHitMoves(t *testing.T) {
+	log := `|player|p1|Alice|1|
+|player|p2|Bob|2|
+|gametype|doubles
+|start
+|switch|p1a: Breloom|Breloom, L50, M|100/100
+|switch|p1b: Maushold|Maushold, L50|100/100
+|switch|p2a: Rotom|Rotom-Wash, L50|100/100
+|switch|p2b: Gastrodon|Gastrodon, L50, F|100/100
+|turn|1
+|move|p1a: Breloom|Bullet Seed|p2b: Gastrodon
+|-supereffective|p2b: Gastrodon
+|-damage|p2b: Gastrodon|70/100
+|-damage|p2b: Gastrodon|40/100
+|-damage|p2b: Gastrodon|10/100
+|-hitcount|p2b: Gastrodon|3
+|move|p1b: Maushold|Population Bomb|p2a: Rotom
+|-damage|p2a: Rotom|90/100
+|-damage|p2a: Rotom|80/100
+|-damage|p2a: Rotom|70/100
+|-damage|p2a: Rotom|60/100
+|move|p2a: Rotom|Hydro Pump|p1a: Breloom
+|-resisted|p1a: Breloom
+|-damage|p1a: Breloom|75/100
+|upkeep
+|turn|2
+|win|Alice
+`
+	for name, parse := range map[string]func(string) (*BattleSummary, error){
+		"basic":    ParseShowdownLog,
+		"enhanced": ParseEnhancedShowdownLog,
+	} {
+		summary, err := parse(log)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+
+		actions := summary.Turns[0].Actions
+		if len(actions) != 3 {
+			t.Fatalf("%s: expected 3 actions, got %d", name, len(actions))
+		}
+		if actions[0].Hits != 3 {
+			t.Errorf("%s: expected Bullet Seed to hit 3 times, got %d", name, actions[0].Hits)
+		}
+		if actions[1].Hits != 4 {
+			t.Errorf("%s: expected Population Bomb to hit 4 times without a hitcount line, got %d", name, actions[1].Hits)
+		}
+		if actions[2].Hits != 0 {
+			t.Errorf("%s: expected a single-hit move to have no hit count, got %d", name, actions[2].Hits)
+		}
+	}
+
+	summary, _ := ParseEnhancedShowdownLog(log)
+	impact := summary.Turns[0].Actions[0].Impact
+	if impact.DamageDealt != 90 {
+		t.Errorf("expected Bullet Seed's hits to total 90, got %d", impact.DamageDealt)
+	}
+	if len(impact.Targets) != 1 || impact.Targets[0].Damage != 90 {
+		t.Errorf("expected one Gastrodon record of 90, got %+v", impact.Targets)
+	}
+}
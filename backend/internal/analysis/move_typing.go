@@ -0,0 +1,82 @@
+package analysis
+
+import "strings"
+
+// ivyCudgelTypes maps Ogerpon's masked forms to Ivy Cudgel's type; the base
+// (Teal Mask) form keeps the move's default Grass typing.
+var ivyCudgelTypes = map[string]string{
+	"ogerpon-wellspring":  "Water",
+	"ogerpon-hearthflame": "Fire",
+	"ogerpon-cornerstone": "Rock",
+}
+
+// ragingBullTypes maps Paldean Tauros breeds to Raging Bull's type.
+var ragingBullTypes = map[string]string{
+	"tauros-paldea-combat": "Fighting",
+	"tauros-paldea-blaze":  "Fire",
+	"tauros-paldea-aqua":   "Water",
+}
+
+// weatherBallTypes maps weather IDs to Weather Ball's type; other weather
+// (Strong Winds) and clear skies leave it Normal.
+var weatherBallTypes = map[string]string{
+	"sunnyday":      "Fire",
+	"desolateland":  "Fire",
+	"raindance":     "Water",
+	"primordialsea": "Water",
+	"sandstorm":     "Rock",
+	"snow":          "Ice",
+	"hail":          "Ice",
+}
+
+// terrainPulseTypes maps terrains to Terrain Pulse's type.
+var terrainPulseTypes = map[string]string{
+	"electric terrain": "Electric",
+	"grassy terrain":   "Grass",
+	"misty terrain":    "Fairy",
+	"psychic terrain":  "Psychic",
+}
+
+// MoveTypeFor returns the type of a move whose typing depends on the user's
+// tera type or form or on the field, or "" for moves with a fixed type (see
+// attackType for those). Tera Blast takes the user's tera type once it has
+// Terastallized; Weather Ball and Terrain Pulse follow the weather and terrain
+// active when they're used.
+func (st *StateTracker) MoveTypeFor(ref, moveID string) string {
+	// A transformed Pokémon uses its copy's form; Tera forms
+	// ("Ogerpon-Wellspring-Tera") use their base form's typing
+	species := st.SpeciesFor(ref)
+	if into := st.TransformedInto(ref); into != "" {
+		species = into
+	}
+	species = strings.TrimSuffix(strings.ToLower(species), "-tera")
+
+	switch moveID {
+	case "tera blast":
+		if teraType := st.TeraTypeFor(ref); teraType != "" {
+			return teraType
+		}
+		return "Normal"
+	case "ivy cudgel":
+		if moveType, ok := ivyCudgelTypes[species]; ok {
+			return moveType
+		}
+		return "Grass"
+	case "raging bull":
+		if moveType, ok := ragingBullTypes[species]; ok {
+			return moveType
+		}
+		return "Normal"
+	case "weather ball":
+		if moveType, ok := weatherBallTypes[st.weather]; ok {
+			return moveType
+		}
+		return "Normal"
+	case "terrain pulse":
+		if moveType, ok := terrainPulseTypes[st.terrain]; ok {
+			return moveType
+		}
+		return "Normal"
+	}
+	return ""
+}
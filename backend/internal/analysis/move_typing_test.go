@@ -0,0 +1,139 @@
+package analysis
+
+import "testing"
+
+const teraBlastLog = `|player|p1|Alice|1|
+|player|p2|Bob|2|
+|gen|9
+|gametype|doubles
+|start
+|switch|p1a: Pika|Pikachu, L50, M|100/100
+|switch|p1b: Ogerpon|Ogerpon-Wellspring, L50, F|100/100
+|switch|p2a: Gholdengo|Gholdengo, L50|100/100
+|switch|p2b: Incineroar|Incineroar, L50, M|100/100
+|turn|1
+|move|p1a: Pika|Tera Blast|p2a: Gholdengo
+|-damage|p2a: Gholdengo|80/100
+|upkeep
+|turn|2
+|-terastallize|p1a: Pika|Ground
+|move|p1a: Pika|Tera Blast|p2a: Gholdengo
+|-supereffective|p2a: Gholdengo
+|-damage|p2a: Gholdengo|10/100
+|move|p1b: Ogerpon|Ivy Cudgel|p2b: Incineroar
+|-supereffective|p2b: Incineroar
+|-damage|p2b: Incineroar|20/100
+|upkeep
+|turn|3
+|win|Alice
+`
+
+func TestParseShowdownLogTeraBlastType(t *testing.T) {
+	for name, parse := range map[string]func(string) (*BattleSummary, error){
+		"basic":    ParseShowdownLog,
+		"enhanced": ParseEnhancedShowdownLog,
+	} {
+		t.Run(name, func(t *testing.T) {
+			summary, err := parse(teraBlastLog)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(summary.Turns) < 2 {
+				t.Fatalf("expected at least 2 turns, got %d", len(summary.Turns))
+			}
+
+			if got := moveTypeIn(summary.Turns[0], "Tera Blast"); got != "Normal" {
+				t.Errorf("expected Tera Blast before Terastallizing to be Normal, got %q", got)
+			}
+			if got := moveTypeIn(summary.Turns[1], "Tera Blast"); got != "Ground" {
+				t.Errorf("expected Tera Blast after Terastallizing to be Ground, got %q", got)
+			}
+			if got := moveTypeIn(summary.Turns[1], "Ivy Cudgel"); got != "Water" {
+				t.Errorf("expected Wellspring Ivy Cudgel to be Water, got %q", got)
+			}
+		})
+	}
+}
+
+func TestMoveTypeForKeepsTeraAcrossSwitches(t *testing.T) {
+	tracker := NewStateTracker()
+	tracker.RegisterSpecies("p1a: Pika", "Pikachu")
+	tracker.SetTeraType("p1a: Pika", "Ground")
+
+	// Switching back in re-registers the species but Terastallization persists
+	tracker.RegisterSpecies("p1b: Pika", "Pikachu")
+
+	if got := tracker.MoveTypeFor("p1b: Pika", "tera blast"); got != "Ground" {
+		t.Errorf("expected Ground, got %q", got)
+	}
+	if got := tracker.MoveTypeFor("p1b: Pika", "thunderbolt"); got != "" {
+		t.Errorf("expected fixed-type move to be left blank, got %q", got)
+	}
+}
+
+func moveTypeIn(turn Turn, moveName string) string {
+	for _, action := range turn.Actions {
+		if action.Move != nil && action.Move.Name == moveName {
+			return action.Move.Type
+		}
+	}
+	return ""
+}
+
+func TestParseShowdownLogFieldDependentMoveTypes(t *testing.T) {
+	log := `|player|p1|Alice|1|
+|player|p2|Bob|2|
+|gametype|doubles
+|start
+|switch|p1a: Torkoal|Torkoal, L50, M|100/100
+|-weather|SunnyDay|[from] ability: Drought|[of] p1a: Torkoal
+|switch|p1b: Indeedee|Indeedee-F, L50, F|100/100
+|-fieldstart|move: Psychic Terrain|[from] ability: Psychic Surge|[of] p1b: Indeedee
+|switch|p2a: Kingambit|Kingambit, L50, M|100/100
+|switch|p2b: Amoonguss|Amoonguss, L50, F|100/100
+|turn|1
+|move|p1a: Torkoal|Weather Ball|p2a: Kingambit
+|-supereffective|p2a: Kingambit
+|-damage|p2a: Kingambit|30/100
+|move|p1b: Indeedee|Terrain Pulse|p2b: Amoonguss
+|-damage|p2b: Amoonguss|60/100
+|upkeep
+|turn|2
+|-weather|none
+|-fieldend|move: Psychic Terrain
+|move|p1a: Torkoal|Weather Ball|p2a: Kingambit
+|-resisted|p2a: Kingambit
+|-damage|p2a: Kingambit|25/100
+|move|p1b: Indeedee|Terrain Pulse|p2b: Amoonguss
+|-damage|p2b: Amoonguss|45/100
+|upkeep
+|win|Alice
+`
+	for name, parse := range map[string]func(string) (*BattleSummary, error){
+		"basic":    ParseShowdownLog,
+		"enhanced": ParseEnhancedShowdownLog,
+	} {
+		t.Run(name, func(t *testing.T) {
+			summary, err := parse(log)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(summary.Turns) != 2 {
+				t.Fatalf("expected 2 turns, got %d", len(summary.Turns))
+			}
+
+			if got := moveTypeIn(summary.Turns[0], "Weather Ball"); got != "Fire" {
+				t.Errorf("expected Weather Ball under sun to be Fire, got %q", got)
+			}
+			if got := moveTypeIn(summary.Turns[0], "Terrain Pulse"); got != "Psychic" {
+				t.Errorf("expected Terrain Pulse in Psychic Terrain to be Psychic, got %q", got)
+			}
+			if got := moveTypeIn(summary.Turns[1], "Weather Ball"); got != "Normal" {
+				t.Errorf("expected Weather Ball without weather to be Normal, got %q", got)
+			}
+			if got := moveTypeIn(summary.Turns[1], "Terrain Pulse"); got != "Normal" {
+				t.Errorf("expected Terrain Pulse without terrain to be Normal, got %q", got)
+			}
+		})
+	}
+}
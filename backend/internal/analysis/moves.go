@@ -0,0 +1,39 @@
+package analysis
+
+import (
+	_ "embed"
+	"encoding/json"
+)
+
+//go:embed data/moves.json
+var movesData []byte
+
+// moveTypes maps move IDs (normalizeID of the name) to their type. It only
+// covers attacking moves with a fixed type; status moves and moves typed by
+// their user or the field (Tera Blast, Weather Ball) aren't listed.
+var moveTypes = mustLoadMoveTypes(movesData)
+
+// mustLoadMoveTypes indexes data/moves.json, which lists move names by type.
+func mustLoadMoveTypes(data []byte) map[string]string {
+	var byType map[string][]string
+	if err := json.Unmarshal(data, &byType); err != nil {
+		panic("analysis: invalid moves.json: " + err.Error())
+	}
+	types := make(map[string]string)
+	for moveType, moves := range byType {
+		for _, name := range moves {
+			types[normalizeID(name)] = moveType
+		}
+	}
+	return types
+}
+
+// attackType returns the type a move was used with: MoveTypeFor for moves
+// whose type varies, otherwise the move's listed type, or "" for status moves
+// and moves missing from the dataset.
+func (st *StateTracker) attackType(ref, moveID string) string {
+	if moveType := st.MoveTypeFor(ref, moveID); moveType != "" {
+		return moveType
+	}
+	return moveTypes[moveID]
+}
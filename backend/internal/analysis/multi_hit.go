@@ -0,0 +1,37 @@
+package analysis
+
+// hitCounter counts how many times the current move has struck each target,
+// so a multi-hit move (Bullet Seed, Population Bomb) stays one action with a
+// hit count instead of reading as several moves.
+type hitCounter map[string]int
+
+// hit records one direct damage instance against ref. The action's Hits is
+// only set once some target has been struck more than once.
+func (h hitCounter) hit(action *Action, ref string) {
+	key := pokemonKey(ref)
+	h[key]++
+	if action != nil && h[key] > 1 && h[key] > action.Hits {
+		action.Hits = h[key]
+	}
+}
+
+// recordHitCount applies a |-hitcount|POKEMON|N line, Showdown's own total
+// for the move, over whatever was counted from damage lines.
+func recordHitCount(action *Action, parts []string) {
+	if action != nil && len(parts) > 3 {
+		action.Hits = parseInt(parts[3])
+	}
+}
+
+// lastMoveAction returns the turn's latest action if it is a move, i.e. the
+// action the damage lines that follow belong to.
+func lastMoveAction(turn *Turn) *Action {
+	if turn == nil || len(turn.Actions) == 0 {
+		return nil
+	}
+	action := &turn.Actions[len(turn.Actions)-1]
+	if action.ActionType != "move" {
+		return nil
+	}
+	return action
+}
@@ -0,0 +1,92 @@
+package analysis
+
+import (
+	"sort"
+	"strings"
+)
+
+// PlayerOpenings is how a player tends to open across many battles.
+type PlayerOpenings struct {
+	PlayerID       string       `json:"playerId"`
+	GamesPlayed    int          `json:"gamesPlayed"`
+	Leads          []LeadUsage  `json:"leads"`          // Most common first
+	FirstTurnMoves []UsageCount `json:"firstTurnMoves"` // Battles each move was chosen on turn 1, most common first
+}
+
+// LeadUsage counts the battles a player led with a set of Pokémon.
+type LeadUsage struct {
+	Pokemon []string `json:"pokemon"` // Alphabetical, so the same pair in either slot is one lead
+	Count   int      `json:"count"`
+}
+
+// PlayerBattle is a parsed battle seen from one player's side.
+type PlayerBattle struct {
+	Slot    string // "player1" or "player2"
+	Summary *BattleSummary
+}
+
+// AggregateOpenings counts a player's lead pairs and turn-1 moves, keeping the
+// top entries of each. A move counts once per battle however many of the
+// leads used it. Battles without a summary count as played but add nothing
+// else. Lists are empty (never nil) when nothing matched.
+func AggregateOpenings(playerID string, battles []PlayerBattle, top int) PlayerOpenings {
+	leads := make(map[string]int)
+	moves := make(map[string]int)
+	for _, battle := range battles {
+		if battle.Summary == nil {
+			continue
+		}
+		side := &battle.Summary.Player1
+		if battle.Slot == "player2" {
+			side = &battle.Summary.Player2
+		}
+		if lead := LeadKey(side.Leads); lead != "" {
+			leads[lead]++
+		}
+		for move := range firstTurnMoves(battle.Summary, battle.Slot) {
+			moves[move]++
+		}
+	}
+
+	openings := PlayerOpenings{
+		PlayerID:       playerID,
+		GamesPlayed:    len(battles),
+		Leads:          []LeadUsage{},
+		FirstTurnMoves: topUsage(moves, top),
+	}
+	for _, lead := range topUsage(leads, top) {
+		openings.Leads = append(openings.Leads, LeadUsage{
+			Pokemon: strings.Split(lead.Name, "|"),
+			Count:   lead.Count,
+		})
+	}
+	return openings
+}
+
+// LeadKey sorts leads and joins them by "|", so the same pair led in either
+// order is one key, or returns "" for no leads.
+func LeadKey(leads []string) string {
+	if len(leads) == 0 {
+		return ""
+	}
+	sorted := append([]string(nil), leads...)
+	sort.Strings(sorted)
+	return strings.Join(sorted, "|")
+}
+
+// firstTurnMoves returns the moves a side chose on turn 1. Moves called by
+// another move or ability (Dancer, Sleep Talk) weren't chosen and are skipped.
+func firstTurnMoves(summary *BattleSummary, slot string) map[string]struct{} {
+	moves := make(map[string]struct{})
+	for _, turn := range summary.Turns {
+		if turn.TurnNumber != 1 {
+			continue
+		}
+		for _, action := range turn.Actions {
+			if action.Player == slot && action.Move != nil && action.Source == "" {
+				moves[action.Move.Name] = struct{}{}
+			}
+		}
+	}
+	return moves
+}
@@ -0,0 +1,70 @@
+package analysis
+
+import "testing"
+
+// openingLog is a doubles battle where p1 leads lead1 and Incineroar, and on
+// turn 1 lead1 uses move while Incineroar uses Fake Out.
+func openingLog(p1, p2, lead1, move string) string {
+	return `|player|p1|` + p1 + `|1|
+|player|p2|` + p2 + `|2|
+|start
+|switch|p1a: ` + lead1 + `|` + lead1 + `, L50|100/100
+|switch|p1b: Incineroar|Incineroar, L50|100/100
+|switch|p2a: Rillaboom|Rillaboom, L50|100/100
+|turn|1
+|move|p1a: ` + lead1 + `|` + move + `|p2a: Rillaboom
+|move|p1b: Incineroar|Fake Out|p2a: Rillaboom
+|move|p2a: Rillaboom|Fake Out|p1b: Incineroar
+|upkeep
+|turn|2
+|move|p1a: ` + lead1 + `|Protect|p1a: ` + lead1 + `
+`
+}
+
+func TestAggregateOpenings(t *testing.T) {
+	parse := func(log string) *BattleSummary {
+		summary, err := ParseShowdownLog(log)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return summary
+	}
+
+	// Alice leads Amoonguss + Incineroar twice and Urshifu + Incineroar once;
+	// from slot 2 she leads Rillaboom, and one log didn't parse
+	battles := []PlayerBattle{
+		{Slot: "player1", Summary: parse(openingLog("Alice", "Bob", "Amoonguss", "Spore"))},
+		{Slot: "player1", Summary: parse(openingLog("Alice", "Bob", "Amoonguss", "Rage Powder"))},
+		{Slot: "player1", Summary: parse(openingLog("Alice", "Carol", "Urshifu", "Close Combat"))},
+		{Slot: "player2", Summary: parse(openingLog("Bob", "Alice", "Urshifu", "Wicked Blow"))},
+		{Slot: "player1"},
+	}
+
+	openings := AggregateOpenings("Alice", battles, 10)
+
+	if openings.PlayerID != "Alice" || openings.GamesPlayed != 5 {
+		t.Errorf("expected 5 games for Alice, got %+v", openings)
+	}
+	if len(openings.Leads) != 3 {
+		t.Fatalf("expected 3 distinct leads, got %+v", openings.Leads)
+	}
+	top := openings.Leads[0]
+	if top.Count != 2 || len(top.Pokemon) != 2 || top.Pokemon[0] != "Amoonguss" || top.Pokemon[1] != "Incineroar" {
+		t.Errorf("expected Amoonguss + Incineroar led twice first, got %+v", top)
+	}
+	if len(openings.FirstTurnMoves) == 0 || openings.FirstTurnMoves[0] != (UsageCount{Name: "Fake Out", Count: 4}) {
+		t.Errorf("expected Fake Out on turn 1 in all 4 parsed battles first, got %+v", openings.FirstTurnMoves)
+	}
+	for _, move := range openings.FirstTurnMoves {
+		if move.Name == "Protect" || move.Name == "Wicked Blow" {
+			t.Errorf("expected only Alice's turn-1 moves, got %+v", openings.FirstTurnMoves)
+		}
+	}
+}
+
+func TestAggregateOpeningsNoBattles(t *testing.T) {
+	openings := AggregateOpenings("Nobody", nil, 10)
+	if openings.GamesPlayed != 0 || openings.Leads == nil || openings.FirstTurnMoves == nil {
+		t.Errorf("expected no games and empty, non-nil lists, got %+v", openings)
+	}
+}
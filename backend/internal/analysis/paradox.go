@@ -0,0 +1,97 @@
+package analysis
+
+import "strings"
+
+// paradoxAbility describes an ability that boosts its holder's highest stat
+// while a field condition is up or after Booster Energy is consumed.
+type paradoxAbility struct {
+	name  string // "Protosynthesis"
+	field string // What activates it without Booster Energy
+}
+
+// paradoxAbilities maps the effect IDs in -start lines
+// (|-start|p1a: Flutter Mane|protosynthesisspe) to their ability.
+var paradoxAbilities = map[string]paradoxAbility{
+	"protosynthesis": {name: "Protosynthesis", field: "Sun"},
+	"quarkdrive":     {name: "Quark Drive", field: "Electric Terrain"},
+}
+
+// paradoxStats are the stats a paradox ability can boost, as they end the
+// -start effect ID.
+var paradoxStats = []string{"atk", "def", "spa", "spd", "spe"}
+
+// parseParadoxStart splits a -start line for a paradox ability into the
+// ability and the boosted stat, e.g. Protosynthesis and "spe".
+func parseParadoxStart(parts []string) (paradoxAbility, string, bool) {
+	if len(parts) < 4 {
+		return paradoxAbility{}, "", false
+	}
+	effect := strings.ToLower(strings.TrimSpace(parts[3]))
+	for _, stat := range paradoxStats {
+		if ability, ok := paradoxAbilities[strings.TrimSuffix(effect, stat)]; ok && strings.HasSuffix(effect, stat) {
+			return ability, stat, true
+		}
+	}
+	return paradoxAbility{}, "", false
+}
+
+// isParadoxAbility reports whether an effect names a paradox ability, as in
+// |-activate|p1a: Flutter Mane|ability: Protosynthesis or |-end|...|Quark Drive.
+func isParadoxAbility(effect string) bool {
+	effect = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(effect), "ability:"))
+	for _, ability := range paradoxAbilities {
+		if strings.EqualFold(effect, ability.name) {
+			return true
+		}
+	}
+	return false
+}
+
+// paradoxActivate notes whether a paradox ability activating in a position
+// was triggered by Booster Energy ([fromitem]) rather than the field.
+func (s *statStages) paradoxActivate(parts []string) {
+	if len(parts) < 4 || !isParadoxAbility(parts[3]) {
+		return
+	}
+	s.fromBooster[fieldPosition(parts[2])] = hasTag(parts, "[fromitem]")
+}
+
+// paradoxStart records the stat a paradox ability boosted in a -start line
+// and returns the activation, with its source, for the summary.
+func (s *statStages) paradoxStart(parts []string) (ParadoxBoost, bool) {
+	ability, stat, ok := parseParadoxStart(parts)
+	if !ok {
+		return ParadoxBoost{}, false
+	}
+	position := fieldPosition(parts[2])
+	s.paradox[position] = stat
+
+	source := ability.field
+	if s.fromBooster[position] {
+		source = "Booster Energy"
+	}
+	delete(s.fromBooster, position)
+	return ParadoxBoost{Ability: ability.name, Stat: stat, Source: source}, true
+}
+
+// paradoxEnd clears a position's boost on |-end|p1a: Flutter Mane|Protosynthesis.
+func (s *statStages) paradoxEnd(parts []string) {
+	if len(parts) > 3 && isParadoxAbility(parts[3]) {
+		delete(s.paradox, fieldPosition(parts[2]))
+	}
+}
+
+// paradoxStat returns the stat boosted by a paradox ability for the Pokémon
+// in ref's position, or "".
+func (s *statStages) paradoxStat(ref string) string {
+	return s.paradox[fieldPosition(ref)]
+}
+
+// recordParadoxBoost adds a paradox ability activation to the summary.
+func recordParadoxBoost(summary *BattleSummary, tracker *StateTracker, turn int, ref string, boost ParadoxBoost) {
+	boost.Turn = turn
+	boost.Player = extractPlayerIDFromRef(ref)
+	boost.Pokemon = extractNickname(ref)
+	boost.Species = tracker.SpeciesFor(ref)
+	summary.ParadoxBoosts = append(summary.ParadoxBoosts, boost)
+}
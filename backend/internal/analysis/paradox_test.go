@@ -0,0 +1,121 @@
+package analysis
+
+import "testing"
+
+const paradoxLog = `|player|p1|Alice|1|
+|player|p2|Bob|2|
+|gen|9
+|gametype|doubles
+|poke|p1|Flutter Mane, L50|
+|poke|p1|Amoonguss, L50, F|
+|poke|p1|Incineroar, L50, M|
+|poke|p2|Miraidon, L50|
+|poke|p2|Iron Hands, L50|
+|poke|p2|Rillaboom, L50, M|
+|start
+|switch|p1a: Flutter Mane|Flutter Mane, L50|100/100
+|switch|p1b: Amoonguss|Amoonguss, L50, F|100/100
+|switch|p2a: Miraidon|Miraidon, L50|100/100
+|switch|p2b: Iron Hands|Iron Hands, L50|100/100
+|-fieldstart|move: Electric Terrain|[from] ability: Hadron Engine|[of] p2a: Miraidon
+|-activate|p2b: Iron Hands|ability: Quark Drive
+|-start|p2b: Iron Hands|quarkdriveatk
+|-enditem|p1a: Flutter Mane|Booster Energy
+|-activate|p1a: Flutter Mane|ability: Protosynthesis|[fromitem]
+|-start|p1a: Flutter Mane|protosynthesisspe
+|turn|1
+|move|p1a: Flutter Mane|Moonblast|p2a: Miraidon
+|-damage|p2a: Miraidon|60/100
+|move|p2b: Iron Hands|Drain Punch|p1b: Amoonguss
+|-resisted|p1b: Amoonguss
+|-damage|p1b: Amoonguss|80/100
+|upkeep
+|turn|2
+|switch|p1a: Incineroar|Incineroar, L50, M|100/100
+|-fieldend|move: Electric Terrain
+|-end|p2b: Iron Hands|Quark Drive
+|move|p2b: Iron Hands|Drain Punch|p1a: Incineroar
+|-damage|p1a: Incineroar|50/100
+|upkeep
+|turn|3
+|win|Alice
+`
+
+func TestParseShowdownLogParadoxBoosts(t *testing.T) {
+	summary, err := ParseShowdownLog(paradoxLog)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []ParadoxBoost{
+		{Turn: 0, Player: "player2", Pokemon: "Iron Hands", Species: "Iron Hands", Ability: "Quark Drive", Stat: "atk", Source: "Electric Terrain"},
+		{Turn: 0, Player: "player1", Pokemon: "Flutter Mane", Species: "Flutter Mane", Ability: "Protosynthesis", Stat: "spe", Source: "Booster Energy"},
+	}
+	if len(summary.ParadoxBoosts) != len(want) {
+		t.Fatalf("expected %d paradox boosts, got %+v", len(want), summary.ParadoxBoosts)
+	}
+	for i, boost := range summary.ParadoxBoosts {
+		if boost != want[i] {
+			t.Errorf("boost %d: expected %+v, got %+v", i, want[i], boost)
+		}
+	}
+}
+
+func TestParseShowdownLogParadoxBoostOnMoves(t *testing.T) {
+	for name, parse := range map[string]func(string) (*BattleSummary, error){
+		"basic":    ParseShowdownLog,
+		"enhanced": ParseEnhancedShowdownLog,
+	} {
+		t.Run(name, func(t *testing.T) {
+			summary, err := parse(paradoxLog)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(summary.Turns) < 2 {
+				t.Fatalf("expected 2 turns, got %d", len(summary.Turns))
+			}
+
+			type use struct {
+				turn    int
+				pokemon string
+			}
+			boosts := map[use]string{}
+			for _, turn := range summary.Turns[:2] {
+				for _, action := range turn.Actions {
+					if action.ActionType == "move" {
+						boosts[use{turn.TurnNumber, action.Pokemon}] = action.ParadoxBoost
+					}
+				}
+			}
+
+			want := map[use]string{
+				{1, "Flutter Mane"}: "spe",
+				{1, "Iron Hands"}:   "atk",
+				{2, "Iron Hands"}:   "", // Quark Drive ended with the terrain
+			}
+			for key, stat := range want {
+				if got, ok := boosts[key]; !ok || got != stat {
+					t.Errorf("turn %d %s: expected paradox boost %q, got %q (present %v)", key.turn, key.pokemon, stat, got, ok)
+				}
+			}
+		})
+	}
+}
+
+func TestStatStagesParadoxClearedOnSwitch(t *testing.T) {
+	stages := newStatStages()
+	stages.paradoxActivate(splitLogLine("|-activate|p1a: Flutter Mane|ability: Protosynthesis|[fromitem]"))
+	if _, ok := stages.paradoxStart(splitLogLine("|-start|p1a: Flutter Mane|protosynthesisspa")); !ok {
+		t.Fatal("expected protosynthesisspa to be recognized")
+	}
+	if got := stages.paradoxStat("p1a: Flutter Mane"); got != "spa" {
+		t.Errorf("expected spa boost, got %q", got)
+	}
+
+	// Baton Pass passes stat stages but not the ability's boost
+	stages.move(splitLogLine("|move|p1a: Flutter Mane|Baton Pass|p1a: Flutter Mane"))
+	stages.switchIn("p1a: Incineroar")
+	if got := stages.paradoxStat("p1a: Incineroar"); got != "" {
+		t.Errorf("expected no boost after switching, got %q", got)
+	}
+}
@@ -0,0 +1,63 @@
+package analysis
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultMaxTurns is the turn limit used when ParseOptions.MaxTurns is unset.
+// Real battles end long before this; it only guards against crafted logs.
+const DefaultMaxTurns = 1000
+
+// ParseOptions tunes the limits applied while parsing a log.
+type ParseOptions struct {
+	// MaxTurns stops parsing after this many |turn| lines, keeping the
+	// partial summary and recording a warning. Zero means DefaultMaxTurns.
+	MaxTurns int
+
+	// AnonymizePlayers replaces every username with its seat ("Player 1",
+	// "Player 2", ...) and drops ratings, for sharing a summary publicly.
+	AnonymizePlayers bool
+
+	// AnonymizeNames does the same for just these usernames (matched
+	// case-insensitively), e.g. players who asked not to be shown.
+	AnonymizeNames []string
+}
+
+func (o ParseOptions) maxTurns() int {
+	if o.MaxTurns > 0 {
+		return o.MaxTurns
+	}
+	return DefaultMaxTurns
+}
+
+func turnLimitWarning(limit int) string {
+	return fmt.Sprintf("log exceeds %d turns; parsing stopped early", limit)
+}
+
+// anonymizes reports whether the player named name should be hidden.
+func (o ParseOptions) anonymizes(name string) bool {
+	if o.AnonymizePlayers {
+		return true
+	}
+	for _, hidden := range o.AnonymizeNames {
+		if strings.EqualFold(strings.TrimSpace(hidden), strings.TrimSpace(name)) {
+			return true
+		}
+	}
+	return false
+}
+
+// anonymizePlayers renames hidden players after their seat and strips their
+// ratings. Everything else refers to players by slot, so only Name and Rating
+// identify them.
+func anonymizePlayers(summary *BattleSummary, opts ParseOptions) {
+	for _, slot := range summary.slots() {
+		player := summary.PlayerBySlot(slot)
+		if player == nil || player.Name == "" || !opts.anonymizes(player.Name) {
+			continue
+		}
+		player.Name = "Player " + strings.TrimPrefix(slot, "p")
+		player.Rating = 0
+	}
+}
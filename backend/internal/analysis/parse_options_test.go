@@ -0,0 +1,105 @@
+package analysis
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func longBattleLog(turns int) string {
+	var b strings.Builder
+	b.WriteString("|player|p1|Alice|1|\n|player|p2|Bob|2|\n|start\n")
+	b.WriteString("|switch|p1a: Pikachu|Pikachu, L50, M|100/100\n")
+	b.WriteString("|switch|p2a: Blastoise|Blastoise, L50, M|100/100\n")
+	for i := 1; i <= turns; i++ {
+		fmt.Fprintf(&b, "|turn|%d\n|move|p1a: Pikachu|Protect|p1a: Pikachu\n|upkeep\n", i)
+	}
+	b.WriteString("|win|Alice\n")
+	return b.String()
+}
+
+func TestParseShowdownLogMaxTurns(t *testing.T) {
+	opts := ParseOptions{MaxTurns: 5}
+
+	for name, parse := range map[string]func(string, ParseOptions) (*BattleSummary, error){
+		"basic":    ParseShowdownLogWithOptions,
+		"enhanced": ParseEnhancedShowdownLogWithOptions,
+	} {
+		summary, err := parse(longBattleLog(50), opts)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+
+		if len(summary.Turns) != 5 {
+			t.Errorf("%s: expected parsing to stop after 5 turns, got %d", name, len(summary.Turns))
+		}
+		if len(summary.Warnings) != 1 {
+			t.Errorf("%s: expected 1 warning, got %v", name, summary.Warnings)
+		}
+		if summary.Winner != "" {
+			t.Errorf("%s: expected lines after the limit to be skipped, got winner %q", name, summary.Winner)
+		}
+	}
+}
+
+func TestParseShowdownLogDefaultMaxTurns(t *testing.T) {
+	summary, _ := ParseShowdownLog(longBattleLog(50))
+
+	if len(summary.Turns) != 50 {
+		t.Errorf("expected all 50 turns under the default limit, got %d", len(summary.Turns))
+	}
+	if len(summary.Warnings) != 0 {
+		t.Errorf("expected no warnings, got %v", summary.Warnings)
+	}
+}
+
+func TestParseShowdownLogAnonymizePlayers(t *testing.T) {
+	plain, err := ParseEnhancedShowdownLog(sampleBattleLog())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	summary, err := ParseEnhancedShowdownLogWithOptions(sampleBattleLog(), ParseOptions{AnonymizePlayers: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if summary.Player1.Name != "Player 1" || summary.Player2.Name != "Player 2" {
+		t.Errorf("expected seat names, got %q and %q", summary.Player1.Name, summary.Player2.Name)
+	}
+	if summary.Player1.Rating != 0 || summary.Player2.Rating != 0 {
+		t.Errorf("expected ratings to be stripped, got %d and %d", summary.Player1.Rating, summary.Player2.Rating)
+	}
+	if summary.Winner != plain.Winner {
+		t.Errorf("expected winner %q to be kept, got %q", plain.Winner, summary.Winner)
+	}
+	if summary.Players["p1"].Name != "Player 1" {
+		t.Errorf("expected Players to share the renamed seat, got %q", summary.Players["p1"].Name)
+	}
+
+	if len(summary.Turns) != len(plain.Turns) {
+		t.Fatalf("expected %d turns, got %d", len(plain.Turns), len(summary.Turns))
+	}
+	for i := range plain.Turns {
+		if len(summary.Turns[i].Actions) != len(plain.Turns[i].Actions) {
+			t.Errorf("turn %d: expected %d actions, got %d", i+1, len(plain.Turns[i].Actions), len(summary.Turns[i].Actions))
+		}
+	}
+
+	if recap := Recap(summary); strings.Contains(recap, "Player1") || strings.Contains(recap, "Player2") {
+		t.Errorf("expected no usernames in the recap, got %q", recap)
+	}
+}
+
+func TestParseShowdownLogAnonymizeNames(t *testing.T) {
+	summary, err := ParseShowdownLogWithOptions(sampleBattleLog(), ParseOptions{AnonymizeNames: []string{"player2"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if summary.Player1.Name != "Player1" || summary.Player1.Rating != 1487 {
+		t.Errorf("expected Player1 to be left alone, got %q rated %d", summary.Player1.Name, summary.Player1.Rating)
+	}
+	if summary.Player2.Name != "Player 2" || summary.Player2.Rating != 0 {
+		t.Errorf("expected Player2 to be anonymized, got %q rated %d", summary.Player2.Name, summary.Player2.Rating)
+	}
+}
@@ -3,32 +3,71 @@ package analysis
 import (
 	"crypto/rand"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 )
 
+// IDGenerator returns the ID given to each parsed BattleSummary. It defaults
+// to a random UUID; tests may replace it for deterministic IDs but must restore
+// it, and must not run in parallel with other parses while it is replaced.
+var IDGenerator = generateUUID
+
 // ParseShowdownLog parses a Pokémon Showdown battle log and returns a comprehensive BattleSummary.
 func ParseShowdownLog(logContent string) (*BattleSummary, error) {
-	lines := strings.Split(logContent, "\n")
+	return ParseShowdownLogWithOptions(logContent, ParseOptions{})
+}
+
+// ParseShowdownLogWithOptions is ParseShowdownLog with configurable limits.
+func ParseShowdownLogWithOptions(logContent string, opts ParseOptions) (*BattleSummary, error) {
+	lines := splitLogLines(logContent)
+	fields := splitLogFields(lines)
+	maxTurns := opts.maxTurns()
 
 	summary := &BattleSummary{
-		ID:         generateUUID(),
-		Timestamp:  time.Now(),
-		Turns:      []Turn{},
-		KeyMoments: []KeyMoment{},
-		Stats:      BattleStats{},
+		ID:             IDGenerator(),
+		Timestamp:      time.Now(),
+		Rules:          []string{},
+		Warnings:       []string{},
+		Turns:          make([]Turn, 0, countTurns(lines)),
+		KeyMoments:     []KeyMoment{},
+		SideConditions: []SideCondition{},
+		WeatherSetters: []WeatherSetter{},
+		ItemsRemoved:   []ItemRemoved{},
+		ClockEvents:    []ClockEvent{},
+		KOEvents:       []KOEvent{},
+		LostTurns:      []LostTurn{},
+		ParadoxBoosts:  []ParadoxBoost{},
+		Stats: BattleStats{
+			SwitchCount:           map[string]int{"player1": 0, "player2": 0},
+			ReplacementCount:      map[string]int{"player1": 0, "player2": 0},
+			ImmuneCount:           map[string]int{"player1": 0, "player2": 0},
+			AbilityImmuneCount:    map[string]int{"player1": 0, "player2": 0},
+			ItemsKnockedOff:       map[string]int{"player1": 0, "player2": 0},
+			LostTurns:             map[string]int{"player1": 0, "player2": 0},
+			TypeEffectiveness:     map[string]TypeEffectiveness{},
+			PassiveDamage:         map[string]float64{"player1": 0, "player2": 0},
+			PassiveDamageBySource: map[string]float64{},
+			DamageTakenByPokemon:  map[string]float64{},
+			StatusTurns:           map[string]int{},
+			RestTurns:             map[string]int{},
+			MinTimeLeft:           map[string]int{},
+		},
 	}
+	summary.Player1 = newPlayer()
+	summary.Player2 = newPlayer()
+	summary.Players = map[string]*Player{"p1": &summary.Player1, "p2": &summary.Player2}
 
 	// Create a state tracker to maintain battle state throughout
 	tracker := NewStateTracker()
 
 	// First pass: extract metadata and team information
-	for _, line := range lines {
+	for i, line := range lines {
 		if line == "" || !strings.HasPrefix(line, "|") {
 			continue
 		}
 
-		parts := strings.Split(line, "|")
+		parts := fields[i]
 		if len(parts) < 2 {
 			continue
 		}
@@ -41,16 +80,29 @@ func ParseShowdownLog(logContent string) (*BattleSummary, error) {
 				summary.Format = strings.Join(parts[2:], "|")
 			}
 
+		case "gen":
+			if len(parts) > 2 {
+				summary.Generation = parseInt(parts[2])
+			}
+
+		case "rule":
+			if len(parts) > 2 {
+				rule := strings.TrimSpace(strings.Join(parts[2:], "|"))
+				if rule != "" && !contains(summary.Rules, rule) {
+					summary.Rules = append(summary.Rules, rule)
+				}
+			}
+
 		case "player":
 			if len(parts) > 3 {
 				playerID := parts[2]
 				playerName := parts[3]
 				tracker.SetPlayerName(playerID, playerName)
-				switch playerID {
-				case "p1":
-					summary.Player1.Name = playerName
-				case "p2":
-					summary.Player2.Name = playerName
+				if player := summary.seat(playerID); player != nil {
+					player.Name = playerName
+					if rating := parsePlayerRating(parts); rating > 0 {
+						player.Rating = rating
+					}
 				}
 			}
 
@@ -61,6 +113,17 @@ func ParseShowdownLog(logContent string) (*BattleSummary, error) {
 				tracker.SetTeamSize(playerID, teamSize)
 			}
 
+		case "teampreview":
+			// |teampreview|4 limits each player to bringing 4; a bare
+			// |teampreview| lets them bring everything shown
+			if len(parts) > 2 {
+				summary.BringCount = parseInt(parts[2])
+			}
+
+		case "clearpoke":
+			// A new preview follows; drop any earlier (superseded) list
+			tracker.ClearTeams()
+
 		case "poke":
 			if len(parts) > 3 {
 				playerID := parts[2]
@@ -70,23 +133,53 @@ func ParseShowdownLog(logContent string) (*BattleSummary, error) {
 			}
 		}
 	}
+	for _, slot := range summary.slots() {
+		if shown := len(tracker.GetTeam(slot)); shown > summary.PreviewSize {
+			summary.PreviewSize = shown
+		}
+	}
 
 	// Initialize tracker with teams
-	summary.Player1.Team = tracker.GetTeam("p1")
-	summary.Player2.Team = tracker.GetTeam("p2")
-	summary.Player1.TotalLeft = tracker.GetTeamSize("p1")
-	summary.Player2.TotalLeft = tracker.GetTeamSize("p2")
+	for _, slot := range summary.slots() {
+		player := summary.Players[slot]
+		player.Team = tracker.GetTeam(slot)
+		player.TotalLeft = tracker.GetTeamSize(slot)
+	}
 
 	// Second pass: process all battle events
 	var currentTurn *Turn
 	var turnNumber int
-
-	for _, line := range lines {
+	// Where the log is within the current turn
+	var boundary turnBoundary
+	// The most recent |move| line, for attributing the effects it causes
+	var lastMove []string
+	survival := newSurvivalTracker()
+	// The Pokémon whose Destiny Bond just activated, credited with the next opposing faint
+	var destinyBond string
+	// pokemonKey -> Pokémon named by [of] on its latest damage line, for KO credit
+	damageSources := make(map[string]string)
+	protects := newProtectTracker()
+	abilities := newAbilityTracker()
+	statuses := newStatusTimer()
+	hits := hitCounter{}
+	matchups := newTypeMatchups()
+	delayed := newDelayedDamage()
+	stages := newStatStages()
+	firsts := firstActions{}
+	var pursuit pursuitTracker
+	priority := priorityTracker{}
+	var phazing phazer
+	turnsSeen := 0
+	// The most recent |t:| timestamp, stamped on the next turn
+	var lastTimestamp time.Time
+
+events:
+	for i, line := range lines {
 		if line == "" || !strings.HasPrefix(line, "|") {
 			continue
 		}
 
-		parts := strings.Split(line, "|")
+		parts := fields[i]
 		if len(parts) < 2 {
 			continue
 		}
@@ -94,40 +187,134 @@ func ParseShowdownLog(logContent string) (*BattleSummary, error) {
 		command := parts[1]
 
 		switch command {
+		case "t:":
+			if len(parts) > 2 {
+				lastTimestamp = parseUnixTime(parts[2])
+			}
+
 		case "turn":
+			turnsSeen++
+			if turnsSeen > maxTurns {
+				summary.Warnings = append(summary.Warnings, turnLimitWarning(maxTurns))
+				break events
+			}
+
 			// Save previous turn and start new one
 			if currentTurn != nil {
 				// Calculate position score for the turn
 				currentTurn.PositionScore = tracker.CalculatePositionScore()
+				currentTurn.DoubleSwitch = firsts.doubleSwitch()
+				currentTurn.Weather, currentTurn.Terrain = tracker.EndTurnField()
 				summary.Turns = append(summary.Turns, *currentTurn)
 			}
 			turnNumber = parseInt(parts[2])
+			boundary.startTurn()
+			clear(firsts)
+			pursuit = pursuitTracker{}
+			clear(priority)
+			phazing = phazer{}
+			matchups.reset()
+			destinyBond = ""
+			survival.startAction()
 			currentTurn = &Turn{
 				TurnNumber:  turnNumber,
 				Actions:     []Action{},
 				DamageDealt: make(map[string]int),
 				HealingDone: make(map[string]int),
+				Redirects:   []RedirectEvent{},
+				Timestamp:   lastTimestamp,
+				Residual:    []ResidualEvent{},
+
+				Abilities:           []AbilityActivation{},
+				SuppressedAbilities: []string{},
 			}
+			abilities.startTurn(currentTurn)
 
-		case "switch":
+		case "switch", "drag":
 			if len(parts) >= 4 {
 				action := parseSwitch(parts)
+				action.Boosts, action.BatonPass = stages.switchIn(parts[2])
+				if command == "drag" {
+					phazing.drag(&action)
+				} else {
+					action.PursuitPunish = pursuit.switchOut(boundary, currentTurn, parts)
+					firsts.record(boundary, action)
+				}
 				if currentTurn != nil {
 					currentTurn.Actions = append(currentTurn.Actions, action)
 				}
 				// Update tracker state
 				playerID := extractRawPlayerID(parts[2])
 				pokeName := extractPokemonName(parts[3])
-				pokehp := extractHPFromSwitch(parts)
-				tracker.SwitchPokemon(playerID, pokeName, pokehp)
+				hp, maxHP := extractHPFromSwitch(parts)
+				tracker.SwitchPokemon(playerID, pokeName, hp, maxHP)
+				tracker.RegisterSpecies(parts[2], pokeName)
+				protects.reset(parts[2])
+				abilities.process(currentTurn, parts)
+				if len(parts) > 4 {
+					survival.setHP(parts[2], parts[4])
+				}
+
+				recordBrought(summary.seat(playerID), pokeName)
+
+				// Switches before the first turn are the leads
+				switch {
+				case action.Forced:
+					// Phazed in, not a choice
+				case turnNumber == 0:
+					player := summary.seat(playerID)
+					player.Leads = append(player.Leads, pokeName)
+				case boundary.afterUpkeep():
+					// Switches between |upkeep| and the next |turn| replace fainted Pokémon
+					summary.Stats.ReplacementCount[action.Player]++
+				default:
+					summary.Stats.SwitchCount[action.Player]++
+				}
 			}
 
+		case "upkeep":
+			boundary.upkeep()
+
+		case "swap":
+			stages.swap(parts)
+
 		case "move":
 			if len(parts) >= 4 {
 				action := parseMove(parts)
+				action.Species = tracker.SpeciesFor(parts[2])
+				action.TransformedInto = tracker.TransformedInto(parts[2])
+				action.Move.Type = tracker.attackType(parts[2], action.Move.ID)
+				action.ConsecutiveProtect = protects.move(parts[2], parts[3], turnNumber)
+				action.Source = moveSource(parts)
+				action.Still = isStill(parts)
+				action.PriorityReason = priority.move(parts)
+				action.ParadoxBoost = stages.paradoxStat(parts[2])
+				firsts.record(boundary, action)
+				pursuit.move(currentTurn, parts)
 				if currentTurn != nil {
 					currentTurn.Actions = append(currentTurn.Actions, action)
 				}
+				recordRevealedMove(summary, tracker, parts)
+				stages.move(parts)
+				phazing.move(parts)
+				lastMove = parts
+				clear(hits)
+				matchups.move(parts[2], action.Move.Type)
+				destinyBond = ""
+				survival.startAction()
+			}
+
+		case "-fail":
+			recordProtectFail(summary, protects, currentTurn, lastMove, parts)
+			recordFieldBlock(currentTurn, lastMove, parts)
+
+		case "-message":
+			recordClauseBlock(currentTurn, lastMove, parts)
+
+		case "-transform":
+			// |-transform|p2a: Ditto|p1a: Garchomp|[from] ability: Imposter
+			if len(parts) > 3 {
+				tracker.MarkTransformed(parts[2], tracker.SpeciesFor(parts[3]))
 			}
 
 		case "-damage":
@@ -136,6 +323,30 @@ func ParseShowdownLog(logContent string) (*BattleSummary, error) {
 				hpStr := parts[3]
 				hp, maxHP := parseHP(hpStr)
 				tracker.UpdatePokemonHP(playerID, hp, maxHP)
+				delete(damageSources, pokemonKey(parts[2]))
+				if boundary.endOfTurn(parts) && !isSilent(parts) {
+					recordResidual(currentTurn, parts)
+				}
+				lost := survival.hpOf(parts[2]) - hpPercent(hpStr)
+				recordDamageTaken(summary, tracker, parts[2], lost)
+				if source, ok := passiveDamageSource(parts); ok {
+					recordPassiveDamage(summary, parts[2], source, lost)
+					if of := ofSource(parts); of != "" {
+						recordIndirectDamage(summary, tracker, of, parts[2], lost)
+						damageSources[pokemonKey(parts[2])] = of
+					}
+				} else if user, move, ok := delayed.hit(parts); ok {
+					// Credited like an [of] source, even if the user has left the field
+					markDelayed(currentTurn, tracker, user, move)
+					recordIndirectDamage(summary, tracker, user, parts[2], lost)
+					damageSources[pokemonKey(parts[2])] = user
+				} else if !isSilent(parts) {
+					hits.hit(lastMoveAction(currentTurn), parts[2])
+					matchups.record(summary, parts[2], effectNeutral)
+				}
+				if source, survived := survival.damage(parts[2], hpStr); survived {
+					addSurvivedMoment(summary, turnNumber, parts[2], source)
+				}
 			}
 
 		case "-heal":
@@ -144,14 +355,95 @@ func ParseShowdownLog(logContent string) (*BattleSummary, error) {
 				hpStr := parts[3]
 				hp, maxHP := parseHP(hpStr)
 				tracker.UpdatePokemonHP(playerID, hp, maxHP)
+				survival.setHP(parts[2], hpStr)
+				if boundary.endOfTurn(parts) && !isSilent(parts) {
+					recordResidual(currentTurn, parts)
+				}
+			}
+
+		case "-singleturn":
+			if event, ok := parseRedirect(parts); ok {
+				recordRedirect(currentTurn, event)
+			}
+
+		case "-enditem", "-activate":
+			if event, ok := parseRedirect(parts); ok && command == "-activate" {
+				recordRedirect(currentTurn, event)
+			}
+			if command == "-activate" {
+				recordFieldBlock(currentTurn, lastMove, parts)
+				pursuit.activate(parts)
+				priority.activate(parts)
+				stages.paradoxActivate(parts)
+			} else {
+				recordItemRemoval(summary, turnNumber, parts)
+				phazing.endItem(parts)
+			}
+			if command == "-activate" && len(parts) > 3 && strings.EqualFold(strings.TrimSpace(parts[3]), "move: Destiny Bond") {
+				destinyBond = parts[2]
+			}
+			if len(parts) > 3 {
+				effect := parts[3]
+				if source := survivalSource(effect); source != "" {
+					if survival.activate(parts[2], source) {
+						addSurvivedMoment(summary, turnNumber, parts[2], source)
+					}
+				} else if command == "-activate" && strings.EqualFold(strings.TrimSpace(effect), "move: Substitute") {
+					survival.substituteHit(parts[2])
+				}
+			}
+
+		case "-ability":
+			abilities.process(currentTurn, parts)
+			recordRevealedAbility(summary, tracker, parts)
+
+		case "-start":
+			delayed.start(lastMove, parts)
+			if boost, ok := stages.paradoxStart(parts); ok {
+				recordParadoxBoost(summary, tracker, turnNumber, parts[2], boost)
+			}
+
+		case "-end":
+			abilities.process(currentTurn, parts)
+			stages.paradoxEnd(parts)
+			if delayed.end(parts) {
+				boundary.residual()
+			}
+			if len(parts) > 3 && strings.HasSuffix(strings.TrimSpace(parts[3]), "Substitute") {
+				if survival.substituteBroken(parts[2]) {
+					addSurvivedMoment(summary, turnNumber, parts[2], "move: Substitute")
+				}
 			}
 
 		case "faint":
 			if len(parts) > 2 {
 				playerID := extractRawPlayerID(parts[2])
 				tracker.FaintPokemon(playerID)
+				stages.process(parts)
+				abilities.process(currentTurn, parts)
+				statuses.stop(summary, statusKey(tracker, parts[2]), turnNumber)
+				description := "Pokémon fainted"
+				of := damageSources[pokemonKey(parts[2])]
+				var by, cause string
+				if destinyBond != "" && extractRawPlayerID(destinyBond) != playerID {
+					creditKOTo(summary, tracker, destinyBond)
+					by, cause = destinyBond, koCauseDestinyBond
+					description = fmt.Sprintf("%s was taken down by %s's Destiny Bond", extractNickname(parts[2]), extractNickname(destinyBond))
+				} else if of != "" && extractRawPlayerID(of) != playerID {
+					// Rough Skin, Rocky Helmet, etc. belong to the [of] Pokémon, not the move user
+					creditKOTo(summary, tracker, of)
+					by, cause = of, koCauseIndirect
+				} else if selfFainted(lastMove, parts[2]) {
+					by, cause = parts[2], koCauseSelfDestruct
+				} else if by = creditKO(summary, tracker, lastMove, parts[2]); by != "" {
+					cause = koCauseMove
+				}
+				recordKO(summary, tracker, turnNumber, parts[2], by, cause)
+				if user, ok := pursuit.faint(currentTurn, parts); ok {
+					description = fmt.Sprintf("%s was caught switching out by %s's Pursuit", extractNickname(parts[2]), extractNickname(user))
+				}
 				if currentTurn != nil {
-					addKeyMoment(summary, turnNumber, "KO", "Pokémon fainted", 8)
+					addKeyMoment(summary, turnNumber, "KO", description, 8)
 				}
 			}
 
@@ -160,6 +452,10 @@ func ParseShowdownLog(logContent string) (*BattleSummary, error) {
 			if len(parts) > 3 {
 				tracker.RecordStatChange(parts)
 			}
+			stages.process(parts)
+
+		case "-clearboost", "-clearallboost":
+			stages.process(parts)
 
 		case "-status":
 			// Track status conditions
@@ -167,58 +463,145 @@ func ParseShowdownLog(logContent string) (*BattleSummary, error) {
 				playerID := extractRawPlayerID(parts[2])
 				status := parts[3]
 				tracker.UpdatePokemonStatus(playerID, status)
+				statuses.start(tracker, parts, turnNumber)
+				if boundary.endOfTurn(parts) && !isSilent(parts) {
+					recordResidual(currentTurn, parts)
+				}
 			}
 
+		case "-curestatus":
+			statuses.cure(summary, tracker, parts, turnNumber)
+
 		case "-terastallize":
 			// Track terastallization
-			if len(parts) > 3 {
+			if len(parts) > 3 && mechanicAvailable(summary.Generation, "terastallize") {
 				playerID := extractRawPlayerID(parts[2])
 				teraType := parts[3]
 				tracker.TerastallizePokemon(playerID, teraType)
+				tracker.SetTeraType(parts[2], teraType)
 			}
 
 		case "-sidestart", "-sideend":
 			// Track field effects like Tailwind
 			tracker.RecordFieldEffect(parts)
+			if len(parts) > 3 {
+				side := sideFromRef(parts[2])
+				if command == "-sidestart" {
+					startSideCondition(summary, side, parts[3], turnNumber, lastMove, "")
+				} else {
+					endSideCondition(summary, side, parts[3], turnNumber)
+				}
+			}
+
+		case "-fieldstart":
+			tracker.UpdateField(parts)
+			abilities.process(currentTurn, parts)
+			recordRevealedAbility(summary, tracker, parts)
+			if len(parts) > 2 {
+				startSideCondition(summary, "field", parts[2], turnNumber, lastMove, ofSource(parts))
+				if strings.HasSuffix(strings.TrimSpace(parts[2]), "Terrain") {
+					recordWeatherSetter(summary, "terrain", turnNumber, lastMove, parts)
+				}
+			}
+
+		case "-fieldactivate":
+			if len(parts) > 2 {
+				activateFieldCondition(summary, parts[2], turnNumber, lastMove, ofSource(parts))
+			}
+
+		case "-weather":
+			tracker.UpdateField(parts)
+			abilities.process(currentTurn, parts)
+			recordRevealedAbility(summary, tracker, parts)
+			recordWeatherSetter(summary, "weather", turnNumber, lastMove, parts)
+
+		case "-fieldend":
+			tracker.UpdateField(parts)
+			if len(parts) > 2 {
+				endSideCondition(summary, "field", parts[2], turnNumber)
+			}
+
+		case "-hitcount":
+			recordHitCount(lastMoveAction(currentTurn), parts)
+
+		case "cant":
+			recordLostTurn(summary, tracker, turnNumber, parts)
 
 		case "-crit":
 			summary.Stats.CriticalHits++
 
 		case "-supereffective":
 			summary.Stats.SuperEffective++
+			if len(parts) > 2 {
+				matchups.record(summary, parts[2], effectSuper)
+			}
 
 		case "-resisted":
 			summary.Stats.NotVeryEffective++
+			if len(parts) > 2 {
+				matchups.record(summary, parts[2], effectResisted)
+			}
+
+		case "-immune":
+			if len(parts) > 2 {
+				if abilities.gasActive(parts[2]) {
+					// A suppressed ability can't be what blocked the move
+					parts = withoutAbilitySource(parts)
+				}
+				recordImmunity(summary, currentTurn, lastMove, parts)
+				matchups.record(summary, parts[2], effectImmune)
+			}
 
 		case "win":
 			if len(parts) > 2 {
 				winner := parts[2]
 				summary.Winner = tracker.PlayerToID(winner)
 			}
+
+		case "tie":
+			summary.Draw = true
+			summary.Winner = ""
+
+		case "inactive":
+			recordInactive(summary, turnNumber, parts)
+
+		case "inactiveoff":
+			recordInactiveOff(summary, turnNumber)
 		}
 	}
 
+	statuses.finish(summary, turnNumber)
+
 	// Add the last turn
 	if currentTurn != nil {
 		currentTurn.PositionScore = tracker.CalculatePositionScore()
+		currentTurn.DoubleSwitch = firsts.doubleSwitch()
+		currentTurn.Weather, currentTurn.Terrain = tracker.EndTurnField()
 		summary.Turns = append(summary.Turns, *currentTurn)
 	}
 
-	// Update player losses from tracker
-	summary.Player1.Losses = tracker.losses["p1"]
-	summary.Player2.Losses = tracker.losses["p2"]
-	summary.Player1.TotalLeft = tracker.GetTeamSize("p1") - tracker.losses["p1"]
-	summary.Player2.TotalLeft = tracker.GetTeamSize("p2") - tracker.losses["p2"]
+	// Update player losses and preview order from tracker
+	for _, slot := range summary.slots() {
+		player := summary.Players[slot]
+		player.Losses = tracker.losses[slot]
+		player.TotalLeft = tracker.GetTeamSize(slot) - tracker.losses[slot]
+		finishPreview(player)
+	}
+
+	summary.Warnings = append(summary.Warnings, checkBringCount(summary, tracker)...)
+	summary.ScoreLine = scoreLine(summary)
+	anonymizePlayers(summary, opts)
 
 	// Calculate statistics and turning points
 	calculateStats(summary)
 	detectTurningPoints(summary)
+	detectDoubleSwitchReads(summary)
 
 	// Classify teams
-	summary.Player1.Classification = ClassifyTeam(summary.Player1.Team)
-	summary.Player1.TeamArchetype = summary.Player1.Classification.Archetype
-	summary.Player2.Classification = ClassifyTeam(summary.Player2.Team)
-	summary.Player2.TeamArchetype = summary.Player2.Classification.Archetype
+	for _, player := range summary.allPlayers() {
+		player.Classification = ClassifyTeam(player.Team)
+		player.TeamArchetype = player.Classification.Archetype
+	}
 
 	return summary, nil
 }
@@ -233,6 +616,13 @@ type StateTracker struct {
 	losses             map[string]int            // Fainted pokemon count
 	fieldEffects       map[string][]string       // Side effects like Tailwind
 	statBoosts         map[string]map[string]int // Player->stat->boost level
+	species            map[string]string         // "p1: Nickname" -> species
+	transformed        map[string]string         // "p1: Nickname" -> species it transformed into since switching in
+	teraTypes          map[string]string         // "p1: Nickname" -> tera type, kept for the rest of the battle
+	weather            string                    // Active weather ID, e.g. "sunnyday"; "" when clear
+	terrain            string                    // Active terrain, e.g. "electric terrain"; "" when none
+	weatherTimer       fieldTimer                // Countdown for weather
+	terrainTimer       fieldTimer                // Countdown for terrain
 }
 
 func NewStateTracker() *StateTracker {
@@ -245,6 +635,9 @@ func NewStateTracker() *StateTracker {
 		losses:             make(map[string]int),
 		fieldEffects:       make(map[string][]string),
 		statBoosts:         make(map[string]map[string]int),
+		species:            make(map[string]string),
+		transformed:        make(map[string]string),
+		teraTypes:          make(map[string]string),
 	}
 }
 
@@ -260,6 +653,11 @@ func (st *StateTracker) AddPokemonToTeam(playerID string, poke Pokémon) {
 	st.teams[playerID] = append(st.teams[playerID], poke)
 }
 
+// ClearTeams forgets all team preview entries, as on |clearpoke|.
+func (st *StateTracker) ClearTeams() {
+	st.teams = make(map[string][]Pokémon)
+}
+
 func (st *StateTracker) GetTeam(playerID string) []Pokémon {
 	return st.teams[playerID]
 }
@@ -271,15 +669,20 @@ func (st *StateTracker) GetTeamSize(playerID string) int {
 	return len(st.teams[playerID])
 }
 
-func (st *StateTracker) SwitchPokemon(playerID, pokeName string, hp int) {
+// SwitchPokemon makes pokeName the player's active Pokémon. The switch line
+// reports max HP (100 in percent-display logs), so it replaces the team
+// preview's default.
+func (st *StateTracker) SwitchPokemon(playerID, pokeName string, hp, maxHP int) {
 	team := st.teams[playerID]
 	for i, poke := range team {
 		if poke.Name == pokeName {
 			st.activePokemon[playerID] = &team[i]
 			st.activePokemonIndex[playerID] = i
 			team[i].CurrentHP = hp
-			if team[i].MaxHP == 0 {
-				team[i].MaxHP = 100 // Default to 100 for now
+			if maxHP > 0 {
+				team[i].MaxHP = maxHP
+			} else if team[i].MaxHP == 0 {
+				team[i].MaxHP = 100
 			}
 			break
 		}
@@ -338,13 +741,65 @@ func (st *StateTracker) RecordStatChange(parts []string) {
 	st.statBoosts[playerID][stat] = boost
 }
 
+// RegisterSpecies records the species behind a Pokémon reference such as
+// "p1b: Ursaluna" when it switches in, clears any transformation, and notes
+// a nickname on the matching team preview entry.
+func (st *StateTracker) RegisterSpecies(ref, species string) {
+	key := pokemonKey(ref)
+	st.species[key] = species
+	delete(st.transformed, key)
+	if nickname := extractNickname(ref); nickname != species {
+		team := st.teams[extractRawPlayerID(ref)]
+		for i := range team {
+			if team[i].Name == species {
+				team[i].Nickname = nickname
+			}
+		}
+	}
+}
+
+// SpeciesFor returns the species for a Pokémon reference, falling back to its
+// nickname when the Pokémon was never seen switching in.
+func (st *StateTracker) SpeciesFor(ref string) string {
+	if species, ok := st.species[pokemonKey(ref)]; ok {
+		return species
+	}
+	return extractNickname(ref)
+}
+
+// SetTeraType records that a Pokémon has Terastallized. Terastallization
+// lasts for the rest of the battle, so switching out does not clear it.
+func (st *StateTracker) SetTeraType(ref, teraType string) {
+	st.teraTypes[pokemonKey(ref)] = strings.TrimSpace(teraType)
+}
+
+// TeraTypeFor returns a Pokémon's tera type, or "" if it hasn't Terastallized.
+func (st *StateTracker) TeraTypeFor(ref string) string {
+	return st.teraTypes[pokemonKey(ref)]
+}
+
+// MarkTransformed flags a Pokémon as transformed (Transform/Imposter) into
+// another species until it switches out. Its own species is unchanged.
+func (st *StateTracker) MarkTransformed(ref, into string) {
+	st.transformed[pokemonKey(ref)] = into
+}
+
+// IsTransformed reports whether a Pokémon is currently transformed.
+func (st *StateTracker) IsTransformed(ref string) bool {
+	_, ok := st.transformed[pokemonKey(ref)]
+	return ok
+}
+
+// TransformedInto returns the species a Pokémon is currently transformed
+// into, or "" if it isn't transformed.
+func (st *StateTracker) TransformedInto(ref string) string {
+	return st.transformed[pokemonKey(ref)]
+}
+
 func (st *StateTracker) PlayerToID(playerName string) string {
 	for id, name := range st.playerNames {
-		if name == playerName {
-			if id == "p1" {
-				return "player1"
-			}
-			return "player2"
+		if name == playerName && validSlot(id) {
+			return playerKey(id)
 		}
 	}
 	return "player2"
@@ -424,6 +879,7 @@ func parseMove(parts []string) Action {
 	return Action{
 		Player:     playerID,
 		ActionType: "move",
+		Pokemon:    extractNickname(parts[2]),
 		Move: &Move{
 			ID:   normalizeID(moveName),
 			Name: moveName,
@@ -439,67 +895,184 @@ func parseSwitch(parts []string) Action {
 	return Action{
 		Player:     playerID,
 		ActionType: "switch",
+		Pokemon:    extractNickname(parts[2]),
+		Species:    switchToPoke,
 		SwitchTo:   switchToPoke,
 	}
 }
 
 func extractPlayerIDFromRef(ref string) string {
-	// Convert "p1a: Whimsicott" to "player1" or "p2b: Maushold" to "player2"
-	if strings.HasPrefix(ref, "p1") {
-		return "player1"
-	}
-	return "player2"
+	// Convert "p1a: Whimsicott" to "player1" or "p3b: Maushold" to "player3"
+	return playerKey(extractRawPlayerID(ref))
 }
 
 func extractRawPlayerID(ref string) string {
-	// Convert "p1a: Whimsicott" to "p1" or "p2b: Maushold" to "p2"
-	if strings.HasPrefix(ref, "p1") {
-		return "p1"
+	// Convert "p1a: Whimsicott" to "p1" or "p3b: Maushold" to "p3";
+	// anything unrecognized is treated as p2
+	if len(ref) >= 2 && validSlot(ref[:2]) {
+		return ref[:2]
 	}
 	return "p2"
 }
 
 func extractPokemonName(ref string) string {
 	// From "Typhlosion-Hisui, L50, M" extract "Typhlosion-Hisui"
-	parts := strings.Split(ref, ",")
-	return strings.TrimSpace(parts[0])
+	name, _, _ := strings.Cut(ref, ",")
+	return strings.TrimSpace(name)
+}
+
+func extractNickname(ref string) string {
+	// From "p1a: Whimsicott" extract "Whimsicott"
+	if idx := strings.Index(ref, ": "); idx >= 0 {
+		return strings.TrimSpace(ref[idx+2:])
+	}
+	return strings.TrimSpace(ref)
+}
+
+func pokemonKey(ref string) string {
+	// "p1a: Whimsicott" and "p1b: Whimsicott" both identify "p1: Whimsicott"
+	return extractRawPlayerID(ref) + ": " + extractNickname(ref)
 }
 
-func extractHPFromSwitch(parts []string) int {
-	// From "100\/100" extract 100
+func extractHPFromSwitch(parts []string) (int, int) {
+	// From "100\/100" or "207/207" extract (100, 100) or (207, 207)
 	if len(parts) > 4 {
-		hpStr := parts[4]
-		hpParts := strings.Split(hpStr, "\\/")
-		return parseInt(hpParts[0])
+		return parseHP(parts[4])
 	}
-	return 100
+	return 100, 100
 }
 
+// parseHP splits an HP string into current and max HP: "63\/100" -> (63, 100),
+// "145/207 par" -> (145, 207), "0 fnt" -> (0, 100). Logs with percent HP
+// display report HP out of 100, others report the Pokémon's actual HP, so
+// anything compared across Pokémon or logs should go through hpFraction or
+// hpPercent.
 func parseHP(hpStr string) (int, int) {
-	// "63\/100" -> (63, 100) or "0 fnt" -> (0, maxHP)
-	if strings.Contains(hpStr, "\\/") {
-		parts := strings.Split(hpStr, "\\/")
-		current := parseInt(parts[0])
-		max := parseInt(parts[1])
-		return current, max
-	}
-	// Handle "0 fnt" format
-	if strings.Contains(hpStr, "fnt") {
+	fields := strings.Fields(strings.ReplaceAll(hpStr, "\\/", "/"))
+	if len(fields) == 0 {
 		return 0, 100
 	}
-	return parseInt(hpStr), 100
+	current, max, ok := strings.Cut(fields[0], "/")
+	if !ok {
+		return parseInt(current), 100
+	}
+	return parseInt(current), parseInt(max)
+}
+
+// hpFraction converts an HP string such as "145/207" to the fraction of max
+// HP left, from 0 to 1.
+func hpFraction(hpStr string) float64 {
+	current, max := parseHP(hpStr)
+	if max <= 0 {
+		return 0
+	}
+	return float64(current) / float64(max)
+}
+
+// hpPercent converts an HP string such as "63/100" or "145/207" to a 0-100
+// percentage, rounded down.
+func hpPercent(hpStr string) int {
+	current, max := parseHP(hpStr)
+	if max <= 0 {
+		return 0
+	}
+	return current * 100 / max
 }
 
 func normalizeID(name string) string {
 	return strings.ToLower(strings.ReplaceAll(name, "-", ""))
 }
 
+// parseUnixTime converts a |t:| value (Unix seconds) to a time, or the zero
+// time if it isn't a positive integer.
+func parseUnixTime(s string) time.Time {
+	if secs := parseInt(s); secs > 0 {
+		return time.Unix(int64(secs), 0).UTC()
+	}
+	return time.Time{}
+}
+
+// parsePlayerRating returns the ladder rating from a |player| line, or 0 when
+// the battle is unrated.
+func parsePlayerRating(parts []string) int {
+	if len(parts) > 5 {
+		return parseInt(parts[5])
+	}
+	return 0
+}
+
+// parseInt reads the leading, optionally signed, decimal integer of s,
+// ignoring surrounding space and anything after the digits: "63" and "63/100"
+// are both 63. It returns 0 when s doesn't start with a number or overflows.
 func parseInt(s string) int {
-	var result int
-	_, _ = fmt.Sscanf(strings.TrimSpace(s), "%d", &result)
+	s = strings.TrimSpace(s)
+	negative := false
+	if s != "" && (s[0] == '+' || s[0] == '-') {
+		negative = s[0] == '-'
+		s = s[1:]
+	}
+	digits := 0
+	for digits < len(s) && s[digits] >= '0' && s[digits] <= '9' {
+		digits++
+	}
+	result, err := strconv.Atoi(s[:digits])
+	if err != nil {
+		return 0
+	}
+	if negative {
+		return -result
+	}
 	return result
 }
 
+// recordRevealedMove adds a move to the user's revealed moveset. Moves called
+// by another move or ability (Sleep Talk, Dancer, Magic Bounce, ...) and moves
+// used while transformed are excluded since they aren't part of the moveset.
+func recordRevealedMove(summary *BattleSummary, tracker *StateTracker, parts []string) {
+	ref := parts[2]
+	if tracker.IsTransformed(ref) || isCalledMove(parts) {
+		return
+	}
+
+	moveName := strings.TrimSpace(parts[3])
+	if moveName == "" {
+		return
+	}
+
+	revealed := summary.seat(extractRawPlayerID(ref)).RevealedMoves
+
+	species := tracker.SpeciesFor(ref)
+	if !contains(revealed[species], moveName) {
+		revealed[species] = append(revealed[species], moveName)
+	}
+}
+
+// isCalledMove reports whether a |move| line carries a [from] source other than
+// a lock-in (Outrage, Petal Dance), meaning the move was not chosen from the moveset.
+func isCalledMove(parts []string) bool {
+	return moveSource(parts) != ""
+}
+
+// moveSource returns what made a Pokémon use a move it didn't select, e.g.
+// "Sleep Talk" for |move|p1a: Snorlax|Body Slam|p2a: Dusclops|[from]Sleep Talk
+// or "Magic Bounce" for [from]ability: Magic Bounce. Lock-ins (Outrage, Petal
+// Dance) were chosen on an earlier turn and have no source.
+func moveSource(parts []string) string {
+	if len(parts) < 5 {
+		return ""
+	}
+	for _, part := range parts[4:] {
+		if !strings.HasPrefix(part, "[from]") || part == "[from]lockedmove" {
+			continue
+		}
+		source := strings.TrimSpace(strings.TrimPrefix(part, "[from]"))
+		source = strings.TrimPrefix(source, "ability:")
+		source = strings.TrimPrefix(source, "move:")
+		return strings.TrimSpace(source)
+	}
+	return ""
+}
+
 func addKeyMoment(summary *BattleSummary, turnNumber int, mType, description string, significance int) {
 	summary.KeyMoments = append(summary.KeyMoments, KeyMoment{
 		TurnNumber:   turnNumber,
@@ -581,18 +1154,22 @@ func calculateStats(summary *BattleSummary) {
 	for _, turn := range summary.Turns {
 		for _, action := range turn.Actions {
 			if action.ActionType == "move" && action.Move != nil {
+				if action.Source != "" || action.Still {
+					// Called by another move or ability, or shown without executing
+					continue
+				}
 				summary.Stats.MoveFrequency[action.Move.ID]++
 
 				if action.Player == "player1" {
 					summary.Stats.Player1Stats.MoveCount++
-				} else {
+				} else if action.Player == "player2" {
 					summary.Stats.Player2Stats.MoveCount++
 				}
-			} else if action.ActionType == "switch" {
+			} else if action.ActionType == "switch" && !action.Forced {
 				summary.Stats.Switch++
 				if action.Player == "player1" {
 					summary.Stats.Player1Stats.SwitchCount++
-				} else {
+				} else if action.Player == "player2" {
 					summary.Stats.Player2Stats.SwitchCount++
 				}
 			}
@@ -602,14 +1179,14 @@ func calculateStats(summary *BattleSummary) {
 		for player, damage := range turn.DamageDealt {
 			if player == "player1" {
 				totalDamageDealt1 += damage
-			} else {
+			} else if player == "player2" {
 				totalDamageDealt2 += damage
 			}
 		}
 		for player, healing := range turn.HealingDone {
 			if player == "player1" {
 				totalHealing1 += healing
-			} else {
+			} else if player == "player2" {
 				totalHealing2 += healing
 			}
 		}
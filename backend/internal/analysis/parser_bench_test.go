@@ -0,0 +1,107 @@
+package analysis
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"testing"
+	"time"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite testdata golden files")
+
+const (
+	benchLogPath    = "testdata/vgc_bo3_game1.log"
+	benchGoldenPath = "testdata/vgc_bo3_game1.golden.json"
+)
+
+func readBenchLog(tb testing.TB) string {
+	tb.Helper()
+	log, err := os.ReadFile(benchLogPath)
+	if err != nil {
+		tb.Fatalf("read %s: %v", benchLogPath, err)
+	}
+	return string(log)
+}
+
+// TestParseBenchLogMatchesGolden pins the output of both parsers on the
+// benchmark log, so allocation work can't change what gets parsed. Run with
+// -update after an intended output change.
+func TestParseBenchLogMatchesGolden(t *testing.T) {
+	original := IDGenerator
+	defer func() { IDGenerator = original }()
+	IDGenerator = func() string { return "battle-golden" }
+
+	log := readBenchLog(t)
+	outputs := make(map[string]*BattleSummary)
+	for name, parse := range map[string]func(string) (*BattleSummary, error){
+		"basic":    ParseShowdownLog,
+		"enhanced": ParseEnhancedShowdownLog,
+	} {
+		summary, err := parse(log)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		summary.Timestamp = time.Time{}
+		outputs[name] = summary
+	}
+
+	got, err := json.MarshalIndent(outputs, "", "  ")
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	got = append(got, '\n')
+
+	if *updateGolden {
+		if err := os.WriteFile(benchGoldenPath, got, 0o644); err != nil {
+			t.Fatalf("write %s: %v", benchGoldenPath, err)
+		}
+	}
+
+	want, err := os.ReadFile(benchGoldenPath)
+	if err != nil {
+		t.Fatalf("read %s: %v", benchGoldenPath, err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("parser output differs from %s; rerun with -update if the change is intended", benchGoldenPath)
+	}
+}
+
+func BenchmarkParseShowdownLog(b *testing.B) {
+	log := readBenchLog(b)
+	b.ReportAllocs()
+	b.SetBytes(int64(len(log)))
+	for b.Loop() {
+		if _, err := ParseShowdownLog(log); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParseEnhancedShowdownLog(b *testing.B) {
+	log := readBenchLog(b)
+	b.ReportAllocs()
+	b.SetBytes(int64(len(log)))
+	for b.Loop() {
+		if _, err := ParseEnhancedShowdownLog(log); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkParseShowdownLogParallel parses from every P at once, as the
+// analysis worker pool does.
+func BenchmarkParseShowdownLogParallel(b *testing.B) {
+	log := readBenchLog(b)
+	b.ReportAllocs()
+	b.SetBytes(int64(len(log)))
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := ParseShowdownLog(log); err != nil {
+				b.Error(err)
+				return
+			}
+		}
+	})
+}
@@ -1,6 +1,9 @@
 package analysis
 
-import "testing"
+import (
+	"math"
+	"testing"
+)
 
 // Tests for uncovered functions
 
@@ -12,7 +15,7 @@ func TestUpdatePokemonStatus(t *testing.T) {
 	tracker.AddPokemonToTeam("p1", poke)
 
 	// Set as active
-	tracker.SwitchPokemon("p1", "Pikachu", 100)
+	tracker.SwitchPokemon("p1", "Pikachu", 100, 100)
 
 	// Update status
 	tracker.UpdatePokemonStatus("p1", "par")
@@ -34,7 +37,7 @@ func TestTerastallizePokemon(t *testing.T) {
 	tracker.AddPokemonToTeam("p2", poke)
 
 	// Set as active
-	tracker.SwitchPokemon("p2", "Charizard", 100)
+	tracker.SwitchPokemon("p2", "Charizard", 100, 100)
 
 	// Terastallize
 	tracker.TerastallizePokemon("p2", "Dragon")
@@ -180,6 +183,30 @@ func TestParseHPEdgeCases(t *testing.T) {
 			expectedCur: 0,
 			expectedMax: 100,
 		},
+		{
+			name:        "escaped slash",
+			hpStr:       `63\/150`,
+			expectedCur: 63,
+			expectedMax: 150,
+		},
+		{
+			name:        "absolute HP",
+			hpStr:       "145/207",
+			expectedCur: 145,
+			expectedMax: 207,
+		},
+		{
+			name:        "absolute HP with status",
+			hpStr:       "145/207 brn",
+			expectedCur: 145,
+			expectedMax: 207,
+		},
+		{
+			name:        "escaped slash with status",
+			hpStr:       `20\/100 par`,
+			expectedCur: 20,
+			expectedMax: 100,
+		},
 	}
 
 	for _, tt := range tests {
@@ -197,32 +224,42 @@ func TestParseHPEdgeCases(t *testing.T) {
 
 func TestExtractHPFromSwitchEdgeCases(t *testing.T) {
 	tests := []struct {
-		name     string
-		parts    []string
-		expected int
+		name        string
+		parts       []string
+		expectedCur int
+		expectedMax int
 	}{
 		{
-			name:     "with HP",
-			parts:    []string{"", "switch", "p1a: Pikachu", "Pikachu, L50", "80/100"},
-			expected: 80,
+			name:        "with HP",
+			parts:       []string{"", "switch", "p1a: Pikachu", "Pikachu, L50", "80/100"},
+			expectedCur: 80,
+			expectedMax: 100,
 		},
 		{
-			name:     "without HP (too few parts)",
-			parts:    []string{"", "switch", "p1a: Pikachu", "Pikachu, L50"},
-			expected: 100,
+			name:        "without HP (too few parts)",
+			parts:       []string{"", "switch", "p1a: Pikachu", "Pikachu, L50"},
+			expectedCur: 100,
+			expectedMax: 100,
 		},
 		{
-			name:     "full HP",
-			parts:    []string{"", "switch", "p1a: Pikachu", "Pikachu, L50", "100/100"},
-			expected: 100,
+			name:        "full HP",
+			parts:       []string{"", "switch", "p1a: Pikachu", "Pikachu, L50", "100/100"},
+			expectedCur: 100,
+			expectedMax: 100,
+		},
+		{
+			name:        "absolute HP",
+			parts:       []string{"", "switch", "p1a: Pikachu", "Pikachu, L50", "145/207"},
+			expectedCur: 145,
+			expectedMax: 207,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := extractHPFromSwitch(tt.parts)
-			if result != tt.expected {
-				t.Errorf("expected HP %d, got %d", tt.expected, result)
+			cur, max := extractHPFromSwitch(tt.parts)
+			if cur != tt.expectedCur || max != tt.expectedMax {
+				t.Errorf("expected HP %d/%d, got %d/%d", tt.expectedCur, tt.expectedMax, cur, max)
 			}
 		})
 	}
@@ -238,19 +275,19 @@ func TestSwitchPokemonEdgeCases(t *testing.T) {
 	tracker.AddPokemonToTeam("p1", poke2)
 
 	// Switch to first pokemon
-	tracker.SwitchPokemon("p1", "Pikachu", 80)
+	tracker.SwitchPokemon("p1", "Pikachu", 80, 100)
 	if tracker.activePokemon["p1"].Name != "Pikachu" {
 		t.Errorf("expected Pikachu to be active, got %s", tracker.activePokemon["p1"].Name)
 	}
 
 	// Switch to second pokemon
-	tracker.SwitchPokemon("p1", "Charizard", 90)
+	tracker.SwitchPokemon("p1", "Charizard", 90, 100)
 	if tracker.activePokemon["p1"].Name != "Charizard" {
 		t.Errorf("expected Charizard to be active, got %s", tracker.activePokemon["p1"].Name)
 	}
 
 	// Try to switch to non-existent pokemon (should not panic)
-	tracker.SwitchPokemon("p1", "Blastoise", 100)
+	tracker.SwitchPokemon("p1", "Blastoise", 100, 100)
 }
 
 func TestUpdatePokemonHPEdgeCases(t *testing.T) {
@@ -259,7 +296,7 @@ func TestUpdatePokemonHPEdgeCases(t *testing.T) {
 	// Add pokemon
 	poke := Pokémon{Name: "Pikachu", CurrentHP: 100, MaxHP: 100}
 	tracker.AddPokemonToTeam("p1", poke)
-	tracker.SwitchPokemon("p1", "Pikachu", 100)
+	tracker.SwitchPokemon("p1", "Pikachu", 100, 100)
 
 	// Update HP normally
 	tracker.UpdatePokemonHP("p1", 50, 100)
@@ -297,3 +334,112 @@ func TestGenerateUUIDFormat(t *testing.T) {
 		t.Errorf("UUID has incorrect format: %s", uuid1)
 	}
 }
+
+func TestParseInt(t *testing.T) {
+	tests := map[string]int{
+		"12":                   12,
+		" 7 ":                  7,
+		"-3":                   -3,
+		"+4":                   4,
+		"63/100":               63,
+		"45 par":               45,
+		"":                     0,
+		"fnt":                  0,
+		"-":                    0,
+		"99999999999999999999": 0,
+	}
+
+	for input, expected := range tests {
+		if got := parseInt(input); got != expected {
+			t.Errorf("parseInt(%q): expected %d, got %d", input, expected, got)
+		}
+	}
+}
+
+func TestHPFraction(t *testing.T) {
+	tests := []struct {
+		hpStr string
+		want  float64
+	}{
+		{"65/100", 0.65},
+		{`63\/100`, 0.63},
+		{"145/207", 145.0 / 207.0},
+		{"145/207 par", 145.0 / 207.0},
+		{"0 fnt", 0},
+		{"5/0", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.hpStr, func(t *testing.T) {
+			if got := hpFraction(tt.hpStr); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("hpFraction(%q) = %v, want %v", tt.hpStr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseShowdownLogAbsoluteHP(t *testing.T) {
+	log := `|player|p1|Player1|test|1500
+|player|p2|Player2|test|1500
+|poke|p1|Garchomp, L50, M|
+|poke|p2|Whimsicott, L50, F|
+|start
+|switch|p1a: Garchomp|Garchomp, L50, M|183/183
+|switch|p2a: Whimsicott|Whimsicott, L50, F|207/207
+|turn|1
+|move|p1a: Garchomp|Dragon Claw|p2a: Whimsicott
+|-damage|p2a: Whimsicott|145/207
+|upkeep
+|win|Player1`
+
+	if got := hpFraction("145/207"); math.Abs(got-0.70) > 0.01 {
+		t.Fatalf("expected fraction ~0.70, got %v", got)
+	}
+
+	summary, err := ParseShowdownLog(log)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := summary.Stats.DamageTakenByPokemon["p2: Whimsicott"]; got != 30 {
+		t.Errorf("expected Whimsicott to lose 30%% HP, got %v", got)
+	}
+
+	enhanced, err := ParseEnhancedShowdownLog(log)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	impact := enhanced.Turns[0].Actions[0].Impact
+	if impact == nil || impact.DamageDealt != 30 {
+		t.Errorf("expected Dragon Claw to deal 30%%, got %+v", impact)
+	}
+}
+
+func TestParseShowdownLogTruncatedImmune(t *testing.T) {
+	log := `|player|p1|Player1|test|1500
+|player|p2|Player2|test|1500
+|poke|p1|Garchomp, L50, M|
+|poke|p2|Corviknight, L50, F|
+|start
+|switch|p1a: Garchomp|Garchomp, L50, M|100/100
+|switch|p2a: Corviknight|Corviknight, L50, F|100/100
+|turn|1
+|move|p1a: Garchomp|Earthquake|p2a: Corviknight
+|-immune
+|upkeep
+|win|Player1`
+
+	for name, parse := range map[string]func(string) (*BattleSummary, error){
+		"basic":    ParseShowdownLog,
+		"enhanced": ParseEnhancedShowdownLog,
+	} {
+		t.Run(name, func(t *testing.T) {
+			summary, err := parse(log)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if summary.Winner != "player1" {
+				t.Errorf("expected the rest of the log to parse, got winner %q", summary.Winner)
+			}
+		})
+	}
+}
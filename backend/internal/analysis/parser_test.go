@@ -3,6 +3,7 @@ package analysis
 import (
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestParseShowdownLogBasicValid(t *testing.T) {
@@ -313,6 +314,25 @@ func TestParseShowdownLogUUIDUniqueness(t *testing.T) {
 	}
 }
 
+func TestParseShowdownLogIDGenerator(t *testing.T) {
+	original := IDGenerator
+	defer func() { IDGenerator = original }()
+	IDGenerator = func() string { return "battle-fixed" }
+
+	for name, parse := range map[string]func(string) (*BattleSummary, error){
+		"basic":    ParseShowdownLog,
+		"enhanced": ParseEnhancedShowdownLog,
+	} {
+		summary, err := parse(sampleBattleLog())
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		if summary.ID != "battle-fixed" {
+			t.Errorf("%s: expected the stubbed ID, got %q", name, summary.ID)
+		}
+	}
+}
+
 // Test fixtures
 
 func sampleBattleLog() string {
@@ -666,3 +686,527 @@ func TestParseShowdownLogMultipleFaints(t *testing.T) {
 		t.Errorf("expected player1 to have 1 loss, got %d", summary.Player1.Losses)
 	}
 }
+
+func TestParseShowdownLogRules(t *testing.T) {
+	summary, _ := ParseShowdownLog(sampleBattleLog())
+
+	expected := []string{
+		"Species Clause: Limit one of each Pokémon",
+		"Item Clause: Limit 1 of each item",
+	}
+	if len(summary.Rules) != len(expected) {
+		t.Fatalf("expected %d rules, got %d: %v", len(expected), len(summary.Rules), summary.Rules)
+	}
+	for i, rule := range expected {
+		if summary.Rules[i] != rule {
+			t.Errorf("rule %d: expected %q, got %q", i, rule, summary.Rules[i])
+		}
+	}
+
+	if !summary.HasClause("Species Clause") {
+		t.Error("expected HasClause to report Species Clause")
+	}
+	if !summary.HasClause("item clause") {
+		t.Error("expected HasClause to be case-insensitive")
+	}
+	if summary.HasClause("Sleep Clause") {
+		t.Error("expected HasClause to be false for a rule not in the log")
+	}
+}
+
+func TestParseShowdownLogRevealedMoves(t *testing.T) {
+	summary, _ := ParseShowdownLog(sampleBattleLog())
+
+	pikachu := summary.Player1.RevealedMoves["Pikachu"]
+	expected := []string{"Thunderbolt", "Thunder Wave", "Quick Attack"}
+	if len(pikachu) != len(expected) {
+		t.Fatalf("expected Pikachu revealed moves %v, got %v", expected, pikachu)
+	}
+	for i, move := range expected {
+		if pikachu[i] != move {
+			t.Errorf("move %d: expected %q, got %q", i, move, pikachu[i])
+		}
+	}
+
+	// Waterfall is used twice but should only be revealed once
+	blastoise := summary.Player2.RevealedMoves["Blastoise"]
+	count := 0
+	for _, move := range blastoise {
+		if move == "Waterfall" {
+			count++
+		}
+	}
+	if count != 1 {
+		t.Errorf("expected Waterfall to appear once for Blastoise, got %d in %v", count, blastoise)
+	}
+}
+
+func TestParseShowdownLogRevealedMovesKeyedBySpecies(t *testing.T) {
+	log := `|player|p1|Player1|test|1500
+|player|p2|Player2|test|1500
+|poke|p1|Ursaluna-Bloodmoon, L50, M|
+|poke|p2|Ditto, L50|
+|poke|p2|Oricorio, L50, F|
+|start
+|switch|p1a: Ursaluna|Ursaluna-Bloodmoon, L50, M|100/100
+|switch|p2a: Ditto|Ditto, L50|100/100
+|switch|p2b: Oricorio|Oricorio, L50, F|100/100
+|-transform|p2a: Ditto|p1a: Ursaluna|[from] ability: Imposter
+|turn|1
+|move|p1a: Ursaluna|Blood Moon|p2a: Ditto
+|move|p2a: Ditto|Blood Moon|p1a: Ursaluna
+|move|p2b: Oricorio|Quiver Dance|p2b: Oricorio
+|move|p1a: Ursaluna|Hyper Voice|p2a: Ditto|[from]move: Sleep Talk
+|upkeep
+|win|Player1`
+
+	summary, _ := ParseShowdownLog(log)
+
+	ursaluna := summary.Player1.RevealedMoves["Ursaluna-Bloodmoon"]
+	if len(ursaluna) != 1 || ursaluna[0] != "Blood Moon" {
+		t.Errorf("expected Ursaluna-Bloodmoon to reveal only Blood Moon, got %v", ursaluna)
+	}
+	if _, ok := summary.Player1.RevealedMoves["Ursaluna"]; ok {
+		t.Error("expected revealed moves to be keyed by species, not nickname")
+	}
+	if moves, ok := summary.Player2.RevealedMoves["Ditto"]; ok {
+		t.Errorf("expected moves used while transformed to be excluded, got %v", moves)
+	}
+	if moves := summary.Player2.RevealedMoves["Oricorio"]; len(moves) != 1 {
+		t.Errorf("expected Oricorio to reveal Quiver Dance, got %v", moves)
+	}
+}
+
+func TestParseShowdownLogTransformKeepsIdentity(t *testing.T) {
+	log := `|player|p1|Player1|test|1500
+|player|p2|Player2|test|1500
+|poke|p1|Tauros-Paldea-Aqua, L50, M|
+|poke|p1|Amoonguss, L50, F|
+|poke|p2|Ditto, L50|
+|poke|p2|Incineroar, L50, M|
+|start
+|switch|p1a: Tauros|Tauros-Paldea-Aqua, L50, M|100/100
+|switch|p2a: Ditto|Ditto, L50|100/100
+|-transform|p2a: Ditto|p1a: Tauros|[from] ability: Imposter
+|turn|1
+|move|p2a: Ditto|Raging Bull|p1a: Tauros
+|-resisted|p1a: Tauros
+|-damage|p1a: Tauros|70/100
+|move|p1a: Tauros|Close Combat|p2a: Ditto
+|-damage|p2a: Ditto|0 fnt
+|faint|p2a: Ditto
+|upkeep
+|switch|p2a: Incineroar|Incineroar, L50, M|100/100
+|turn|2
+|move|p2a: Incineroar|Fake Out|p1a: Tauros
+|-damage|p1a: Tauros|60/100
+|upkeep
+|win|Player1`
+
+	for name, parse := range map[string]func(string) (*BattleSummary, error){
+		"basic":    ParseShowdownLog,
+		"enhanced": ParseEnhancedShowdownLog,
+	} {
+		summary, err := parse(log)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+
+		if team := summary.Player2.Team; len(team) != 2 || team[0].Name != "Ditto" {
+			t.Errorf("%s: expected Ditto to stay Ditto on the team, got %+v", name, team)
+		}
+
+		// Ditto's move shows the copy: Tauros-Paldea-Aqua's Water-type Raging Bull
+		copied := summary.Turns[0].Actions[0]
+		if copied.Species != "Ditto" || copied.TransformedInto != "Tauros-Paldea-Aqua" {
+			t.Errorf("%s: expected Ditto transformed into Tauros-Paldea-Aqua, got %q into %q", name, copied.Species, copied.TransformedInto)
+		}
+		if copied.Move.Type != "Water" {
+			t.Errorf("%s: expected the copied Raging Bull to be Water type, got %q", name, copied.Move.Type)
+		}
+
+		if moves, ok := summary.Player2.RevealedMoves["Ditto"]; ok {
+			t.Errorf("%s: expected copied moves not credited to Ditto, got %v", name, moves)
+		}
+		if moves := summary.Player1.RevealedMoves["Tauros-Paldea-Aqua"]; len(moves) != 1 || moves[0] != "Close Combat" {
+			t.Errorf("%s: expected Tauros to reveal only its own Close Combat, got %v", name, moves)
+		}
+
+		// The switch-in replacing Ditto isn't transformed
+		if next := summary.Turns[1].Actions[len(summary.Turns[1].Actions)-1]; next.TransformedInto != "" {
+			t.Errorf("%s: expected Incineroar untransformed, got %+v", name, next)
+		}
+	}
+}
+
+func TestParseShowdownLogNicknames(t *testing.T) {
+	log := `|player|p1|Player1|test|1500
+|player|p2|Player2|test|1500
+|poke|p1|Pikachu, L50, M|
+|poke|p2|Garchomp, L50, F|
+|start
+|switch|p1a: Sparky|Pikachu, L50, M|100/100
+|switch|p2a: Garchomp|Garchomp, L50, F|100/100
+|turn|1
+|move|p1a: Sparky|Thunderbolt|p2a: Garchomp
+|-immune|p2a: Garchomp
+|move|p2a: Garchomp|Earthquake|p1a: Sparky
+|-supereffective|p1a: Sparky
+|-damage|p1a: Sparky|0 fnt
+|faint|p1a: Sparky
+|upkeep
+|win|Player2`
+
+	for name, parse := range map[string]func(string) (*BattleSummary, error){
+		"basic":    ParseShowdownLog,
+		"enhanced": ParseEnhancedShowdownLog,
+	} {
+		summary, err := parse(log)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+
+		move := summary.Turns[0].Actions[0]
+		if move.Pokemon != "Sparky" || move.Species != "Pikachu" {
+			t.Errorf("%s: expected Sparky (Pikachu), got %q (%q)", name, move.Pokemon, move.Species)
+		}
+		if _, ok := summary.Stats.DamageTakenByPokemon["p1: Pikachu"]; !ok {
+			t.Errorf("%s: expected damage taken keyed by species, got %v", name, summary.Stats.DamageTakenByPokemon)
+		}
+		if moves := summary.Player1.RevealedMoves["Pikachu"]; len(moves) != 1 {
+			t.Errorf("%s: expected Pikachu to reveal Thunderbolt, got %v", name, summary.Player1.RevealedMoves)
+		}
+
+		team := summary.Player1.Team
+		if len(team) != 1 || team[0].Name != "Pikachu" || team[0].Nickname != "Sparky" {
+			t.Errorf("%s: expected team entry Pikachu nicknamed Sparky, got %+v", name, team)
+		}
+		if nickname := summary.Player2.Team[0].Nickname; nickname != "" {
+			t.Errorf("%s: expected no nickname for an unnamed Garchomp, got %q", name, nickname)
+		}
+	}
+}
+
+func TestParseShowdownLogCalledMoveSource(t *testing.T) {
+	log := `|player|p1|Player1|test|1500
+|player|p2|Player2|test|1500
+|start
+|switch|p1a: Snorlax|Snorlax, L50, M|100/100
+|switch|p2a: Hatterene|Hatterene, L50, F|100/100
+|switch|p2b: Oricorio|Oricorio, L50, F|100/100
+|turn|1
+|move|p1a: Snorlax|Sleep Talk|p1a: Snorlax
+|move|p1a: Snorlax|Body Slam|p2a: Hatterene|[from]Sleep Talk
+|move|p2b: Oricorio|Quiver Dance|p2b: Oricorio
+|move|p2b: Oricorio|Body Slam|p1a: Snorlax|[from]ability: Dancer
+|upkeep
+|win|Player1`
+
+	for name, parse := range map[string]func(string) (*BattleSummary, error){
+		"basic":    ParseShowdownLog,
+		"enhanced": ParseEnhancedShowdownLog,
+	} {
+		t.Run(name, func(t *testing.T) {
+			summary, err := parse(log)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var sources []string
+			for _, action := range summary.Turns[0].Actions {
+				if action.ActionType == "move" {
+					sources = append(sources, action.Source)
+				}
+			}
+			want := []string{"", "Sleep Talk", "", "Dancer"}
+			if strings.Join(sources, ",") != strings.Join(want, ",") {
+				t.Errorf("expected move sources %q, got %q", want, sources)
+			}
+
+			// Only the selected moves count as usage
+			if got := summary.Stats.MoveFrequency["body slam"]; got != 0 {
+				t.Errorf("expected called Body Slam not to be counted, got %d", got)
+			}
+			if got := summary.Stats.MoveFrequency["sleep talk"]; got != 1 {
+				t.Errorf("expected Sleep Talk counted once, got %d", got)
+			}
+			if got := summary.Stats.Player1Stats.MoveCount; got != 1 {
+				t.Errorf("expected player1 to have selected 1 move, got %d", got)
+			}
+		})
+	}
+}
+
+func TestParseShowdownLogSwitchAndReplacementCounts(t *testing.T) {
+	summary, _ := ParseShowdownLog(sampleBattleLog())
+
+	// Turn 3's switch to Charizard is voluntary; Pikachu coming back after
+	// Charizard faints is a replacement. The opening leads count as neither.
+	if got := summary.Stats.SwitchCount["player1"]; got != 1 {
+		t.Errorf("expected player1 to have 1 voluntary switch, got %d", got)
+	}
+	if got := summary.Stats.ReplacementCount["player1"]; got != 1 {
+		t.Errorf("expected player1 to have 1 replacement, got %d", got)
+	}
+	if got := summary.Stats.SwitchCount["player2"]; got != 0 {
+		t.Errorf("expected player2 to have 0 voluntary switches, got %d", got)
+	}
+	if got := summary.Stats.ReplacementCount["player2"]; got != 0 {
+		t.Errorf("expected player2 to have 0 replacements, got %d", got)
+	}
+}
+
+func TestParseShowdownLogGeneration(t *testing.T) {
+	summary, _ := ParseShowdownLog(sampleBattleLog())
+	if summary.Generation != 9 {
+		t.Errorf("expected generation 9, got %d", summary.Generation)
+	}
+
+	summary, _ = ParseShowdownLog(minimalBattleLog())
+	if summary.Generation != 0 {
+		t.Errorf("expected generation 0 when |gen| is absent, got %d", summary.Generation)
+	}
+}
+
+func TestMechanicAvailable(t *testing.T) {
+	tests := []struct {
+		generation int
+		mechanic   string
+		expected   bool
+	}{
+		{9, "terastallize", true},
+		{8, "terastallize", false},
+		{8, "dynamax", true},
+		{9, "dynamax", false},
+		{0, "terastallize", true},
+		{9, "unknown", true},
+	}
+
+	for _, tt := range tests {
+		if got := mechanicAvailable(tt.generation, tt.mechanic); got != tt.expected {
+			t.Errorf("mechanicAvailable(%d, %q): expected %v, got %v", tt.generation, tt.mechanic, tt.expected, got)
+		}
+	}
+}
+
+func TestParseShowdownLogClearpokeResetsPreview(t *testing.T) {
+	log := `|player|p1|Alice|1|
+|player|p2|Bob|2|
+|clearpoke
+|poke|p1|Pikachu, L50, M|
+|poke|p1|Charizard, L50, M|
+|poke|p2|Blastoise, L50, M|
+|clearpoke
+|poke|p1|Pikachu, L50, M|
+|poke|p1|Gengar, L50, M|
+|poke|p2|Blastoise, L50, M|
+|poke|p2|Dragonite, L50, M|
+|teampreview
+|start
+|turn|1
+|win|Alice
+`
+	for name, parse := range map[string]func(string) (*BattleSummary, error){
+		"basic":    ParseShowdownLog,
+		"enhanced": ParseEnhancedShowdownLog,
+	} {
+		summary, err := parse(log)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+
+		var p1 []string
+		for _, poke := range summary.Player1.Team {
+			p1 = append(p1, poke.Name)
+		}
+		if len(p1) != 2 || p1[0] != "Pikachu" || p1[1] != "Gengar" {
+			t.Errorf("%s: expected player1 preview [Pikachu Gengar], got %v", name, p1)
+		}
+		if len(summary.Player2.Team) != 2 {
+			t.Errorf("%s: expected 2 Pokémon in player2's preview, got %d", name, len(summary.Player2.Team))
+		}
+	}
+}
+
+func TestParseShowdownLogTie(t *testing.T) {
+	log := `|player|p1|Alice|1|
+|player|p2|Bob|2|
+|start
+|switch|p1a: Electrode|Electrode, L50|100/100
+|switch|p2a: Gengar|Gengar, L50, M|100/100
+|turn|1
+|move|p2a: Gengar|Destiny Bond|p2a: Gengar
+|-singlemove|p2a: Gengar|Destiny Bond
+|move|p1a: Electrode|Shadow Ball|p2a: Gengar
+|-damage|p2a: Gengar|0 fnt
+|-activate|p2a: Gengar|move: Destiny Bond
+|faint|p2a: Gengar
+|faint|p1a: Electrode
+|
+|tie
+`
+	summary, err := ParseShowdownLog(log)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !summary.Draw {
+		t.Error("expected Draw to be true")
+	}
+	if summary.Winner != "" {
+		t.Errorf("expected empty winner, got %q", summary.Winner)
+	}
+	if summary.ScoreLine != "0-0" {
+		t.Errorf("expected score line 0-0 for a mutual faint, got %q", summary.ScoreLine)
+	}
+
+	if summary.Player1.KOs["Electrode"] != 1 {
+		t.Errorf("expected Electrode credited with Gengar's KO, got %v", summary.Player1.KOs)
+	}
+	if summary.Player2.KOs["Gengar"] != 1 {
+		t.Errorf("expected Gengar credited with Electrode's KO via Destiny Bond, got %v", summary.Player2.KOs)
+	}
+
+	found := false
+	for _, m := range summary.KeyMoments {
+		if m.Description == "Electrode was taken down by Gengar's Destiny Bond" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected a Destiny Bond KO moment, got %+v", summary.KeyMoments)
+	}
+}
+
+func TestParseShowdownLogTurnTimestamps(t *testing.T) {
+	log := `|player|p1|Alice|1|
+|player|p2|Bob|2|
+|start
+|switch|p1a: Pikachu|Pikachu, L50, M|100/100
+|switch|p2a: Blastoise|Blastoise, L50, M|100/100
+|t:|1700000000
+|turn|1
+|move|p1a: Pikachu|Thunderbolt|p2a: Blastoise
+|-damage|p2a: Blastoise|50/100
+|upkeep
+|t:|1700000045
+|turn|2
+|move|p1a: Pikachu|Thunderbolt|p2a: Blastoise
+|-damage|p2a: Blastoise|0 fnt
+|faint|p2a: Blastoise
+|win|Alice
+`
+	for name, parse := range map[string]func(string) (*BattleSummary, error){
+		"basic":    ParseShowdownLog,
+		"enhanced": ParseEnhancedShowdownLog,
+	} {
+		summary, err := parse(log)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		if len(summary.Turns) != 2 {
+			t.Fatalf("%s: expected 2 turns, got %d", name, len(summary.Turns))
+		}
+
+		if got := summary.Turns[0].Timestamp.Unix(); got != 1700000000 {
+			t.Errorf("%s: expected turn 1 at 1700000000, got %d", name, got)
+		}
+		if got := summary.Turns[1].Timestamp.Sub(summary.Turns[0].Timestamp); got != 45*time.Second {
+			t.Errorf("%s: expected 45s between turns, got %v", name, got)
+		}
+	}
+
+	summary, _ := ParseShowdownLog(minimalBattleLog())
+	for _, turn := range summary.Turns {
+		if !turn.Timestamp.IsZero() {
+			t.Errorf("expected zero timestamp without |t:| lines, got %v", turn.Timestamp)
+		}
+	}
+}
+
+func TestParseShowdownLogBringCount(t *testing.T) {
+	summary, err := ParseShowdownLog(sampleBattleLog())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if summary.BringCount != 2 {
+		t.Errorf("expected bring count 2, got %d", summary.BringCount)
+	}
+	if summary.PreviewSize != 2 {
+		t.Errorf("expected preview size 2, got %d", summary.PreviewSize)
+	}
+	if len(summary.Warnings) != 0 {
+		t.Errorf("expected no warnings, got %v", summary.Warnings)
+	}
+
+	// Three different Pokémon switching in breaks a bring-2 limit
+	log := `|player|p1|Alice|1|
+|player|p2|Bob|2|
+|poke|p1|Pikachu, L50, M|
+|poke|p1|Charizard, L50, M|
+|poke|p1|Gengar, L50, M|
+|poke|p2|Blastoise, L50, M|
+|teampreview|2
+|teamsize|p1|2
+|teamsize|p2|1
+|start
+|switch|p1a: Pikachu|Pikachu, L50, M|100/100
+|switch|p2a: Blastoise|Blastoise, L50, M|100/100
+|turn|1
+|switch|p1a: Charizard|Charizard, L50, M|100/100
+|turn|2
+|switch|p1a: Gengar|Gengar, L50, M|100/100
+|win|Alice
+`
+	summary, err = ParseShowdownLog(log)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if summary.PreviewSize != 3 {
+		t.Errorf("expected preview size 3, got %d", summary.PreviewSize)
+	}
+	if len(summary.Warnings) != 1 || !strings.Contains(summary.Warnings[0], "p1 used 3") {
+		t.Errorf("expected a warning for p1 using 3 Pokémon, got %v", summary.Warnings)
+	}
+}
+
+func TestParseShowdownLogScoreLine(t *testing.T) {
+	summary, err := ParseShowdownLog(sampleBattleLog())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Player2 won without losing either Pokémon
+	if summary.ScoreLine != "2-0" {
+		t.Errorf("expected score line 2-0, got %q", summary.ScoreLine)
+	}
+
+	// Winner-first even when player1 wins, and unfinished battles have none
+	summary, err = ParseShowdownLog(`|player|p1|Alice|1|
+|player|p2|Bob|2|
+|teampreview|4
+|teamsize|p1|4
+|teamsize|p2|4
+|start
+|turn|1
+|faint|p1a: Gengar
+|faint|p2a: Blastoise
+|faint|p2a: Snorlax
+|faint|p2a: Pikachu
+|faint|p2a: Charizard
+|win|Alice
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if summary.ScoreLine != "3-0" {
+		t.Errorf("expected score line 3-0, got %q", summary.ScoreLine)
+	}
+
+	summary, err = ParseShowdownLog(minimalBattleLog()[:strings.Index(minimalBattleLog(), "|win|")])
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if summary.ScoreLine != "" {
+		t.Errorf("expected no score line for an unfinished battle, got %q", summary.ScoreLine)
+	}
+}
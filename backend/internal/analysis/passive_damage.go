@@ -0,0 +1,72 @@
+package analysis
+
+import "strings"
+
+// passiveDamageSource returns the [from] source of a -damage line, e.g. "psn"
+// or "item: Life Orb". ok is false for direct move damage.
+func passiveDamageSource(parts []string) (source string, ok bool) {
+	if len(parts) < 5 {
+		return "", false
+	}
+	for _, part := range parts[4:] {
+		if strings.HasPrefix(part, "[from]") {
+			return strings.TrimSpace(strings.TrimPrefix(part, "[from]")), true
+		}
+	}
+	return "", false
+}
+
+// passiveDamageCategory classifies a [from] damage source as "weather",
+// "status", "hazard", "item", "ability", "recoil", or "other".
+func passiveDamageCategory(source string) string {
+	lower := strings.ToLower(source)
+	switch {
+	case strings.HasPrefix(lower, "item:"):
+		return "item"
+	case strings.HasPrefix(lower, "ability:"):
+		return "ability"
+	}
+
+	switch strings.TrimPrefix(lower, "move: ") {
+	case "sandstorm", "hail":
+		return "weather"
+	case "psn", "tox", "brn", "confusion", "leech seed", "curse", "nightmare", "salt cure", "partiallytrapped":
+		return "status"
+	case "stealth rock", "spikes", "g-max steelsurge":
+		return "hazard"
+	case "recoil", "mindblown", "steelbeam", "highjumpkick", "jumpkick":
+		return "recoil"
+	}
+	return "other"
+}
+
+// recordPassiveDamage adds HP lost to a non-move source to the victim's total.
+func recordPassiveDamage(summary *BattleSummary, ref, source string, lost int) {
+	if lost <= 0 {
+		return
+	}
+	player := extractPlayerIDFromRef(ref)
+	summary.Stats.PassiveDamage[player] += float64(lost)
+	summary.Stats.PassiveDamageBySource[passiveDamageCategory(source)] += float64(lost)
+}
+
+// recordDamageTaken adds HP% a Pokémon lost from any source. Totals are keyed
+// by side and species ("p2: Blastoise"), not nickname or field position, so a
+// Pokémon switching out and back in keeps one running total.
+func recordDamageTaken(summary *BattleSummary, tracker *StateTracker, ref string, lost int) {
+	if lost <= 0 {
+		return
+	}
+	key := extractRawPlayerID(ref) + ": " + tracker.SpeciesFor(ref)
+	summary.Stats.DamageTakenByPokemon[key] += float64(lost)
+}
+
+// recordIndirectDamage credits passive damage to the [of] Pokémon that caused
+// it (Rough Skin, Iron Barbs, Rocky Helmet) when it's on the opposing side.
+func recordIndirectDamage(summary *BattleSummary, tracker *StateTracker, of, victim string, lost int) {
+	if lost <= 0 || extractRawPlayerID(of) == extractRawPlayerID(victim) {
+		return
+	}
+	dealt := summary.seat(extractRawPlayerID(of)).IndirectDamage
+	dealt[tracker.SpeciesFor(of)] += float64(lost)
+}
@@ -0,0 +1,131 @@
+package analysis
+
+import "testing"
+
+func TestParseShowdownLogPassiveDamage(t *testing.T) {
+	log := `|player|p1|Alice|1|
+|player|p2|Bob|2|
+|start
+|switch|p1a: Amoonguss|Amoonguss, L50, F|100/100
+|switch|p2a: Incineroar|Incineroar, L50, M|100/100
+|turn|1
+|move|p1a: Amoonguss|Sludge Bomb|p2a: Incineroar
+|-damage|p2a: Incineroar|60/100
+|-status|p2a: Incineroar|psn
+|upkeep
+|-weather|Sandstorm|[upkeep]
+|-damage|p2a: Incineroar|54/100 psn|[from] Sandstorm
+|-damage|p2a: Incineroar|42/100 psn|[from] psn
+|-damage|p1a: Amoonguss|94/100|[from] Sandstorm
+|turn|2
+|win|Alice
+`
+	summary, err := ParseShowdownLog(log)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := summary.Stats.PassiveDamage["player2"]; got != 18 {
+		t.Errorf("expected player2 to lose 18%% to passive damage, got %v", got)
+	}
+	if got := summary.Stats.PassiveDamage["player1"]; got != 6 {
+		t.Errorf("expected player1 to lose 6%% to passive damage, got %v", got)
+	}
+	if got := summary.Stats.PassiveDamageBySource["status"]; got != 12 {
+		t.Errorf("expected 12%% poison damage classified as status, got %v", got)
+	}
+	if got := summary.Stats.PassiveDamageBySource["weather"]; got != 12 {
+		t.Errorf("expected 12%% sandstorm damage classified as weather, got %v", got)
+	}
+}
+
+func TestPassiveDamageCategory(t *testing.T) {
+	tests := []struct {
+		source   string
+		expected string
+	}{
+		{"psn", "status"},
+		{"Sandstorm", "weather"},
+		{"Stealth Rock", "hazard"},
+		{"item: Life Orb", "item"},
+		{"ability: Rough Skin", "ability"},
+		{"Recoil", "recoil"},
+		{"Something New", "other"},
+	}
+
+	for _, tt := range tests {
+		if got := passiveDamageCategory(tt.source); got != tt.expected {
+			t.Errorf("passiveDamageCategory(%q): expected %q, got %q", tt.source, tt.expected, got)
+		}
+	}
+}
+
+func TestEnhanceActionIgnoresPassiveDamage(t *testing.T) {
+	action := &Action{}
+	EnhanceActionWithImpact(action, "Sludge Bomb", []string{
+		"|-damage|p2a: Incineroar|60/100",
+		"|-damage|p1a: Amoonguss|90/100|[from] item: Life Orb",
+	})
+
+	if action.Impact.DamageDealt != 40 {
+		t.Errorf("expected only the move's 40 damage, got %d", action.Impact.DamageDealt)
+	}
+}
+
+func TestParseShowdownLogOfSourceAttribution(t *testing.T) {
+	log := `|player|p1|Alice|1|
+|player|p2|Bob|2|
+|start
+|switch|p1a: Urshifu|Urshifu-Rapid-Strike, L50, M|100/100
+|switch|p2a: Garchomp|Garchomp, L50, M|100/100
+|turn|1
+|move|p1a: Urshifu|Close Combat|p2a: Garchomp
+|-damage|p2a: Garchomp|40/100
+|-damage|p1a: Urshifu|88/100|[from] ability: Rough Skin|[of] p2a: Garchomp
+|upkeep
+|turn|2
+|move|p1a: Urshifu|Close Combat|p2a: Garchomp
+|-damage|p2a: Garchomp|1/100
+|-damage|p1a: Urshifu|0 fnt|[from] ability: Rough Skin|[of] p2a: Garchomp
+|faint|p1a: Urshifu
+|upkeep
+|win|Bob
+`
+	summary, err := ParseShowdownLog(log)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := summary.Player2.IndirectDamage["Garchomp"]; got != 100 {
+		t.Errorf("expected Garchomp credited with 100%% Rough Skin damage, got %v", got)
+	}
+	if summary.Player2.KOs["Garchomp"] != 1 {
+		t.Errorf("expected Garchomp credited with the Rough Skin KO, got %v", summary.Player2.KOs)
+	}
+	if len(summary.Player1.KOs) != 0 {
+		t.Errorf("expected no KOs for Urshifu, got %v", summary.Player1.KOs)
+	}
+}
+
+func TestParseShowdownLogDamageTakenByPokemon(t *testing.T) {
+	summary, err := ParseShowdownLog(sampleBattleLog())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Blastoise: 100 -> 65 -> 60 -> 30 -> 20 over turns 1, 2, 4 and 5
+	// Pikachu keeps one total across switching out at 30% and back in
+	expected := map[string]float64{
+		"p2: Blastoise": 80,
+		"p1: Charizard": 100,
+		"p1: Pikachu":   100,
+	}
+	for key, want := range expected {
+		if got := summary.Stats.DamageTakenByPokemon[key]; got != want {
+			t.Errorf("expected %s to take %.0f%%, got %.0f%%", key, want, got)
+		}
+	}
+	if len(summary.Stats.DamageTakenByPokemon) != len(expected) {
+		t.Errorf("expected %d entries, got %v", len(expected), summary.Stats.DamageTakenByPokemon)
+	}
+}
@@ -0,0 +1,30 @@
+package analysis
+
+import "strings"
+
+// phazer remembers what would force the next |drag|: the latest move (Roar,
+// Whirlwind, Dragon Tail, Circle Throw), or a Red Card that activated after
+// it and dragged the attacker out instead.
+type phazer struct {
+	source string
+}
+
+// move records a |move| line as the potential phazing move.
+func (p *phazer) move(parts []string) {
+	if len(parts) > 3 {
+		p.source = strings.TrimSpace(parts[3])
+	}
+}
+
+// endItem notes a Red Card activating: |-enditem|p2a: Holder|Red Card|[of] p1a: Attacker.
+func (p *phazer) endItem(parts []string) {
+	if len(parts) > 3 && strings.EqualFold(strings.TrimSpace(parts[3]), "Red Card") {
+		p.source = "Red Card"
+	}
+}
+
+// drag marks a switch action as forced by the move or item that caused it.
+func (p *phazer) drag(action *Action) {
+	action.Forced = true
+	action.ForcedBy = p.source
+}
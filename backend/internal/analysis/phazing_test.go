@@ -0,0 +1,103 @@
+package analysis
+
+import "testing"
+
+const phazingLog = `|player|p1|Alice|1|
+|player|p2|Bob|2|
+|gen|9
+|gametype|doubles
+|poke|p1|Dragonite, L50, M|
+|poke|p1|Arcanine, L50, M|
+|poke|p1|Amoonguss, L50, F|
+|poke|p2|Kingambit, L50, M|
+|poke|p2|Sneasler, L50, F|
+|poke|p2|Gholdengo, L50|
+|start
+|switch|p1a: Dragonite|Dragonite, L50, M|100/100
+|switch|p1b: Arcanine|Arcanine, L50, M|100/100
+|switch|p2a: Kingambit|Kingambit, L50, M|100/100
+|switch|p2b: Sneasler|Sneasler, L50, F|100/100
+|turn|1
+|move|p1a: Dragonite|Dragon Tail|p2a: Kingambit
+|-resisted|p2a: Kingambit
+|-damage|p2a: Kingambit|90/100
+|drag|p2a: Gholdengo|Gholdengo, L50|100/100
+|move|p2b: Sneasler|Close Combat|p1b: Arcanine
+|-damage|p1b: Arcanine|30/100
+|-enditem|p1b: Arcanine|Red Card|[of] p2b: Sneasler
+|drag|p2b: Kingambit|Kingambit, L50, M|90/100
+|upkeep
+|turn|2
+|switch|p1b: Amoonguss|Amoonguss, L50, F|100/100
+|move|p1a: Dragonite|Roar|p2a: Gholdengo
+|drag|p2a: Sneasler|Sneasler, L50, F|100/100
+|upkeep
+|turn|3
+|win|Alice
+`
+
+func TestParseShowdownLogDragIsForced(t *testing.T) {
+	for name, parse := range map[string]func(string) (*BattleSummary, error){
+		"basic":    ParseShowdownLog,
+		"enhanced": ParseEnhancedShowdownLog,
+	} {
+		t.Run(name, func(t *testing.T) {
+			summary, err := parse(phazingLog)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(summary.Turns) < 2 {
+				t.Fatalf("expected 2 turns, got %d", len(summary.Turns))
+			}
+
+			var switches []Action
+			for _, turn := range summary.Turns[:2] {
+				for _, action := range turn.Actions {
+					if action.ActionType == "switch" {
+						switches = append(switches, action)
+					}
+				}
+			}
+
+			want := []struct {
+				switchTo string
+				forcedBy string
+			}{
+				{"Gholdengo", "Dragon Tail"},
+				{"Kingambit", "Red Card"},
+				{"Amoonguss", ""},
+				{"Sneasler", "Roar"},
+			}
+			if len(switches) != len(want) {
+				t.Fatalf("expected %d switches, got %+v", len(want), switches)
+			}
+			for i, w := range want {
+				got := switches[i]
+				if got.SwitchTo != w.switchTo || got.Forced != (w.forcedBy != "") || got.ForcedBy != w.forcedBy {
+					t.Errorf("switch %d: expected %s forced by %q, got %s (forced %v by %q)",
+						i, w.switchTo, w.forcedBy, got.SwitchTo, got.Forced, got.ForcedBy)
+				}
+			}
+		})
+	}
+}
+
+func TestParseShowdownLogDragNotCountedAsSwitch(t *testing.T) {
+	summary, err := ParseShowdownLog(phazingLog)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := summary.Stats.SwitchCount; got["player1"] != 1 || got["player2"] != 0 {
+		t.Errorf("expected only Alice's Amoonguss switch to count, got %v", got)
+	}
+	if summary.Stats.Switch != 1 {
+		t.Errorf("expected 1 chosen switch, got %d", summary.Stats.Switch)
+	}
+	if leads := summary.Player2.Leads; len(leads) != 2 {
+		t.Errorf("expected phazed-in Pokémon not to be leads, got %v", leads)
+	}
+	if brought := summary.Player2.BringOrder; len(brought) != 3 || brought[2] != "Gholdengo" {
+		t.Errorf("expected Gholdengo to be brought after being dragged in, got %v", brought)
+	}
+}
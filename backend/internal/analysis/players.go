@@ -0,0 +1,96 @@
+package analysis
+
+// maxPlayers is the most players a battle can seat; free-for-all and multi
+// battles use p1 through p4.
+const maxPlayers = 4
+
+// newPlayer returns a Player with its maps and slices initialized.
+func newPlayer() Player {
+	return Player{
+		RevealedMoves:     make(map[string][]string),
+		RevealedAbilities: make(map[string]string),
+		KOs:               make(map[string]int),
+		IndirectDamage:    make(map[string]float64),
+		Leads:             []string{},
+		PreviewOrder:      []string{},
+		BringOrder:        []string{},
+		Benched:           []string{},
+	}
+}
+
+// validSlot reports whether slot is a raw player ID from "p1" to "p4".
+func validSlot(slot string) bool {
+	return len(slot) == 2 && slot[0] == 'p' && slot[1] >= '1' && slot[1] <= '0'+maxPlayers
+}
+
+// playerKey converts a raw slot like "p3" to the "player3" form used in
+// actions, stats maps, and Winner.
+func playerKey(slot string) string {
+	return "player" + slot[1:]
+}
+
+// seat returns the player in a raw slot ("p1".."p4"), seating a new player
+// in Player3 or Player4 on first use. p1 and p2 are Player1 and Player2. It
+// returns nil for anything that isn't a slot.
+func (s *BattleSummary) seat(slot string) *Player {
+	if !validSlot(slot) {
+		return nil
+	}
+	if s.Players == nil {
+		s.Players = map[string]*Player{"p1": &s.Player1, "p2": &s.Player2}
+	}
+	if player, ok := s.Players[slot]; ok {
+		return player
+	}
+	player := newPlayer()
+	s.Players[slot] = &player
+	switch slot {
+	case "p3":
+		s.Player3 = &player
+	case "p4":
+		s.Player4 = &player
+	}
+	return &player
+}
+
+// PlayerBySlot returns the player in a slot, given as "p3" or "player3", or
+// nil if nobody is seated there.
+func (s *BattleSummary) PlayerBySlot(slot string) *Player {
+	if len(slot) == len("player1") && slot[:len("player")] == "player" {
+		slot = "p" + slot[len("player"):]
+	}
+	switch slot {
+	case "p1":
+		return &s.Player1
+	case "p2":
+		return &s.Player2
+	case "p3":
+		return s.Player3
+	case "p4":
+		return s.Player4
+	}
+	return nil
+}
+
+// allPlayers returns every seated player in slot order. Summaries built
+// without Players (e.g. by hand) have just Player1 and Player2.
+func (s *BattleSummary) allPlayers() []*Player {
+	slots := s.slots()
+	players := make([]*Player, 0, len(slots))
+	for _, slot := range slots {
+		players = append(players, s.PlayerBySlot(slot))
+	}
+	return players
+}
+
+// slots returns the raw IDs of every seated player in order.
+func (s *BattleSummary) slots() []string {
+	slots := []string{"p1", "p2"}
+	if s.Player3 != nil {
+		slots = append(slots, "p3")
+	}
+	if s.Player4 != nil {
+		slots = append(slots, "p4")
+	}
+	return slots
+}
@@ -0,0 +1,107 @@
+package analysis
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const freeForAllLog = `|gametype|freeforall
+|player|p1|Alice|1|1200
+|player|p2|Bob|2|
+|player|p3|Carol|3|1300
+|player|p4|Dave|4|
+|gen|9
+|poke|p1|Pikachu, L50, M|
+|poke|p2|Gengar, L50, M|
+|poke|p3|Snorlax, L50, F|
+|poke|p4|Blastoise, L50, M|
+|start
+|switch|p1a: Pikachu|Pikachu, L50, M|100/100
+|switch|p2a: Gengar|Gengar, L50, M|100/100
+|switch|p3a: Snorlax|Snorlax, L50, F|100/100
+|switch|p4a: Blastoise|Blastoise, L50, M|100/100
+|turn|1
+|move|p3a: Snorlax|Body Slam|p4a: Blastoise
+|-damage|p4a: Blastoise|0 fnt
+|faint|p4a: Blastoise
+|move|p1a: Pikachu|Thunderbolt|p2a: Gengar
+|-damage|p2a: Gengar|40/100
+|upkeep
+|turn|2
+|win|Carol
+`
+
+func TestParseShowdownLogFreeForAll(t *testing.T) {
+	summary, err := ParseShowdownLog(freeForAllLog)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := map[string]string{"p1": "Alice", "p2": "Bob", "p3": "Carol", "p4": "Dave"}
+	if len(summary.Players) != len(want) {
+		t.Fatalf("expected %d players, got %d", len(want), len(summary.Players))
+	}
+	for slot, name := range want {
+		player := summary.Players[slot]
+		if player == nil || player.Name != name {
+			t.Errorf("expected %s to be %s, got %+v", slot, name, player)
+		}
+	}
+
+	// Player1 and Player2 stay in sync with their seats
+	if summary.Player1.Name != "Alice" || summary.Players["p1"] != &summary.Player1 {
+		t.Error("expected Player1 to be seat p1")
+	}
+	if summary.Player3 != summary.Players["p3"] || summary.Player4 != summary.Players["p4"] {
+		t.Error("expected Player3 and Player4 to be seats p3 and p4")
+	}
+	if summary.Players["p3"].Rating != 1300 {
+		t.Errorf("expected Carol's rating 1300, got %d", summary.Players["p3"].Rating)
+	}
+
+	if summary.Winner != "player3" {
+		t.Errorf("expected winner player3, got %q", summary.Winner)
+	}
+	if summary.Players["p3"].KOs["Snorlax"] != 1 {
+		t.Errorf("expected Snorlax to be credited a KO, got %v", summary.Players["p3"].KOs)
+	}
+	if summary.Players["p4"].Losses != 1 {
+		t.Errorf("expected Dave to have 1 loss, got %d", summary.Players["p4"].Losses)
+	}
+	if leads := summary.Players["p4"].Leads; len(leads) != 1 || leads[0] != "Blastoise" {
+		t.Errorf("expected Dave's lead Blastoise, got %v", leads)
+	}
+	if len(summary.Player2.Leads) != 1 {
+		t.Errorf("expected other seats' leads to stay off Player2, got %v", summary.Player2.Leads)
+	}
+
+	if len(summary.Turns) == 0 || summary.Turns[0].Actions[0].Player != "player3" {
+		t.Errorf("expected the first action to be attributed to player3")
+	}
+	// Each seat is serialized once
+	encoded, err := json.Marshal(summary)
+	if err != nil {
+		t.Fatalf("failed to marshal summary: %v", err)
+	}
+	if strings.Contains(string(encoded), `"players"`) || strings.Count(string(encoded), `"name":"Carol"`) != 1 {
+		t.Errorf("expected Carol to be serialized once, under player3")
+	}
+}
+
+func TestPlayerBySlot(t *testing.T) {
+	summary := &BattleSummary{Player1: Player{Name: "Alice"}, Player2: Player{Name: "Bob"}}
+
+	if p := summary.PlayerBySlot("player1"); p == nil || p.Name != "Alice" {
+		t.Errorf("expected player1 to be Alice, got %+v", p)
+	}
+	if p := summary.PlayerBySlot("p2"); p == nil || p.Name != "Bob" {
+		t.Errorf("expected p2 to be Bob, got %+v", p)
+	}
+	if p := summary.PlayerBySlot("player3"); p != nil {
+		t.Errorf("expected no player3, got %+v", p)
+	}
+	if p := summary.PlayerBySlot(""); p != nil {
+		t.Errorf("expected no player for an empty slot, got %+v", p)
+	}
+}
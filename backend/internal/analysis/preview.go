@@ -0,0 +1,43 @@
+package analysis
+
+import "strings"
+
+// recordBrought adds a species to the player's bring order the first time it
+// switches in. Leads come first, since they switch in before turn 1.
+func recordBrought(player *Player, species string) {
+	if player == nil {
+		return
+	}
+	for _, brought := range player.BringOrder {
+		if brought == species {
+			return
+		}
+	}
+	player.BringOrder = append(player.BringOrder, species)
+}
+
+// finishPreview fills in the preview order and the previewed Pokémon that
+// never switched in once the log has been read.
+func finishPreview(player *Player) {
+	player.PreviewOrder = make([]string, 0, len(player.Team))
+	player.Benched = []string{}
+	for _, poke := range player.Team {
+		player.PreviewOrder = append(player.PreviewOrder, poke.Name)
+		if !previewBrought(poke.Name, player.BringOrder) {
+			player.Benched = append(player.Benched, poke.Name)
+		}
+	}
+}
+
+// previewBrought reports whether a previewed species is in the bring order.
+// Preview hides some formes behind a wildcard ("Urshifu-*"), which matches
+// any forme that switched in ("Urshifu-Rapid-Strike").
+func previewBrought(preview string, bringOrder []string) bool {
+	prefix, wildcard := strings.CutSuffix(preview, "*")
+	for _, species := range bringOrder {
+		if species == preview || (wildcard && strings.HasPrefix(species, prefix)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,59 @@
+package analysis
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseShowdownLogPreviewAndBringOrder(t *testing.T) {
+	summary, err := ParseShowdownLog(readBenchLog(t))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		player                       *Player
+		preview, bringOrder, benched []string
+	}{
+		{
+			player:     &summary.Player1,
+			preview:    []string{"Incineroar", "Rillaboom", "Torkoal", "Lilligant-Hisui", "Ursaluna", "Farigiraf"},
+			bringOrder: []string{"Incineroar", "Torkoal", "Rillaboom", "Farigiraf", "Ursaluna"},
+			benched:    []string{"Lilligant-Hisui"},
+		},
+		{
+			player:     &summary.Player2,
+			preview:    []string{"Pelipper", "Archaludon", "Amoonguss", "Dragonite", "Kingambit", "Sinistcha"},
+			bringOrder: []string{"Pelipper", "Archaludon", "Kingambit", "Amoonguss"},
+			benched:    []string{"Dragonite", "Sinistcha"},
+		},
+	}
+	for _, tt := range tests {
+		if !reflect.DeepEqual(tt.player.PreviewOrder, tt.preview) {
+			t.Errorf("%s: expected preview order %v, got %v", tt.player.Name, tt.preview, tt.player.PreviewOrder)
+		}
+		if !reflect.DeepEqual(tt.player.BringOrder, tt.bringOrder) {
+			t.Errorf("%s: expected bring order %v, got %v", tt.player.Name, tt.bringOrder, tt.player.BringOrder)
+		}
+		if !reflect.DeepEqual(tt.player.Benched, tt.benched) {
+			t.Errorf("%s: expected benched %v, got %v", tt.player.Name, tt.benched, tt.player.Benched)
+		}
+		// The leads open the bring order
+		if !reflect.DeepEqual(tt.player.BringOrder[:len(tt.player.Leads)], tt.player.Leads) {
+			t.Errorf("%s: expected bring order to start with leads %v, got %v", tt.player.Name, tt.player.Leads, tt.player.BringOrder)
+		}
+	}
+}
+
+func TestPreviewBroughtWildcardForme(t *testing.T) {
+	bringOrder := []string{"Urshifu-Rapid-Strike", "Amoonguss"}
+	if !previewBrought("Urshifu-*", bringOrder) {
+		t.Error("expected Urshifu-* to match Urshifu-Rapid-Strike")
+	}
+	if !previewBrought("Amoonguss", bringOrder) {
+		t.Error("expected an exact match")
+	}
+	if previewBrought("Urshifu", bringOrder) {
+		t.Error("expected a plain species not to match another forme")
+	}
+}
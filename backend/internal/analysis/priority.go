@@ -0,0 +1,38 @@
+package analysis
+
+import "strings"
+
+// priorityActivations maps the -activate effects that let a Pokémon act
+// first within its priority bracket, lowercased, to the name recorded on
+// Action.PriorityReason:
+//
+//	|-activate|p1a: Snorlax|item: Quick Claw
+//	|-activate|p2a: Slowbro|ability: Quick Draw
+var priorityActivations = map[string]string{
+	"item: quick claw":    "Quick Claw",
+	"item: custap berry":  "Custap Berry",
+	"ability: quick draw": "Quick Draw",
+}
+
+// priorityTracker remembers which Pokémon were announced to move first this
+// turn, "p1: Snorlax" -> reason, until their move is logged.
+type priorityTracker map[string]string
+
+// activate records a Quick Claw, Custap Berry or Quick Draw activation.
+func (p priorityTracker) activate(parts []string) {
+	if len(parts) < 4 {
+		return
+	}
+	if reason, ok := priorityActivations[strings.ToLower(strings.TrimSpace(parts[3]))]; ok {
+		p[pokemonKey(parts[2])] = reason
+	}
+}
+
+// move returns why the user of a |move| line moved early, if an activation
+// announced it, and forgets the activation.
+func (p priorityTracker) move(parts []string) string {
+	key := pokemonKey(parts[2])
+	reason := p[key]
+	delete(p, key)
+	return reason
+}
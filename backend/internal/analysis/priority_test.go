@@ -0,0 +1,67 @@
+package analysis
+
+import "testing"
+
+func TestParseShowdownLogPriorityReason(t *testing.T) {
+	log := `|player|p1|Alice|1|
+|player|p2|Bob|2|
+|gametype|doubles
+|start
+|switch|p1a: Snorlax|Snorlax, L50, M|100/100
+|switch|p1b: Torkoal|Torkoal, L50, F|100/100
+|switch|p2a: Dragapult|Dragapult, L50, M|100/100
+|switch|p2b: Garchomp|Garchomp, L50, F|20/100
+|turn|1
+|-activate|p1a: Snorlax|item: Quick Claw
+|move|p1a: Snorlax|Body Slam|p2a: Dragapult
+|-damage|p2a: Dragapult|60/100
+|-activate|p2b: Garchomp|item: Custap Berry
+|-enditem|p2b: Garchomp|Custap Berry|[eat]
+|move|p2b: Garchomp|Rock Slide|p1b: Torkoal|[spread] p1a,p1b
+|-damage|p1a: Snorlax|80/100
+|-damage|p1b: Torkoal|85/100
+|move|p2a: Dragapult|Shadow Ball|p1a: Snorlax
+|-immune|p1a: Snorlax
+|move|p1b: Torkoal|Heat Wave|p2a: Dragapult|[spread] p2a,p2b
+|-damage|p2a: Dragapult|40/100
+|-damage|p2b: Garchomp|5/100
+|upkeep
+|turn|2
+|move|p1a: Snorlax|Body Slam|p2a: Dragapult
+|-damage|p2a: Dragapult|10/100
+|upkeep
+|win|Alice
+`
+	for name, parse := range map[string]func(string) (*BattleSummary, error){
+		"basic":    ParseShowdownLog,
+		"enhanced": ParseEnhancedShowdownLog,
+	} {
+		t.Run(name, func(t *testing.T) {
+			summary, err := parse(log)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			want := map[string]string{
+				"Snorlax":   "Quick Claw",
+				"Garchomp":  "Custap Berry",
+				"Dragapult": "",
+				"Torkoal":   "",
+			}
+			turn1 := summary.Turns[0].Actions
+			if len(turn1) != len(want) {
+				t.Fatalf("expected %d actions on turn 1, got %+v", len(want), turn1)
+			}
+			for _, action := range turn1 {
+				if action.PriorityReason != want[action.Pokemon] {
+					t.Errorf("%s: expected priority reason %q, got %q", action.Pokemon, want[action.Pokemon], action.PriorityReason)
+				}
+			}
+
+			// Quick Claw didn't activate on turn 2
+			if reason := summary.Turns[1].Actions[0].PriorityReason; reason != "" {
+				t.Errorf("expected no priority reason on turn 2, got %q", reason)
+			}
+		})
+	}
+}
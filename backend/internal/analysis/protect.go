@@ -0,0 +1,87 @@
+package analysis
+
+import (
+	"fmt"
+	"strings"
+)
+
+// protectMoves are the moves sharing Protect's consecutive-use penalty: each
+// repeat on the following turn only has a 1/3 chance to work.
+var protectMoves = map[string]bool{
+	"protect":         true,
+	"detect":          true,
+	"spiky shield":    true,
+	"baneful bunker":  true,
+	"king's shield":   true,
+	"silk trap":       true,
+	"burning bulwark": true,
+	"obstruct":        true,
+	"max guard":       true,
+}
+
+// isProtectMove reports whether a move name, optionally "move: "-prefixed, is
+// in the Protect family.
+func isProtectMove(name string) bool {
+	name = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(name), "move:"))
+	return protectMoves[strings.ToLower(name)]
+}
+
+// protectTracker remembers the last turn each Pokémon protected, keyed by
+// pokemonKey, to spot back-to-back Protects.
+type protectTracker struct {
+	lastTurn map[string]int
+}
+
+func newProtectTracker() *protectTracker {
+	return &protectTracker{lastTurn: make(map[string]int)}
+}
+
+// move records a move and reports whether it is a Protect used on the turn
+// right after the same Pokémon's previous Protect.
+func (p *protectTracker) move(ref, move string, turn int) bool {
+	key := pokemonKey(ref)
+	if !isProtectMove(move) {
+		delete(p.lastTurn, key)
+		return false
+	}
+	last, ok := p.lastTurn[key]
+	p.lastTurn[key] = turn
+	return ok && turn > 0 && last == turn-1
+}
+
+// reset clears a Pokémon's streak: switching out or a failed Protect both
+// restore full odds.
+func (p *protectTracker) reset(ref string) {
+	delete(p.lastTurn, pokemonKey(ref))
+}
+
+// recordProtectFail handles a |-fail| line. When it is the failure of a
+// consecutive Protect on the most recent |move| line, it adds a ProtectFail
+// key moment. Any failed Protect resets the Pokémon's streak.
+func recordProtectFail(summary *BattleSummary, protects *protectTracker, turn *Turn, lastMove []string, parts []string) {
+	if len(parts) < 3 || len(lastMove) < 4 || !isProtectMove(lastMove[3]) {
+		return
+	}
+	if pokemonKey(parts[2]) != pokemonKey(lastMove[2]) {
+		return
+	}
+	if len(parts) > 3 && strings.HasPrefix(strings.TrimSpace(parts[3]), "move:") && !isProtectMove(parts[3]) {
+		return
+	}
+	protects.reset(parts[2])
+
+	if turn == nil {
+		return
+	}
+	for i := len(turn.Actions) - 1; i >= 0; i-- {
+		action := turn.Actions[i]
+		if action.Move == nil || action.Player != extractPlayerIDFromRef(lastMove[2]) {
+			continue
+		}
+		if action.ConsecutiveProtect {
+			addKeyMoment(summary, turn.TurnNumber, "ProtectFail",
+				fmt.Sprintf("%s's consecutive %s failed", extractNickname(lastMove[2]), strings.TrimSpace(lastMove[3])), 6)
+		}
+		return
+	}
+}
@@ -0,0 +1,71 @@
+package analysis
+
+import "testing"
+
+const doubleProtectLog = `|player|p1|Alice|1|
+|player|p2|Bob|2|
+|start
+|switch|p1a: Amoonguss|Amoonguss, L50, F|100/100
+|switch|p2a: Urshifu|Urshifu, L50, M|100/100
+|turn|1
+|move|p1a: Amoonguss|Protect|p1a: Amoonguss
+|-singleturn|p1a: Amoonguss|Protect
+|move|p2a: Urshifu|Close Combat|p1a: Amoonguss
+|-activate|p1a: Amoonguss|move: Protect
+|upkeep
+|turn|2
+|move|p1a: Amoonguss|Protect|p1a: Amoonguss
+|-fail|p1a: Amoonguss
+|move|p2a: Urshifu|Close Combat|p1a: Amoonguss
+|-damage|p1a: Amoonguss|40/100
+|upkeep
+|turn|3
+|move|p1a: Amoonguss|Protect|p1a: Amoonguss
+|-singleturn|p1a: Amoonguss|Protect
+|upkeep
+|turn|4
+|win|Alice
+`
+
+func TestParseShowdownLogConsecutiveProtect(t *testing.T) {
+	for name, parse := range map[string]func(string) (*BattleSummary, error){
+		"basic":    ParseShowdownLog,
+		"enhanced": ParseEnhancedShowdownLog,
+	} {
+		t.Run(name, func(t *testing.T) {
+			summary, err := parse(doubleProtectLog)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(summary.Turns) < 3 {
+				t.Fatalf("expected at least 3 turns, got %d", len(summary.Turns))
+			}
+
+			// Only the turn-2 Protect is a repeat; the failure resets the streak
+			for i, want := range []bool{false, true, false} {
+				if got := firstMoveAction(summary.Turns[i]).ConsecutiveProtect; got != want {
+					t.Errorf("turn %d: expected ConsecutiveProtect %v, got %v", i+1, want, got)
+				}
+			}
+
+			var fails []KeyMoment
+			for _, m := range summary.KeyMoments {
+				if m.Type == "ProtectFail" {
+					fails = append(fails, m)
+				}
+			}
+			if len(fails) != 1 || fails[0].TurnNumber != 2 {
+				t.Errorf("expected one ProtectFail moment on turn 2, got %+v", fails)
+			}
+		})
+	}
+}
+
+func firstMoveAction(turn Turn) Action {
+	for _, action := range turn.Actions {
+		if action.ActionType == "move" {
+			return action
+		}
+	}
+	return Action{}
+}
@@ -0,0 +1,67 @@
+package analysis
+
+import "strings"
+
+// pursuitTracker links a Pursuit to the switch it punished. Pursuit against
+// a Pokémon that is switching out strikes first at double power; Showdown
+// announces it with |-activate|TARGET|move: Pursuit, logs the Pursuit, and
+// then lets the switch go ahead if the target survived.
+type pursuitTracker struct {
+	// pokemonKey of the Pokémon Pursuit caught switching out
+	caught string
+	// Field position hit by the latest Pursuit, cleared by the next action
+	target string
+	// The latest Pursuit's user and its index in the turn's actions
+	user  string
+	index int
+}
+
+// activate notes a |-activate|...|move: Pursuit interception.
+func (p *pursuitTracker) activate(parts []string) {
+	if len(parts) > 3 && strings.EqualFold(strings.TrimSpace(parts[3]), "move: Pursuit") {
+		p.caught = pokemonKey(parts[2])
+	}
+}
+
+// move starts waiting for the target's switch if parts is a Pursuit that is
+// about to become turn's next action. Any other move ends the wait.
+func (p *pursuitTracker) move(turn *Turn, parts []string) {
+	p.target = ""
+	if turn == nil || len(parts) < 5 || normalizeID(parts[3]) != "pursuit" {
+		p.caught = ""
+		return
+	}
+	p.target = fieldPosition(parts[4])
+	p.user = parts[2]
+	p.index = len(turn.Actions)
+}
+
+// switchOut reports whether a |switch| line is the target of the Pursuit
+// just before it leaving the field, marking that Pursuit if so.
+func (p *pursuitTracker) switchOut(boundary turnBoundary, turn *Turn, parts []string) bool {
+	tracked := *p
+	*p = pursuitTracker{}
+	if tracked.target == "" || boundary.phase != phaseActions || fieldPosition(parts[2]) != tracked.target {
+		return false
+	}
+	return tracked.mark(turn)
+}
+
+// faint returns the Pursuit user when a Pokémon Pursuit caught switching out
+// faints to it, marking that Pursuit.
+func (p *pursuitTracker) faint(turn *Turn, parts []string) (string, bool) {
+	tracked := *p
+	if len(parts) < 3 || tracked.target == "" || tracked.caught != pokemonKey(parts[2]) {
+		return "", false
+	}
+	*p = pursuitTracker{}
+	return tracked.user, tracked.mark(turn)
+}
+
+func (p pursuitTracker) mark(turn *Turn) bool {
+	if turn == nil || p.index >= len(turn.Actions) {
+		return false
+	}
+	turn.Actions[p.index].PursuitPunish = true
+	return true
+}
@@ -0,0 +1,103 @@
+package analysis
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseShowdownLogPursuitPunish(t *testing.T) {
+	log := `|player|p1|Alice|1|
+|player|p2|Bob|2|
+|gametype|singles
+|start
+|switch|p1a: Alakazam|Alakazam, L50, M|100/100
+|switch|p2a: Tyranitar|Tyranitar, L50, M|100/100
+|turn|1
+|-activate|p1a: Alakazam|move: Pursuit
+|move|p2a: Tyranitar|Pursuit|p1a: Alakazam
+|-supereffective|p1a: Alakazam
+|-damage|p1a: Alakazam|20/100
+|switch|p1a: Starmie|Starmie, L50|100/100
+|upkeep
+|turn|2
+|move|p1a: Starmie|Surf|p2a: Tyranitar
+|-supereffective|p2a: Tyranitar
+|-damage|p2a: Tyranitar|30/100
+|move|p2a: Tyranitar|Pursuit|p1a: Starmie
+|-damage|p1a: Starmie|60/100
+|upkeep
+|turn|3
+|switch|p1a: Alakazam|Alakazam, L50, M|20/100
+|-activate|p1a: Alakazam|move: Pursuit
+|move|p2a: Tyranitar|Pursuit|p1a: Alakazam
+|-supereffective|p1a: Alakazam
+|-damage|p1a: Alakazam|0 fnt
+|faint|p1a: Alakazam
+|upkeep
+|win|Bob
+`
+	for name, parse := range map[string]func(string) (*BattleSummary, error){
+		"basic":    ParseShowdownLog,
+		"enhanced": ParseEnhancedShowdownLog,
+	} {
+		t.Run(name, func(t *testing.T) {
+			summary, err := parse(log)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			turn1 := summary.Turns[0].Actions
+			if len(turn1) != 2 {
+				t.Fatalf("expected Pursuit and a switch on turn 1, got %+v", turn1)
+			}
+			if !turn1[0].PursuitPunish || turn1[0].Move.Name != "Pursuit" {
+				t.Errorf("expected the Pursuit to be linked to the switch, got %+v", turn1[0])
+			}
+			if !turn1[1].PursuitPunish || turn1[1].SwitchTo != "Starmie" {
+				t.Errorf("expected the switch to Starmie to be linked to the Pursuit, got %+v", turn1[1])
+			}
+
+			// A Pursuit against a target that stays in is an ordinary hit
+			for _, action := range summary.Turns[1].Actions {
+				if action.PursuitPunish {
+					t.Errorf("expected no Pursuit link on turn 2, got %+v", action)
+				}
+			}
+
+			// Alakazam fainted before it could switch out again
+			turn3 := summary.Turns[2].Actions
+			if last := turn3[len(turn3)-1]; !last.PursuitPunish || last.Move == nil || last.Move.Name != "Pursuit" {
+				t.Errorf("expected the KO Pursuit to be marked, got %+v", last)
+			}
+		})
+	}
+}
+
+func TestParseShowdownLogPursuitKOMoment(t *testing.T) {
+	summary, err := ParseShowdownLog(`|player|p1|Alice|1|
+|player|p2|Bob|2|
+|start
+|switch|p1a: Alakazam|Alakazam, L50, M|30/100
+|switch|p2a: Tyranitar|Tyranitar, L50, M|100/100
+|turn|1
+|-activate|p1a: Alakazam|move: Pursuit
+|move|p2a: Tyranitar|Pursuit|p1a: Alakazam
+|-damage|p1a: Alakazam|0 fnt
+|faint|p1a: Alakazam
+|upkeep
+|win|Bob
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, moment := range summary.KeyMoments {
+		if moment.Type == "KO" {
+			if !strings.Contains(moment.Description, "switching out") || !strings.Contains(moment.Description, "Tyranitar's Pursuit") {
+				t.Errorf("expected the KO moment to explain the Pursuit, got %q", moment.Description)
+			}
+			return
+		}
+	}
+	t.Fatalf("expected a KO moment, got %+v", summary.KeyMoments)
+}
@@ -0,0 +1,192 @@
+package analysis
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	recapKeyMoments     = 3
+	recapMaxDescription = 120
+)
+
+// KO causes recorded on KOEvents.
+const (
+	koCauseMove         = "move"
+	koCauseDestinyBond  = "destinybond"
+	koCauseSelfDestruct = "selfdestruct"
+	koCauseIndirect     = "indirect"
+)
+
+// selfFaintMoves make their user faint as part of the move.
+var selfFaintMoves = map[string]bool{
+	"explosion":       true,
+	"selfdestruct":    true,
+	"misty explosion": true,
+	"final gambit":    true,
+	"memento":         true,
+	"healing wish":    true,
+	"lunar dance":     true,
+}
+
+// creditKO credits a faint to the Pokémon whose move caused it and returns
+// that Pokémon. The move must have targeted the fainted Pokémon or been a
+// spread move, and must come from the opposing side; chip damage, self-KOs
+// and recoil go uncredited and return "".
+func creditKO(summary *BattleSummary, tracker *StateTracker, lastMove []string, fainted string) string {
+	if len(lastMove) < 4 {
+		return ""
+	}
+	attacker := lastMove[2]
+	if extractRawPlayerID(attacker) == extractRawPlayerID(fainted) {
+		return ""
+	}
+
+	targeted := len(lastMove) > 4 && pokemonKey(lastMove[4]) == pokemonKey(fainted)
+	if !targeted && !hasSpreadTag(lastMove) {
+		return ""
+	}
+
+	creditKOTo(summary, tracker, attacker)
+	return attacker
+}
+
+// creditKOTo credits a KO to the given Pokémon reference.
+func creditKOTo(summary *BattleSummary, tracker *StateTracker, attacker string) {
+	kos := summary.seat(extractRawPlayerID(attacker)).KOs
+	kos[tracker.SpeciesFor(attacker)]++
+}
+
+// selfFainted reports whether fainted went down to its own Explosion,
+// Memento, or other move that costs the user its HP.
+func selfFainted(lastMove []string, fainted string) bool {
+	return len(lastMove) >= 4 && pokemonKey(lastMove[2]) == pokemonKey(fainted) &&
+		selfFaintMoves[normalizeID(lastMove[3])]
+}
+
+// recordKO adds a KOEvent for fainted, credited to by ("" when nothing was).
+func recordKO(summary *BattleSummary, tracker *StateTracker, turn int, fainted, by, cause string) {
+	event := KOEvent{
+		Turn:    turn,
+		Player:  extractPlayerIDFromRef(fainted),
+		Pokemon: extractNickname(fainted),
+		Species: tracker.SpeciesFor(fainted),
+		Cause:   cause,
+	}
+	if by != "" {
+		event.By = tracker.SpeciesFor(by)
+		event.ByPlayer = extractPlayerIDFromRef(by)
+	}
+	summary.KOEvents = append(summary.KOEvents, event)
+}
+
+// hasSpreadTag reports whether a |move| line carries a [spread] annotation,
+// e.g. "[spread] p2a,p2b".
+func hasSpreadTag(parts []string) bool {
+	for _, part := range parts[4:] {
+		if strings.HasPrefix(part, "[spread]") {
+			return true
+		}
+	}
+	return false
+}
+
+// MVP returns the winning side's Pokémon with the most KOs. Ties go to the
+// alphabetically first species. ok is false for draws, unfinished battles,
+// or when the winner scored no KOs.
+func (s *BattleSummary) MVP() (species string, kos int, ok bool) {
+	player := s.PlayerBySlot(s.Winner)
+	if player == nil {
+		return "", 0, false
+	}
+
+	for name, count := range player.KOs {
+		if count > kos || (count == kos && name < species) {
+			species, kos = name, count
+		}
+	}
+	return species, kos, kos > 0
+}
+
+// Recap renders a short multi-line plain-text summary of the battle suitable
+// for pasting into chat.
+func Recap(s *BattleSummary) string {
+	var b strings.Builder
+
+	if s.Format != "" {
+		b.WriteString(s.Format + "\n")
+	}
+	names := []string{}
+	for _, player := range s.allPlayers() {
+		names = append(names, recapPlayer(*player))
+	}
+	b.WriteString(strings.Join(names, " vs ") + "\n")
+
+	switch winner := s.PlayerBySlot(s.Winner); {
+	case s.Draw:
+		b.WriteString("Result: Draw\n")
+	case winner != nil:
+		fmt.Fprintf(&b, "Winner: %s\n", winner.Name)
+	default:
+		b.WriteString("Result: Unfinished\n")
+	}
+
+	fmt.Fprintf(&b, "Turns: %d\n", s.Stats.TotalTurns)
+
+	if species, kos, ok := s.MVP(); ok {
+		fmt.Fprintf(&b, "MVP: %s (%d KO%s)\n", species, kos, plural(kos))
+	}
+
+	moments := topKeyMoments(s.KeyMoments, recapKeyMoments)
+	if len(moments) > 0 {
+		b.WriteString("Key moments:\n")
+		for _, m := range moments {
+			fmt.Fprintf(&b, "- Turn %d: %s\n", m.TurnNumber, truncate(m.Description, recapMaxDescription))
+		}
+	}
+
+	return b.String()
+}
+
+func recapPlayer(p Player) string {
+	name := p.Name
+	if name == "" {
+		name = "Unknown"
+	}
+	if p.Rating > 0 {
+		return fmt.Sprintf("%s (%d)", name, p.Rating)
+	}
+	return name
+}
+
+// topKeyMoments returns the n most significant moments, earliest first on ties.
+func topKeyMoments(moments []KeyMoment, n int) []KeyMoment {
+	sorted := make([]KeyMoment, len(moments))
+	copy(sorted, moments)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Significance != sorted[j].Significance {
+			return sorted[i].Significance > sorted[j].Significance
+		}
+		return sorted[i].TurnNumber < sorted[j].TurnNumber
+	})
+	if len(sorted) > n {
+		sorted = sorted[:n]
+	}
+	return sorted
+}
+
+func truncate(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max-1]) + "…"
+}
+
+func plural(n int) string {
+	if n == 1 {
+		return ""
+	}
+	return "s"
+}
@@ -0,0 +1,99 @@
+package analysis
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseShowdownLogCreditsKOs(t *testing.T) {
+	summary, _ := ParseShowdownLog(sampleBattleLog())
+
+	if summary.Player2.KOs["Blastoise"] != 2 {
+		t.Errorf("expected Blastoise credited with 2 KOs, got %v", summary.Player2.KOs)
+	}
+	if len(summary.Player1.KOs) != 0 {
+		t.Errorf("expected no KOs for player1, got %v", summary.Player1.KOs)
+	}
+	if summary.Player1.Rating != 1487 || summary.Player2.Rating != 1398 {
+		t.Errorf("expected ratings 1487/1398, got %d/%d", summary.Player1.Rating, summary.Player2.Rating)
+	}
+}
+
+func TestRecap(t *testing.T) {
+	summary, _ := ParseShowdownLog(sampleBattleLog())
+
+	recap := Recap(summary)
+
+	for _, want := range []string{
+		"Player1 (1487) vs Player2 (1398)",
+		"Winner: Player2",
+		"Turns: 5",
+		"MVP: Blastoise (2 KOs)",
+	} {
+		if !strings.Contains(recap, want) {
+			t.Errorf("expected recap to contain %q, got:\n%s", want, recap)
+		}
+	}
+}
+
+func TestMVPDraw(t *testing.T) {
+	summary := &BattleSummary{Draw: true}
+	if _, _, ok := summary.MVP(); ok {
+		t.Error("expected no MVP for a draw")
+	}
+}
+
+func TestParseShowdownLogKOEventCauses(t *testing.T) {
+	log := `|player|p1|Alice|1|
+|player|p2|Bob|2|
+|start
+|switch|p1a: Gengar|Gengar, L50, M|100/100
+|switch|p2a: Garchomp|Garchomp, L50, M|100/100
+|turn|1
+|move|p1a: Gengar|Destiny Bond|p1a: Gengar
+|-singlemove|p1a: Gengar|Destiny Bond
+|move|p2a: Garchomp|Crunch|p1a: Gengar
+|-supereffective|p1a: Gengar
+|-damage|p1a: Gengar|0 fnt
+|-activate|p1a: Gengar|move: Destiny Bond
+|faint|p1a: Gengar
+|faint|p2a: Garchomp
+|upkeep
+|switch|p1a: Boomer|Electrode, L50|100/100
+|switch|p2a: Blissey|Blissey, L50, F|100/100
+|turn|2
+|move|p1a: Boomer|Explosion|p2a: Blissey
+|-damage|p2a: Blissey|0 fnt
+|faint|p2a: Blissey
+|faint|p1a: Boomer
+|
+|tie
+`
+	summary, err := ParseShowdownLog(log)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []KOEvent{
+		{Turn: 1, Player: "player1", Pokemon: "Gengar", Species: "Gengar", By: "Garchomp", ByPlayer: "player2", Cause: "move"},
+		{Turn: 1, Player: "player2", Pokemon: "Garchomp", Species: "Garchomp", By: "Gengar", ByPlayer: "player1", Cause: "destinybond"},
+		{Turn: 2, Player: "player2", Pokemon: "Blissey", Species: "Blissey", By: "Electrode", ByPlayer: "player1", Cause: "move"},
+		{Turn: 2, Player: "player1", Pokemon: "Boomer", Species: "Electrode", By: "Electrode", ByPlayer: "player1", Cause: "selfdestruct"},
+	}
+	if len(summary.KOEvents) != len(want) {
+		t.Fatalf("expected %d KO events, got %+v", len(want), summary.KOEvents)
+	}
+	for i, w := range want {
+		if summary.KOEvents[i] != w {
+			t.Errorf("KO event %d: expected %+v, got %+v", i, w, summary.KOEvents[i])
+		}
+	}
+
+	// Destiny Bond credits Garchomp's faint to Gengar though no attack hit it
+	if summary.Player1.KOs["Gengar"] != 1 || summary.Player1.KOs["Electrode"] != 1 {
+		t.Errorf("expected one KO each for Gengar and Electrode, got %v", summary.Player1.KOs)
+	}
+	if summary.Player2.KOs["Garchomp"] != 1 {
+		t.Errorf("expected Garchomp credited with Gengar only, got %v", summary.Player2.KOs)
+	}
+}
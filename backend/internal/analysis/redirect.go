@@ -0,0 +1,59 @@
+package analysis
+
+import "strings"
+
+// redirectMoves are moves that change which ally an opposing move hits.
+var redirectMoves = map[string]string{
+	"follow me":   "Follow Me",
+	"rage powder": "Rage Powder",
+	"spotlight":   "Spotlight",
+	"ally switch": "Ally Switch",
+}
+
+// parseRedirect recognizes redirection from a -singleturn line
+// (|-singleturn|p1a: Amoonguss|move: Rage Powder) or an -activate line
+// (|-activate|p1a: Indeedee|move: Ally Switch|[of] p1b: Dondozo).
+func parseRedirect(parts []string) (RedirectEvent, bool) {
+	if len(parts) < 4 {
+		return RedirectEvent{}, false
+	}
+	effect := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(parts[3]), "move:")))
+	move, ok := redirectMoves[effect]
+	if !ok {
+		return RedirectEvent{}, false
+	}
+
+	event := RedirectEvent{
+		Player:  extractPlayerIDFromRef(parts[2]),
+		Pokemon: extractNickname(parts[2]),
+		Move:    move,
+	}
+	if of := ofSource(parts); of != "" {
+		event.Partner = extractNickname(of)
+	}
+	return event, true
+}
+
+// recordRedirect adds a redirect to the turn and tags the move action that
+// caused it.
+func recordRedirect(turn *Turn, event RedirectEvent) {
+	if turn == nil {
+		return
+	}
+	turn.Redirects = append(turn.Redirects, event)
+
+	for i := len(turn.Actions) - 1; i >= 0; i-- {
+		action := &turn.Actions[i]
+		if action.ActionType != "move" || action.Move == nil || action.Player != event.Player {
+			continue
+		}
+		if !strings.EqualFold(action.Move.Name, event.Move) {
+			continue
+		}
+		if action.Pokemon != "" && extractNickname(action.Pokemon) != event.Pokemon {
+			continue
+		}
+		action.Redirect = true
+		return
+	}
+}
@@ -0,0 +1,73 @@
+package analysis
+
+import "testing"
+
+const redirectLog = `|player|p1|Alice|1|
+|player|p2|Bob|2|
+|gametype|doubles
+|start
+|switch|p1a: Amoonguss|Amoonguss, L50, F|100/100
+|switch|p1b: Indeedee|Indeedee-F, L50, F|100/100
+|switch|p2a: Flutter Mane|Flutter Mane, L50|100/100
+|switch|p2b: Clefairy|Clefairy, L50, F|100/100
+|turn|1
+|move|p2b: Clefairy|Follow Me|p2b: Clefairy
+|-singleturn|p2b: Clefairy|move: Follow Me
+|move|p1a: Amoonguss|Spore|p2b: Clefairy
+|-status|p2b: Clefairy|slp
+|upkeep
+|turn|2
+|move|p1b: Indeedee|Ally Switch|p1b: Indeedee
+|-activate|p1b: Indeedee|move: Ally Switch|[of] p1a: Amoonguss
+|upkeep
+|turn|3
+|win|Alice
+`
+
+func TestParseShowdownLogRedirects(t *testing.T) {
+	summary, err := ParseShowdownLog(redirectLog)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	checkRedirectTurns(t, summary.Turns)
+}
+
+func TestParseEnhancedShowdownLogRedirects(t *testing.T) {
+	summary, err := ParseEnhancedShowdownLog(redirectLog)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	checkRedirectTurns(t, summary.Turns)
+}
+
+func checkRedirectTurns(t *testing.T, turns []Turn) {
+	t.Helper()
+	if len(turns) < 2 {
+		t.Fatalf("expected at least 2 turns, got %d", len(turns))
+	}
+
+	turn1 := turns[0]
+	if len(turn1.Redirects) != 1 {
+		t.Fatalf("expected 1 redirect on turn 1, got %d", len(turn1.Redirects))
+	}
+	followMe := turn1.Redirects[0]
+	if followMe.Pokemon != "Clefairy" || followMe.Player != "player2" || followMe.Move != "Follow Me" {
+		t.Errorf("expected Follow Me by player2's Clefairy, got %+v", followMe)
+	}
+	if !turn1.Actions[0].Redirect {
+		t.Error("expected the Follow Me action to be tagged as a redirect")
+	}
+	for _, action := range turn1.Actions[1:] {
+		if action.Redirect {
+			t.Errorf("expected only Follow Me tagged, got %+v", action)
+		}
+	}
+
+	turn2 := turns[1]
+	if len(turn2.Redirects) != 1 {
+		t.Fatalf("expected 1 redirect on turn 2, got %d", len(turn2.Redirects))
+	}
+	if allySwitch := turn2.Redirects[0]; allySwitch.Move != "Ally Switch" || allySwitch.Partner != "Amoonguss" {
+		t.Errorf("expected Ally Switch with Amoonguss, got %+v", allySwitch)
+	}
+}
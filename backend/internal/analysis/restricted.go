@@ -0,0 +1,113 @@
+package analysis
+
+import (
+	_ "embed"
+	"encoding/json"
+	"strings"
+)
+
+//go:embed data/restricted.json
+var restrictedData []byte
+
+// restrictedConfig mirrors data/restricted.json. New regulations only need a
+// new "formats" entry; new restricted species go in "restricted".
+type restrictedConfig struct {
+	Restricted []string          `json:"restricted"`
+	Formats    []formatAllowance `json:"formats"`
+}
+
+// formatAllowance is how many restricted Pokémon a format allows per team.
+// Match is a case-insensitive substring of the |tier| text.
+type formatAllowance struct {
+	Name              string `json:"name"`
+	Generation        int    `json:"generation"`
+	Match             string `json:"match"`
+	RestrictedAllowed int    `json:"restrictedAllowed"`
+}
+
+var restrictedRules = mustLoadRestricted(restrictedData)
+
+type restrictedIndex struct {
+	species map[string]bool
+	formats []formatAllowance
+}
+
+func mustLoadRestricted(data []byte) restrictedIndex {
+	var cfg restrictedConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		panic("analysis: invalid restricted.json: " + err.Error())
+	}
+	idx := restrictedIndex{species: make(map[string]bool), formats: cfg.Formats}
+	for _, name := range cfg.Restricted {
+		idx.species[strings.ToLower(name)] = true
+	}
+	return idx
+}
+
+// IsRestricted reports whether a species (or any of its forms, e.g.
+// "Calyrex-Shadow") is a restricted Pokémon.
+func IsRestricted(species string) bool {
+	name := strings.ToLower(strings.TrimSpace(species))
+	if restrictedRules.species[name] {
+		return true
+	}
+	// Forms are "Base-Form"; Ho-Oh is the only hyphenated base name and is
+	// matched exactly above.
+	if idx := strings.Index(name, "-"); idx > 0 {
+		return restrictedRules.species[name[:idx]]
+	}
+	return false
+}
+
+// RestrictedAllowance returns how many restricted Pokémon the format allows
+// per team. ok is false when the format isn't listed in the data file.
+func RestrictedAllowance(format string) (allowed int, ok bool) {
+	f := strings.ToLower(format)
+	for _, entry := range restrictedRules.formats {
+		if strings.Contains(f, strings.ToLower(entry.Match)) {
+			return entry.RestrictedAllowed, true
+		}
+	}
+	return 0, false
+}
+
+// RestrictedCount counts the restricted Pokémon each side revealed, keyed by
+// "player1", "player2", and so on for every seated player.
+func RestrictedCount(summary *BattleSummary) map[string]int {
+	counts := map[string]int{"player1": 0, "player2": 0}
+	if summary == nil {
+		return counts
+	}
+	for _, slot := range summary.slots() {
+		key := playerKey(slot)
+		counts[key] = 0
+		for _, poke := range summary.PlayerBySlot(slot).Team {
+			if IsRestricted(poke.Name) {
+				counts[key]++
+			}
+		}
+	}
+	return counts
+}
+
+// FormatInfo describes a format with dedicated ruleset data.
+type FormatInfo struct {
+	Name              string `json:"name"`
+	Generation        int    `json:"generation"`
+	RestrictedAllowed int    `json:"restrictedAllowed"`
+	FullMechanics     bool   `json:"fullMechanics"` // Every generation-specific mechanic (Tera, Dynamax, ...) is parsed
+}
+
+// SupportedFormats lists the formats in the embedded ruleset data, in file order.
+func SupportedFormats() []FormatInfo {
+	formats := make([]FormatInfo, 0, len(restrictedRules.formats))
+	for _, entry := range restrictedRules.formats {
+		formats = append(formats, FormatInfo{
+			Name:              entry.Name,
+			Generation:        entry.Generation,
+			RestrictedAllowed: entry.RestrictedAllowed,
+			FullMechanics:     fullMechanicSupport(entry.Generation),
+		})
+	}
+	return formats
+}
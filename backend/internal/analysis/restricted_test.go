@@ -0,0 +1,78 @@
+package analysis
+
+import "testing"
+
+func TestRestrictedCountFlagsRegH(t *testing.T) {
+	log := `|player|p1|Alice|1|
+|player|p2|Bob|2|
+|tier|[Gen 9] VGC 2025 Reg H (Bo3)
+|poke|p1|Calyrex-Shadow, L50|
+|poke|p1|Incineroar, L50, M|
+|poke|p2|Amoonguss, L50, F|
+|start
+|turn|1
+|win|Alice
+`
+	summary, err := ParseShowdownLog(log)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	counts := RestrictedCount(summary)
+	if counts["player1"] != 1 || counts["player2"] != 0 {
+		t.Errorf("expected 1 and 0 restricted, got %v", counts)
+	}
+
+	allowed, ok := RestrictedAllowance(summary.Format)
+	if !ok {
+		t.Fatalf("expected an allowance for %q", summary.Format)
+	}
+	if counts["player1"] <= allowed {
+		t.Errorf("expected player1 to exceed the Reg H allowance of %d", allowed)
+	}
+}
+
+func TestIsRestricted(t *testing.T) {
+	tests := []struct {
+		species  string
+		expected bool
+	}{
+		{"Miraidon", true},
+		{"Calyrex-Ice", true},
+		{"Ho-Oh", true},
+		{"Ursaluna-Bloodmoon", false},
+		{"Incineroar", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsRestricted(tt.species); got != tt.expected {
+			t.Errorf("IsRestricted(%q): expected %v, got %v", tt.species, tt.expected, got)
+		}
+	}
+}
+
+func TestRestrictedAllowanceUnknownFormat(t *testing.T) {
+	if _, ok := RestrictedAllowance("[Gen 9] Random Battle"); ok {
+		t.Error("expected no allowance for an unlisted format")
+	}
+}
+
+func TestSupportedFormats(t *testing.T) {
+	formats := SupportedFormats()
+
+	found := map[string]FormatInfo{}
+	for _, f := range formats {
+		found[f.Name] = f
+	}
+
+	regH, ok := found["[Gen 9] VGC 2025 Reg H"]
+	if !ok {
+		t.Fatalf("expected Reg H in supported formats, got %v", formats)
+	}
+	if !regH.FullMechanics || regH.Generation != 9 {
+		t.Errorf("expected Reg H to be gen 9 with full mechanics, got %+v", regH)
+	}
+	if gen8, ok := found["[Gen 8] VGC 2022"]; !ok || gen8.FullMechanics {
+		t.Errorf("expected gen 8 without full mechanics (Dynamax isn't parsed), got %+v", gen8)
+	}
+}
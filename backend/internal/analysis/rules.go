@@ -0,0 +1,53 @@
+package analysis
+
+import (
+	"fmt"
+	"strings"
+)
+
+// HasClause reports whether the battle was played under the named rule.
+// The match is case-insensitive against the rule name, i.e. the text before
+// the ":" in lines such as "Species Clause: Limit one of each Pokémon".
+func (s *BattleSummary) HasClause(name string) bool {
+	want := strings.TrimSpace(name)
+	for _, rule := range s.Rules {
+		if strings.EqualFold(ruleName(rule), want) {
+			return true
+		}
+	}
+	return false
+}
+
+// ruleName extracts the rule name from a |rule| line's text.
+func ruleName(rule string) string {
+	if idx := strings.Index(rule, ":"); idx >= 0 {
+		return strings.TrimSpace(rule[:idx])
+	}
+	return strings.TrimSpace(rule)
+}
+
+// checkBringCount cross-checks each player's |teamsize| and the Pokémon that
+// actually switched in against the |teampreview| bring count, returning a
+// warning for every player over the limit.
+func checkBringCount(summary *BattleSummary, tracker *StateTracker) []string {
+	if summary.BringCount <= 0 {
+		return nil
+	}
+
+	// Each "p1: Nickname" key is a distinct Pokémon that switched in
+	appeared := make(map[string]int)
+	for key := range tracker.species {
+		appeared[extractRawPlayerID(key)]++
+	}
+
+	var warnings []string
+	for _, slot := range summary.slots() {
+		if size := tracker.teamSizes[slot]; size > summary.BringCount {
+			warnings = append(warnings, fmt.Sprintf("%s teamsize %d exceeds bring count %d", slot, size, summary.BringCount))
+		}
+		if n := appeared[slot]; n > summary.BringCount {
+			warnings = append(warnings, fmt.Sprintf("%s used %d Pokémon but could bring %d", slot, n, summary.BringCount))
+		}
+	}
+	return warnings
+}
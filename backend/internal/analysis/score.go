@@ -0,0 +1,51 @@
+package analysis
+
+import (
+	"strconv"
+	"strings"
+)
+
+// scoreLine formats each side's remaining Pokémon winner-first, e.g. "2-0"
+// for a win with two Pokémon left. Draws list every side in seat order
+// ("0-0" when the last Pokémon fainted together); unfinished battles have no
+// score.
+func scoreLine(summary *BattleSummary) string {
+	if summary.Winner == "" && !summary.Draw {
+		return ""
+	}
+
+	slots := summary.slots()
+	if winner := summary.PlayerBySlot(summary.Winner); winner != nil {
+		ordered := make([]string, 0, len(slots))
+		for _, slot := range slots {
+			if summary.PlayerBySlot(slot) == winner {
+				ordered = append([]string{slot}, ordered...)
+			} else {
+				ordered = append(ordered, slot)
+			}
+		}
+		slots = ordered
+	}
+
+	counts := make([]string, 0, len(slots))
+	for _, slot := range slots {
+		counts = append(counts, strconv.Itoa(remainingPokemon(summary, summary.PlayerBySlot(slot))))
+	}
+	return strings.Join(counts, "-")
+}
+
+// remainingPokemon is how many of the Pokémon a player brought are still
+// standing; a team listed at preview only counts up to the bring count.
+func remainingPokemon(summary *BattleSummary, player *Player) int {
+	if player == nil {
+		return 0
+	}
+	brought := player.TotalLeft + player.Losses
+	if summary.BringCount > 0 && brought > summary.BringCount {
+		brought = summary.BringCount
+	}
+	if left := brought - player.Losses; left > 0 {
+		return left
+	}
+	return 0
+}
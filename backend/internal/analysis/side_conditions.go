@@ -0,0 +1,102 @@
+package analysis
+
+import "strings"
+
+// trackedConditions maps lower-cased condition names to their display names.
+// Trick Room, Gravity, Wonder Room and Magic Room are field-wide; the rest
+// apply to one side.
+var trackedConditions = map[string]string{
+	"tailwind":     "Tailwind",
+	"reflect":      "Reflect",
+	"light screen": "Light Screen",
+	"aurora veil":  "Aurora Veil",
+	"trick room":   "Trick Room",
+	"gravity":      "Gravity",
+	"wonder room":  "Wonder Room",
+	"magic room":   "Magic Room",
+}
+
+// conditionName normalizes "move: Tailwind" or "Tailwind" to "Tailwind".
+// It returns "" for conditions that aren't tracked.
+func conditionName(effect string) string {
+	effect = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(effect), "move:"))
+	return trackedConditions[strings.ToLower(effect)]
+}
+
+// startSideCondition opens a condition on a side ("player1", "player2", or
+// "field"). lastMove is the most recent |move| line, used to attribute the
+// condition to the Pokémon whose move set it.
+func startSideCondition(summary *BattleSummary, side, effect string, turn int, lastMove []string, of string) {
+	name := conditionName(effect)
+	if name == "" {
+		return
+	}
+
+	condition := SideCondition{
+		Condition: name,
+		Side:      side,
+		StartTurn: turn,
+	}
+
+	switch {
+	case of != "":
+		condition.SetBy = extractNickname(of)
+	case len(lastMove) >= 4 && strings.EqualFold(strings.TrimSpace(lastMove[3]), name):
+		moveSide := extractPlayerIDFromRef(lastMove[2])
+		if side == "field" || side == moveSide {
+			condition.SetBy = extractNickname(lastMove[2])
+		}
+	}
+	if condition.SetBy != "" {
+		condition.Move = name
+	}
+
+	summary.SideConditions = append(summary.SideConditions, condition)
+}
+
+// endSideCondition closes the most recent open condition of that name on a side.
+func endSideCondition(summary *BattleSummary, side, effect string, turn int) {
+	name := conditionName(effect)
+	if name == "" {
+		return
+	}
+
+	for i := len(summary.SideConditions) - 1; i >= 0; i-- {
+		c := &summary.SideConditions[i]
+		if c.Condition == name && c.Side == side && c.EndTurn == 0 {
+			c.EndTurn = turn
+			return
+		}
+	}
+}
+
+// activateFieldCondition handles |-fieldactivate|move: Gravity, which
+// announces a field condition taking effect. It only opens a condition when
+// none is open already, e.g. when the log starts after it was set.
+func activateFieldCondition(summary *BattleSummary, effect string, turn int, lastMove []string, of string) {
+	name := conditionName(effect)
+	if name == "" {
+		return
+	}
+	for _, c := range summary.SideConditions {
+		if c.Condition == name && c.Side == "field" && c.EndTurn == 0 {
+			return
+		}
+	}
+	startSideCondition(summary, "field", effect, turn, lastMove, of)
+}
+
+// sideFromRef converts a side reference like "p1: Player1" to "player1".
+func sideFromRef(ref string) string {
+	return extractPlayerIDFromRef(strings.TrimSpace(ref))
+}
+
+// ofSource returns the Pokémon named by an "[of] p1a: Farigiraf" annotation, if any.
+func ofSource(parts []string) string {
+	for _, part := range parts {
+		if strings.HasPrefix(part, "[of]") {
+			return strings.TrimSpace(strings.TrimPrefix(part, "[of]"))
+		}
+	}
+	return ""
+}
@@ -0,0 +1,133 @@
+package analysis
+
+import "testing"
+
+func TestParseShowdownLogSideConditions(t *testing.T) {
+	log := `|player|p1|Player1|test|1500
+|player|p2|Player2|test|1500
+|poke|p1|Whimsicott, L50, M|
+|poke|p1|Farigiraf, L50, F|
+|poke|p2|Grimmsnarl, L50, M|
+|start
+|switch|p1a: Whimsicott|Whimsicott, L50, M|100/100
+|switch|p1b: Farigiraf|Farigiraf, L50, F|100/100
+|switch|p2a: Grimmsnarl|Grimmsnarl, L50, M|100/100
+|turn|1
+|move|p1a: Whimsicott|Tailwind|p1a: Whimsicott
+|-sidestart|p1: Player1|move: Tailwind
+|move|p2a: Grimmsnarl|Reflect|p2a: Grimmsnarl
+|-sidestart|p2: Player2|Reflect
+|move|p1b: Farigiraf|Trick Room|p1b: Farigiraf
+|-fieldstart|move: Trick Room|[of] p1b: Farigiraf
+|upkeep
+|turn|2
+|upkeep
+|turn|3
+|upkeep
+|turn|4
+|-sideend|p1: Player1|move: Tailwind
+|upkeep
+|turn|5
+|-fieldend|move: Trick Room
+|upkeep
+|win|Player1`
+
+	summary, _ := ParseShowdownLog(log)
+
+	if len(summary.SideConditions) != 3 {
+		t.Fatalf("expected 3 side conditions, got %d: %+v", len(summary.SideConditions), summary.SideConditions)
+	}
+
+	tailwind := summary.SideConditions[0]
+	if tailwind.Condition != "Tailwind" || tailwind.Side != "player1" {
+		t.Errorf("expected player1 Tailwind, got %+v", tailwind)
+	}
+	if tailwind.StartTurn != 1 || tailwind.EndTurn != 4 {
+		t.Errorf("expected Tailwind from turn 1 to 4, got %d to %d", tailwind.StartTurn, tailwind.EndTurn)
+	}
+	if tailwind.SetBy != "Whimsicott" || tailwind.Move != "Tailwind" {
+		t.Errorf("expected Tailwind set by Whimsicott, got %q (%q)", tailwind.SetBy, tailwind.Move)
+	}
+
+	reflect := summary.SideConditions[1]
+	if reflect.Condition != "Reflect" || reflect.Side != "player2" || reflect.EndTurn != 0 {
+		t.Errorf("expected an open player2 Reflect, got %+v", reflect)
+	}
+	if reflect.SetBy != "Grimmsnarl" {
+		t.Errorf("expected Reflect set by Grimmsnarl, got %q", reflect.SetBy)
+	}
+
+	trickRoom := summary.SideConditions[2]
+	if trickRoom.Condition != "Trick Room" || trickRoom.Side != "field" {
+		t.Errorf("expected field Trick Room, got %+v", trickRoom)
+	}
+	if trickRoom.StartTurn != 1 || trickRoom.EndTurn != 5 || trickRoom.SetBy != "Farigiraf" {
+		t.Errorf("expected Trick Room set by Farigiraf from turn 1 to 5, got %+v", trickRoom)
+	}
+}
+
+func TestParseShowdownLogFieldRooms(t *testing.T) {
+	log := `|player|p1|Player1|test|1500
+|player|p2|Player2|test|1500
+|start
+|switch|p1a: Farigiraf|Farigiraf, L50, F|100/100
+|switch|p2a: Hatterene|Hatterene, L50, F|100/100
+|turn|1
+|move|p1a: Farigiraf|Gravity|p1a: Farigiraf
+|-fieldstart|move: Gravity
+|-fieldactivate|move: Gravity
+|move|p2a: Hatterene|Magic Room|p2a: Hatterene
+|-fieldstart|move: Magic Room|[of] p2a: Hatterene
+|upkeep
+|turn|2
+|-fieldactivate|move: Wonder Room
+|upkeep
+|turn|3
+|-fieldend|move: Gravity
+|upkeep
+|win|Player1`
+
+	summary, _ := ParseShowdownLog(log)
+
+	if len(summary.SideConditions) != 3 {
+		t.Fatalf("expected 3 field conditions, got %d: %+v", len(summary.SideConditions), summary.SideConditions)
+	}
+
+	gravity := summary.SideConditions[0]
+	if gravity.Condition != "Gravity" || gravity.Side != "field" {
+		t.Errorf("expected field Gravity, got %+v", gravity)
+	}
+	if gravity.StartTurn != 1 || gravity.EndTurn != 3 || gravity.SetBy != "Farigiraf" {
+		t.Errorf("expected Gravity set by Farigiraf from turn 1 to 3, got %+v", gravity)
+	}
+
+	magicRoom := summary.SideConditions[1]
+	if magicRoom.Condition != "Magic Room" || magicRoom.SetBy != "Hatterene" || magicRoom.EndTurn != 0 {
+		t.Errorf("expected an open Magic Room set by Hatterene, got %+v", magicRoom)
+	}
+
+	// An activation with nothing open starts the condition
+	wonderRoom := summary.SideConditions[2]
+	if wonderRoom.Condition != "Wonder Room" || wonderRoom.StartTurn != 2 {
+		t.Errorf("expected Wonder Room from turn 2, got %+v", wonderRoom)
+	}
+}
+
+func TestConditionName(t *testing.T) {
+	tests := map[string]string{
+		"move: Tailwind":     "Tailwind",
+		"Reflect":            "Reflect",
+		"move: Light Screen": "Light Screen",
+		"move: Aurora Veil":  "Aurora Veil",
+		"move: Trick Room":   "Trick Room",
+		"move: Gravity":      "Gravity",
+		"move: Wonder Room":  "Wonder Room",
+		"Spikes":             "",
+	}
+
+	for input, expected := range tests {
+		if got := conditionName(input); got != expected {
+			t.Errorf("conditionName(%q): expected %q, got %q", input, expected, got)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package analysis
+
+import "strings"
+
+// Showdown tags some lines so clients don't announce them. The parser treats
+// them as follows:
+//
+//   - [silent] on -damage, -heal and -status: the change is real, so it still
+//     updates tracked HP and status, but it isn't an event of its own. It adds
+//     no residual entry and nothing to the last move's impact or hit count.
+//     Damage totals come from HP deltas, so a silent line repeating an HP
+//     already announced adds nothing to them.
+//   - [still] on |move|: the move was shown without executing, as on a Solar
+//     Beam charge turn or a move that failed for lack of a target. It stays in
+//     the turn's actions flagged Still, but isn't counted in MoveFrequency or
+//     per-player move counts.
+
+// hasTag reports whether a log line carries an annotation such as "[silent]".
+func hasTag(parts []string, tag string) bool {
+	if len(parts) < 3 {
+		return false
+	}
+	for _, part := range parts[3:] {
+		if strings.TrimSpace(part) == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// isSilent reports whether an HP or status line is tagged [silent].
+func isSilent(parts []string) bool {
+	return hasTag(parts, "[silent]")
+}
+
+// isStill reports whether a |move| line is tagged [still].
+func isStill(parts []string) bool {
+	return hasTag(parts, "[still]")
+}
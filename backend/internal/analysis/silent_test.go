@@ -0,0 +1,64 @@
+package analysis
+
+import "testing"
+
+func TestParseShowdownLogSilentAndStill(t *testing.T) {
+	log := `|player|p1|Alice|1|
+|player|p2|Bob|2|
+|start
+|switch|p1a: Venusaur|Venusaur, L50, F|100/100
+|switch|p2a: Incineroar|Incineroar, L50, M|100/100
+|turn|1
+|move|p1a: Venusaur|Solar Beam||[still]
+|-prepare|p1a: Venusaur|Solar Beam
+|move|p2a: Incineroar|Flare Blitz|p1a: Venusaur
+|-supereffective|p1a: Venusaur
+|-damage|p1a: Venusaur|40/100
+|-damage|p1a: Venusaur|40/100|[silent]
+|-damage|p2a: Incineroar|90/100|[from] Recoil
+|upkeep
+|-heal|p1a: Venusaur|46/100|[from] item: Leftovers
+|-heal|p1a: Venusaur|46/100|[silent]
+|turn|2
+|move|p1a: Venusaur|Solar Beam|p2a: Incineroar|[from]lockedmove
+|-resisted|p2a: Incineroar
+|-damage|p2a: Incineroar|75/100
+|upkeep
+|win|Alice
+`
+	for name, parse := range map[string]func(string) (*BattleSummary, error){
+		"basic":    ParseShowdownLog,
+		"enhanced": ParseEnhancedShowdownLog,
+	} {
+		summary, err := parse(log)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+
+		charge := summary.Turns[0].Actions[0]
+		if !charge.Still {
+			t.Errorf("%s: expected the charge turn to be flagged still, got %+v", name, charge)
+		}
+		if got := summary.Stats.MoveFrequency["solar beam"]; got != 1 {
+			t.Errorf("%s: expected Solar Beam counted once, got %d", name, got)
+		}
+		if got := summary.Stats.Player1Stats.MoveCount; got != 1 {
+			t.Errorf("%s: expected 1 move for player1, got %d", name, got)
+		}
+
+		// The silent heal repeats Leftovers' HP and isn't a second residual event
+		residual := summary.Turns[0].Residual
+		if len(residual) != 1 || residual[0].Source != "Leftovers" {
+			t.Errorf("%s: expected only the Leftovers heal as residual, got %+v", name, residual)
+		}
+		if got := summary.Stats.DamageTakenByPokemon["p1: Venusaur"]; got != 60 {
+			t.Errorf("%s: expected Venusaur to take 60%% once, got %v", name, got)
+		}
+	}
+
+	enhanced, _ := ParseEnhancedShowdownLog(log)
+	blitz := enhanced.Turns[0].Actions[1]
+	if blitz.Impact == nil || len(blitz.Impact.Targets) != 1 || blitz.Impact.Targets[0].Damage != 60 {
+		t.Errorf("expected Flare Blitz to deal 60 to one target, got %+v", blitz.Impact)
+	}
+}
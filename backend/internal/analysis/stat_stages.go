@@ -0,0 +1,89 @@
+package analysis
+
+import "strings"
+
+// statStages tracks the stat stages of each field position ("p1a" -> "atk"
+// -> +2). Stages belong to the Pokémon in the position and are cleared when
+// it switches out, unless it left by Baton Pass, which hands them to the
+// Pokémon switching in. Protosynthesis and Quark Drive boosts are tracked
+// alongside them but never passed.
+type statStages struct {
+	stages      map[string]map[string]int
+	batonPass   map[string]bool   // Positions whose latest move was Baton Pass
+	paradox     map[string]string // Stat boosted by Protosynthesis or Quark Drive
+	fromBooster map[string]bool   // Paradox activations pending their -start line
+}
+
+func newStatStages() *statStages {
+	return &statStages{
+		stages:      make(map[string]map[string]int),
+		batonPass:   make(map[string]bool),
+		paradox:     make(map[string]string),
+		fromBooster: make(map[string]bool),
+	}
+}
+
+// move notes whether a position is about to Baton Pass.
+func (s *statStages) move(parts []string) {
+	if len(parts) < 4 {
+		return
+	}
+	s.batonPass[fieldPosition(parts[2])] = normalizeID(strings.TrimSpace(parts[3])) == "baton pass"
+}
+
+// process applies a -boost, -unboost, -clearboost, -clearallboost or faint line.
+func (s *statStages) process(parts []string) {
+	if parts[1] == "-clearallboost" {
+		// Haze
+		clear(s.stages)
+		return
+	}
+	if len(parts) < 3 {
+		return
+	}
+	position := fieldPosition(parts[2])
+
+	switch parts[1] {
+	case "-boost", "-unboost":
+		if len(parts) < 5 {
+			return
+		}
+		amount := parseInt(parts[4])
+		if parts[1] == "-unboost" {
+			amount = -amount
+		}
+		if s.stages[position] == nil {
+			s.stages[position] = make(map[string]int)
+		}
+		stat := strings.TrimSpace(parts[3])
+		s.stages[position][stat] = max(-6, min(6, s.stages[position][stat]+amount))
+	case "-clearboost":
+		delete(s.stages, position)
+	case "faint":
+		delete(s.stages, position)
+		delete(s.paradox, position)
+	}
+}
+
+// switchIn handles a Pokémon switching into ref's position. After a Baton
+// Pass it keeps the position's stages and returns them; otherwise it clears
+// them and returns nil.
+func (s *statStages) switchIn(ref string) (passed map[string]int, batonPass bool) {
+	position := fieldPosition(ref)
+	batonPass = s.batonPass[position]
+	delete(s.batonPass, position)
+	delete(s.paradox, position)
+	delete(s.fromBooster, position)
+	if !batonPass {
+		delete(s.stages, position)
+		return nil, false
+	}
+
+	passed = make(map[string]int, len(s.stages[position]))
+	for stat, stage := range s.stages[position] {
+		if stage != 0 {
+			passed[stat] = stage
+		}
+	}
+	return passed, true
+}
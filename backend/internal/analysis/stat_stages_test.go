@@ -0,0 +1,59 @@
+package analysis
+
+import "testing"
+
+func TestParseShowdownLogBatonPass(t *testing.T) {
+	log := `|player|p1|Alice|1|
+|player|p2|Bob|2|
+|start
+|switch|p1a: Espathra|Espathra, L50, F|100/100
+|switch|p2a: Garchomp|Garchomp, L50, M|100/100
+|turn|1
+|move|p1a: Espathra|Calm Mind|p1a: Espathra
+|-boost|p1a: Espathra|spa|1
+|-boost|p1a: Espathra|spd|1
+|move|p2a: Garchomp|Scary Face|p1a: Espathra
+|-unboost|p1a: Espathra|spe|2
+|upkeep
+|turn|2
+|move|p1a: Espathra|Baton Pass|p1a: Espathra
+|switch|p1a: Gardevoir|Gardevoir, L50, F|100/100
+|move|p2a: Garchomp|Swords Dance|p2a: Garchomp
+|-boost|p2a: Garchomp|atk|2
+|upkeep
+|turn|3
+|switch|p2a: Dragonite|Dragonite, L50, M|100/100
+|move|p1a: Gardevoir|Moonblast|p2a: Dragonite
+|-damage|p2a: Dragonite|20/100
+|upkeep
+|win|Alice
+`
+	for name, parse := range map[string]func(string) (*BattleSummary, error){
+		"basic":    ParseShowdownLog,
+		"enhanced": ParseEnhancedShowdownLog,
+	} {
+		summary, err := parse(log)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+
+		pass := summary.Turns[1].Actions[1]
+		if pass.ActionType != "switch" || !pass.BatonPass {
+			t.Fatalf("%s: expected a Baton Pass switch, got %+v", name, pass)
+		}
+		want := map[string]int{"spa": 1, "spd": 1, "spe": -2}
+		if len(pass.Boosts) != len(want) {
+			t.Errorf("%s: expected boosts %v to carry over, got %v", name, want, pass.Boosts)
+		}
+		for stat, stage := range want {
+			if pass.Boosts[stat] != stage {
+				t.Errorf("%s: expected %s %+d, got %+d", name, stat, stage, pass.Boosts[stat])
+			}
+		}
+
+		plain := summary.Turns[2].Actions[0]
+		if plain.BatonPass || plain.Boosts != nil {
+			t.Errorf("%s: expected a regular switch to drop Garchomp's boosts, got %+v", name, plain)
+		}
+	}
+}
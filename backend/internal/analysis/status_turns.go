@@ -0,0 +1,70 @@
+package analysis
+
+import "strings"
+
+// statusTimer counts how long Pokémon stay asleep or frozen, from
+// |-status|p2a: Snorlax|slp to |-curestatus|p2a: Snorlax|slp|[msg].
+type statusTimer struct {
+	started map[string]int  // "p2: Snorlax" -> turn the status began
+	rest    map[string]bool // "p2: Snorlax" -> the sleep came from Rest
+}
+
+func newStatusTimer() *statusTimer {
+	return &statusTimer{
+		started: make(map[string]int),
+		rest:    make(map[string]bool),
+	}
+}
+
+// luckStatuses end after a random number of turns; the rest don't wear off.
+var luckStatuses = map[string]bool{"slp": true, "frz": true}
+
+// statusKey keys a Pokémon by side and species, like DamageTakenByPokemon.
+func statusKey(tracker *StateTracker, ref string) string {
+	return extractRawPlayerID(ref) + ": " + tracker.SpeciesFor(ref)
+}
+
+// start handles a -status line. Rest always sleeps for two turns, so it is
+// timed separately from sleep inflicted by moves.
+func (t *statusTimer) start(tracker *StateTracker, parts []string, turn int) {
+	if len(parts) < 4 || !luckStatuses[strings.TrimSpace(parts[3])] {
+		return
+	}
+	key := statusKey(tracker, parts[2])
+	t.started[key] = turn
+	t.rest[key] = false
+	for _, part := range parts[4:] {
+		if strings.EqualFold(strings.TrimSpace(strings.TrimPrefix(part, "[from]")), "move: Rest") {
+			t.rest[key] = true
+		}
+	}
+}
+
+// cure handles a -curestatus line, crediting the turns spent under the status.
+func (t *statusTimer) cure(summary *BattleSummary, tracker *StateTracker, parts []string, turn int) {
+	if len(parts) < 4 || !luckStatuses[strings.TrimSpace(parts[3])] {
+		return
+	}
+	t.stop(summary, statusKey(tracker, parts[2]), turn)
+}
+
+// finish credits statuses still in effect when the battle ended.
+func (t *statusTimer) finish(summary *BattleSummary, turn int) {
+	for key := range t.started {
+		t.stop(summary, key, turn)
+	}
+}
+
+func (t *statusTimer) stop(summary *BattleSummary, key string, turn int) {
+	start, ok := t.started[key]
+	if !ok {
+		return
+	}
+	if t.rest[key] {
+		summary.Stats.RestTurns[key] += turn - start
+	} else {
+		summary.Stats.StatusTurns[key] += turn - start
+	}
+	delete(t.started, key)
+	delete(t.rest, key)
+}
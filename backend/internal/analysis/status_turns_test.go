@@ -0,0 +1,51 @@
+package analysis
+
+import "testing"
+
+func TestParseShowdownLogStatusTurns(t *testing.T) {
+	log := `|player|p1|Alice|1|
+|player|p2|Bob|2|
+|start
+|switch|p1a: Amoonguss|Amoonguss, L50, F|100/100
+|switch|p2a: Snorlax|Snorlax, L50, M|100/100
+|switch|p2b: Cresselia|Cresselia, L50, F|100/100
+|turn|1
+|move|p1a: Amoonguss|Spore|p2a: Snorlax
+|-status|p2a: Snorlax|slp
+|move|p2b: Cresselia|Rest|p2b: Cresselia
+|-status|p2b: Cresselia|slp|[from] move: Rest
+|upkeep
+|turn|2
+|cant|p2a: Snorlax|slp
+|cant|p2b: Cresselia|slp
+|upkeep
+|turn|3
+|cant|p2a: Snorlax|slp
+|-curestatus|p2b: Cresselia|slp|[msg]
+|upkeep
+|turn|4
+|-curestatus|p2a: Snorlax|slp|[msg]
+|move|p2a: Snorlax|Body Slam|p1a: Amoonguss
+|move|p1a: Amoonguss|Ice Beam|p2a: Snorlax
+|-status|p2a: Snorlax|frz
+|upkeep
+|turn|5
+|upkeep
+|win|Alice
+`
+	summary, err := ParseShowdownLog(log)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Asleep from turn 1 to 4 (3 turns), then frozen from turn 4 until the end on turn 5
+	if got := summary.Stats.StatusTurns["p2: Snorlax"]; got != 4 {
+		t.Errorf("expected Snorlax to spend 4 turns asleep or frozen, got %d", got)
+	}
+	if got := summary.Stats.RestTurns["p2: Cresselia"]; got != 2 {
+		t.Errorf("expected Cresselia to spend 2 turns asleep from Rest, got %d", got)
+	}
+	if _, ok := summary.Stats.StatusTurns["p2: Cresselia"]; ok {
+		t.Error("expected Rest sleep not to count as status turns")
+	}
+}
@@ -0,0 +1,127 @@
+package analysis
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// nearLethalPercent is the most HP a Pokémon can be left with for a
+	// Sash/Sturdy/Endure activation to count as surviving a KO.
+	nearLethalPercent = 10
+	// substitutePercent is the HP a Substitute costs; a Pokémon at or below
+	// this when its Substitute breaks would have been KO'd by the hit.
+	substitutePercent = 25
+)
+
+// survivalTracker correlates survival effects (Focus Sash, Sturdy, Endure,
+// Substitute) with the damage around them to detect hits that would have KO'd.
+type survivalTracker struct {
+	hp          map[string]int    // pokemonKey -> current HP percent
+	damaged     map[string]bool   // pokemonKey -> took damage during the current action
+	pending     map[string]string // pokemonKey -> source awaiting its damage line
+	substitutes map[string]bool   // pokemonKey -> Substitute absorbed a hit this action
+}
+
+func newSurvivalTracker() *survivalTracker {
+	return &survivalTracker{
+		hp:          make(map[string]int),
+		damaged:     make(map[string]bool),
+		pending:     make(map[string]string),
+		substitutes: make(map[string]bool),
+	}
+}
+
+// startAction resets per-action state when a new move or turn begins.
+func (s *survivalTracker) startAction() {
+	clear(s.damaged)
+	clear(s.pending)
+	clear(s.substitutes)
+}
+
+func (s *survivalTracker) setHP(ref, hpStr string) {
+	s.hp[pokemonKey(ref)] = hpPercent(hpStr)
+}
+
+// hpOf returns a Pokémon's last known HP percent, assuming full HP if unseen.
+func (s *survivalTracker) hpOf(ref string) int {
+	if hp, ok := s.hp[pokemonKey(ref)]; ok {
+		return hp
+	}
+	return 100
+}
+
+// damage records a -damage line and reports the source of a survival it confirms.
+func (s *survivalTracker) damage(ref, hpStr string) (string, bool) {
+	key := pokemonKey(ref)
+	s.hp[key] = hpPercent(hpStr)
+	s.damaged[key] = true
+
+	source, ok := s.pending[key]
+	if !ok {
+		return "", false
+	}
+	delete(s.pending, key)
+	return source, s.nearLethal(key)
+}
+
+// activate records a survival effect and reports whether its damage already
+// left the Pokémon at near-lethal HP. Otherwise it waits for the damage line.
+func (s *survivalTracker) activate(ref, source string) bool {
+	key := pokemonKey(ref)
+	if s.damaged[key] {
+		return s.nearLethal(key)
+	}
+	s.pending[key] = source
+	return false
+}
+
+// substituteHit records a Substitute absorbing a hit.
+func (s *survivalTracker) substituteHit(ref string) {
+	s.substitutes[pokemonKey(ref)] = true
+}
+
+// substituteBroken reports whether a broken Substitute saved its user from a KO.
+func (s *survivalTracker) substituteBroken(ref string) bool {
+	key := pokemonKey(ref)
+	if !s.substitutes[key] {
+		return false
+	}
+	delete(s.substitutes, key)
+	hp, ok := s.hp[key]
+	return ok && hp > 0 && hp <= substitutePercent
+}
+
+func (s *survivalTracker) nearLethal(key string) bool {
+	hp := s.hp[key]
+	return hp > 0 && hp <= nearLethalPercent
+}
+
+// survivalSource returns the item, ability, or move behind a survival effect
+// such as "item: Focus Sash", or "" if the effect doesn't prevent KOs.
+func survivalSource(effect string) string {
+	effect = strings.TrimSpace(effect)
+	switch strings.ToLower(effect) {
+	case "focus sash", "item: focus sash":
+		return "item: Focus Sash"
+	case "item: focus band":
+		return "item: Focus Band"
+	case "ability: sturdy":
+		return "ability: Sturdy"
+	case "move: endure", "endure":
+		return "move: Endure"
+	}
+	return ""
+}
+
+// addSurvivedMoment records a Pokémon living through a hit that would have KO'd it.
+func addSurvivedMoment(summary *BattleSummary, turnNumber int, ref, source string) {
+	name := strings.TrimPrefix(strings.TrimPrefix(strings.TrimPrefix(source, "item: "), "ability: "), "move: ")
+	summary.KeyMoments = append(summary.KeyMoments, KeyMoment{
+		TurnNumber:   turnNumber,
+		Type:         "Survived",
+		Description:  fmt.Sprintf("%s survived a KO with %s", extractNickname(ref), name),
+		Significance: 6,
+		Source:       source,
+	})
+}
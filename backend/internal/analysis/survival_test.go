@@ -0,0 +1,112 @@
+package analysis
+
+import "testing"
+
+func survivalLog(events string) string {
+	return `|player|p1|Player1|test|1500
+|player|p2|Player2|test|1500
+|poke|p1|Garchomp, L50, M|
+|poke|p2|Whimsicott, L50, F|
+|start
+|switch|p1a: Garchomp|Garchomp, L50, M|100/100
+|switch|p2a: Whimsicott|Whimsicott, L50, F|100/100
+|turn|1
+` + events + `
+|upkeep
+|win|Player1`
+}
+
+func survivedMoments(summary *BattleSummary) []KeyMoment {
+	var moments []KeyMoment
+	for _, km := range summary.KeyMoments {
+		if km.Type == "Survived" {
+			moments = append(moments, km)
+		}
+	}
+	return moments
+}
+
+func TestParseShowdownLogFocusSashSurvival(t *testing.T) {
+	log := survivalLog(`|move|p1a: Garchomp|Dragon Claw|p2a: Whimsicott
+|-enditem|p2a: Whimsicott|Focus Sash
+|-damage|p2a: Whimsicott|1/100`)
+
+	summary, _ := ParseShowdownLog(log)
+	moments := survivedMoments(summary)
+
+	if len(moments) != 1 {
+		t.Fatalf("expected 1 Survived moment, got %d: %+v", len(moments), summary.KeyMoments)
+	}
+	if moments[0].Source != "item: Focus Sash" {
+		t.Errorf("expected source item: Focus Sash, got %q", moments[0].Source)
+	}
+	if moments[0].TurnNumber != 1 {
+		t.Errorf("expected turn 1, got %d", moments[0].TurnNumber)
+	}
+	if moments[0].Description != "Whimsicott survived a KO with Focus Sash" {
+		t.Errorf("unexpected description %q", moments[0].Description)
+	}
+}
+
+func TestParseShowdownLogSurvivalEvents(t *testing.T) {
+	tests := []struct {
+		name           string
+		events         string
+		expectedSource string
+	}{
+		{
+			name: "sash activation after the damage line",
+			events: `|move|p1a: Garchomp|Dragon Claw|p2a: Whimsicott
+|-damage|p2a: Whimsicott|1/100
+|-activate|p2a: Whimsicott|item: Focus Sash`,
+			expectedSource: "item: Focus Sash",
+		},
+		{
+			name: "sturdy",
+			events: `|move|p1a: Garchomp|Dragon Claw|p2a: Whimsicott
+|-activate|p2a: Whimsicott|ability: Sturdy
+|-damage|p2a: Whimsicott|1/100`,
+			expectedSource: "ability: Sturdy",
+		},
+		{
+			name: "substitute breaking at low HP",
+			events: `|-damage|p2a: Whimsicott|20/100
+|move|p1a: Garchomp|Dragon Claw|p2a: Whimsicott
+|-activate|p2a: Whimsicott|move: Substitute|[damage]
+|-end|p2a: Whimsicott|Substitute`,
+			expectedSource: "move: Substitute",
+		},
+		{
+			name: "substitute breaking at high HP is not a survival",
+			events: `|move|p1a: Garchomp|Dragon Claw|p2a: Whimsicott
+|-activate|p2a: Whimsicott|move: Substitute|[damage]
+|-end|p2a: Whimsicott|Substitute`,
+			expectedSource: "",
+		},
+		{
+			name: "sturdy without near-lethal damage is not a survival",
+			events: `|move|p1a: Garchomp|Dragon Claw|p2a: Whimsicott
+|-activate|p2a: Whimsicott|ability: Sturdy
+|move|p2a: Whimsicott|Moonblast|p1a: Garchomp
+|-damage|p1a: Garchomp|40/100`,
+			expectedSource: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			summary, _ := ParseShowdownLog(survivalLog(tt.events))
+			moments := survivedMoments(summary)
+
+			if tt.expectedSource == "" {
+				if len(moments) != 0 {
+					t.Errorf("expected no Survived moments, got %+v", moments)
+				}
+				return
+			}
+			if len(moments) != 1 || moments[0].Source != tt.expectedSource {
+				t.Errorf("expected one Survived moment from %q, got %+v", tt.expectedSource, moments)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package analysis
+
+import "strconv"
+
+// swapPositions returns the two field positions a |swap| line exchanges:
+// |swap|p1a: Indeedee|1|[from] move: Ally Switch moves Indeedee from p1a to
+// p1b (positions count from 0), and whatever was in p1b to p1a. Later lines
+// name both Pokémon by their new positions.
+func swapPositions(parts []string) (from, to string, ok bool) {
+	if len(parts) < 4 {
+		return "", "", false
+	}
+	from = fieldPosition(parts[2])
+	index, err := strconv.Atoi(parts[3])
+	if err != nil || index < 0 || index > 2 || len(from) != 3 {
+		return "", "", false
+	}
+	to = from[:2] + string(rune('a'+index))
+	return from, to, from != to
+}
+
+// swap moves position-keyed state along with the Pokémon on a |swap| line.
+func (s *statStages) swap(parts []string) {
+	from, to, ok := swapPositions(parts)
+	if !ok {
+		return
+	}
+	s.stages[from], s.stages[to] = s.stages[to], s.stages[from]
+	s.batonPass[from], s.batonPass[to] = s.batonPass[to], s.batonPass[from]
+	s.paradox[from], s.paradox[to] = s.paradox[to], s.paradox[from]
+}
@@ -0,0 +1,79 @@
+package analysis
+
+import "testing"
+
+func TestParseShowdownLogSwapMovesStatStages(t *testing.T) {
+	log := `|player|p1|Alice|1|
+|player|p2|Bob|2|
+|gametype|doubles
+|start
+|switch|p1a: Indeedee|Indeedee-F, L50, F|100/100
+|switch|p1b: Espathra|Espathra, L50, F|100/100
+|switch|p2a: Garchomp|Garchomp, L50, M|100/100
+|switch|p2b: Rillaboom|Rillaboom, L50, M|100/100
+|turn|1
+|move|p1a: Indeedee|Ally Switch|p1a: Indeedee
+|swap|p1a: Indeedee|1|[from] move: Ally Switch
+|move|p1a: Espathra|Calm Mind|p1a: Espathra
+|-boost|p1a: Espathra|spa|1
+|-boost|p1a: Espathra|spd|1
+|move|p2a: Garchomp|Rock Slide|p1a: Espathra|[spread] p1a,p1b
+|-damage|p1a: Espathra|60/100
+|-damage|p1b: Indeedee|70/100
+|upkeep
+|turn|2
+|move|p1b: Indeedee|Ally Switch|p1b: Indeedee
+|swap|p1b: Indeedee|0|[from] move: Ally Switch
+|move|p1b: Espathra|Baton Pass|p1b: Espathra
+|switch|p1b: Farigiraf|Farigiraf, L50, M|100/100
+|upkeep
+|win|Alice
+`
+	for name, parse := range map[string]func(string) (*BattleSummary, error){
+		"basic":    ParseShowdownLog,
+		"enhanced": ParseEnhancedShowdownLog,
+	} {
+		t.Run(name, func(t *testing.T) {
+			summary, err := parse(log)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			calmMind := summary.Turns[0].Actions[1]
+			if calmMind.Pokemon != "Espathra" || calmMind.Species != "Espathra" {
+				t.Errorf("expected Calm Mind from Espathra after the swap, got %q (%q)", calmMind.Pokemon, calmMind.Species)
+			}
+
+			// Espathra's boosts followed it from p1a to p1b and on to Farigiraf,
+			// not to Indeedee, who swapped into p1b and back out
+			pass := summary.Turns[1].Actions[2]
+			if pass.ActionType != "switch" || !pass.BatonPass {
+				t.Fatalf("expected a Baton Pass switch, got %+v", pass)
+			}
+			if len(pass.Boosts) != 2 || pass.Boosts["spa"] != 1 || pass.Boosts["spd"] != 1 {
+				t.Errorf("expected Espathra's +1 SpA/SpD to be passed, got %v", pass.Boosts)
+			}
+		})
+	}
+}
+
+func TestSwapPositions(t *testing.T) {
+	tests := []struct {
+		line     []string
+		from, to string
+		ok       bool
+	}{
+		{line: []string{"", "swap", "p1a: Indeedee", "1", "[from] move: Ally Switch"}, from: "p1a", to: "p1b", ok: true},
+		{line: []string{"", "swap", "p2c: Dondozo", "0"}, from: "p2c", to: "p2a", ok: true},
+		{line: []string{"", "swap", "p1a: Indeedee", "0"}, from: "p1a", to: "p1a"},
+		{line: []string{"", "swap", "p1a: Indeedee"}},
+		{line: []string{"", "swap", "p1a: Indeedee", "left"}},
+	}
+
+	for _, tt := range tests {
+		from, to, ok := swapPositions(tt.line)
+		if ok != tt.ok || (ok && (from != tt.from || to != tt.to)) {
+			t.Errorf("swapPositions(%v) = %q, %q, %v; want %q, %q, %v", tt.line, from, to, ok, tt.from, tt.to, tt.ok)
+		}
+	}
+}
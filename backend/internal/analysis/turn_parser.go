@@ -1,6 +1,9 @@
 package analysis
 
-import "strings"
+import (
+	"strings"
+	"time"
+)
 
 // TurnParser handles parsing detailed turn-by-turn information from battle logs
 type TurnParser struct {
@@ -8,6 +11,16 @@ type TurnParser struct {
 	pendingEvents    []string
 	actionOrder      int
 	lastMovedPokemon map[string]string // tracks which Pokemon just moved for impact attribution
+	lastMove         []string          // the most recent |move| line
+	protects         *protectTracker
+	abilities        *abilityTracker
+	delayed          *delayedDamage
+	stages           *statStages
+	firsts           firstActions
+	pursuit          pursuitTracker
+	priority         priorityTracker
+	phazing          phazer
+	boundary         turnBoundary
 }
 
 // NewTurnParser creates a new turn parser
@@ -16,6 +29,12 @@ func NewTurnParser() *TurnParser {
 		pendingEvents:    []string{},
 		lastMovedPokemon: make(map[string]string),
 		actionOrder:      0,
+		protects:         newProtectTracker(),
+		abilities:        newAbilityTracker(),
+		delayed:          newDelayedDamage(),
+		stages:           newStatStages(),
+		firsts:           firstActions{},
+		priority:         priorityTracker{},
 	}
 }
 
@@ -24,8 +43,11 @@ func (tp *TurnParser) ProcessTurnEvent(line string, tracker *StateTracker) {
 	if line == "" || !strings.HasPrefix(line, "|") {
 		return
 	}
+	tp.processEvent(line, splitLogLine(line), tracker)
+}
 
-	parts := strings.Split(line, "|")
+// processEvent is ProcessTurnEvent for a line already split into parts.
+func (tp *TurnParser) processEvent(line string, parts []string, tracker *StateTracker) {
 	if len(parts) < 2 {
 		return
 	}
@@ -40,6 +62,20 @@ func (tp *TurnParser) ProcessTurnEvent(line string, tracker *StateTracker) {
 		// Parse the move
 		if len(parts) >= 4 {
 			action := tp.parseMove(parts)
+			action.Species = tracker.SpeciesFor(parts[2])
+			action.TransformedInto = tracker.TransformedInto(parts[2])
+			action.Move.Type = tracker.attackType(parts[2], action.Move.ID)
+			action.Source = moveSource(parts)
+			action.Still = isStill(parts)
+			action.PriorityReason = tp.priority.move(parts)
+			action.ParadoxBoost = tp.stages.paradoxStat(parts[2])
+			tp.firsts.record(tp.boundary, action)
+			tp.stages.move(parts)
+			tp.phazing.move(parts)
+			tp.pursuit.move(tp.currentTurn, parts)
+			if tp.currentTurn != nil {
+				action.ConsecutiveProtect = tp.protects.move(parts[2], parts[3], tp.currentTurn.TurnNumber)
+			}
 			action.OrderInTurn = tp.actionOrder
 			tp.actionOrder++
 
@@ -49,13 +85,23 @@ func (tp *TurnParser) ProcessTurnEvent(line string, tracker *StateTracker) {
 
 			// Track which Pokemon just moved
 			tp.lastMovedPokemon["last"] = action.Pokemon
+			tp.lastMove = parts
 		}
 
-	case "switch":
+	case "switch", "drag":
 		tp.flushPendingEvents()
 
 		if len(parts) >= 4 {
 			action := tp.parseSwitch(parts)
+			action.Boosts, action.BatonPass = tp.stages.switchIn(parts[2])
+			if command == "drag" {
+				tp.phazing.drag(&action)
+			} else {
+				action.PursuitPunish = tp.pursuit.switchOut(tp.boundary, tp.currentTurn, parts)
+				tp.firsts.record(tp.boundary, action)
+			}
+			tp.protects.reset(parts[2])
+			tp.abilities.process(tp.currentTurn, parts)
 			action.OrderInTurn = tp.actionOrder
 			tp.actionOrder++
 
@@ -65,10 +111,87 @@ func (tp *TurnParser) ProcessTurnEvent(line string, tracker *StateTracker) {
 		}
 
 	case "-damage", "-heal", "-status", "faint", "-crit", "-supereffective", "-resisted",
-		"-immune", "-miss", "-weather", "-fieldstart", "-boost", "-unboost":
+		"-immune", "-miss", "-weather", "-fieldstart", "-boost", "-unboost", "-hitcount":
+		switch command {
+		case "-boost", "-unboost":
+			tp.stages.process(parts)
+		case "-weather", "-fieldstart":
+			tp.abilities.process(tp.currentTurn, parts)
+		case "-damage", "-heal", "-status":
+			if isSilent(parts) {
+				// Real but unannounced; not an event of its own
+				tp.boundary.endOfTurn(parts)
+				return
+			}
+			if tp.boundary.endOfTurn(parts) {
+				// End-of-turn effects aren't the result of the last action
+				recordResidual(tp.currentTurn, parts)
+				if command == "-damage" {
+					if user, move, ok := tp.delayed.hit(parts); ok {
+						markDelayed(tp.currentTurn, tracker, user, move)
+					}
+				}
+				return
+			}
+		case "faint":
+			tp.pursuit.faint(tp.currentTurn, parts)
+			tp.abilities.process(tp.currentTurn, parts)
+			tp.stages.process(parts)
+			if tp.boundary.phase >= phaseResidual {
+				return
+			}
+		}
 		// Collect events that relate to the last action
 		tp.pendingEvents = append(tp.pendingEvents, line)
 
+	case "-singleturn", "-activate":
+		if event, ok := parseRedirect(parts); ok {
+			recordRedirect(tp.currentTurn, event)
+		}
+		if command == "-activate" {
+			recordFieldBlock(tp.currentTurn, tp.lastMove, parts)
+			tp.pursuit.activate(parts)
+			tp.priority.activate(parts)
+			tp.stages.paradoxActivate(parts)
+		}
+
+	case "-ability", "-end":
+		tp.abilities.process(tp.currentTurn, parts)
+		if command == "-end" {
+			tp.stages.paradoxEnd(parts)
+			if tp.delayed.end(parts) {
+				tp.boundary.residual()
+			}
+		}
+
+	case "-start":
+		tp.delayed.start(tp.lastMove, parts)
+		tp.stages.paradoxStart(parts)
+
+	case "-clearboost", "-clearallboost":
+		tp.stages.process(parts)
+
+	case "swap":
+		tp.stages.swap(parts)
+
+	case "upkeep":
+		tp.flushPendingEvents()
+		tp.boundary.upkeep()
+
+	case "-message":
+		recordClauseBlock(tp.currentTurn, tp.lastMove, parts)
+
+	case "-enditem":
+		tp.phazing.endItem(parts)
+
+	case "-fail":
+		recordFieldBlock(tp.currentTurn, tp.lastMove, parts)
+		// A failed Protect restores full odds for the next one
+		if len(parts) > 2 && len(tp.lastMove) >= 4 && isProtectMove(tp.lastMove[3]) &&
+			pokemonKey(parts[2]) == pokemonKey(tp.lastMove[2]) {
+			tp.protects.reset(parts[2])
+		}
+
 	default:
 		// Other events - might want to track these too
 	}
@@ -84,7 +207,18 @@ func (tp *TurnParser) StartNewTurn(turnNumber int) *Turn {
 		Actions:     []Action{},
 		DamageDealt: make(map[string]int),
 		HealingDone: make(map[string]int),
+		Redirects:   []RedirectEvent{},
+		Residual:    []ResidualEvent{},
+
+		Abilities:           []AbilityActivation{},
+		SuppressedAbilities: []string{},
 	}
+	tp.abilities.startTurn(tp.currentTurn)
+	tp.boundary.startTurn()
+	clear(tp.firsts)
+	tp.pursuit = pursuitTracker{}
+	clear(tp.priority)
+	tp.phazing = phazer{}
 	tp.actionOrder = 0
 	tp.lastMovedPokemon = make(map[string]string)
 
@@ -98,6 +232,8 @@ func (tp *TurnParser) FinalizeTurn(tracker *StateTracker) *Turn {
 
 	if tp.currentTurn != nil {
 		tp.currentTurn.PositionScore = tracker.CalculatePositionScore()
+		tp.currentTurn.DoubleSwitch = tp.firsts.doubleSwitch()
+		tp.currentTurn.Weather, tp.currentTurn.Terrain = tracker.EndTurnField()
 	}
 
 	turn := tp.currentTurn
@@ -118,6 +254,7 @@ func (tp *TurnParser) flushPendingEvents() {
 		// Enhance it with impact information
 		if lastAction.Move != nil {
 			EnhanceActionWithImpact(lastAction, lastAction.Move.Name, tp.pendingEvents)
+			markSpreadHits(lastAction.Impact, tp.lastMove)
 		}
 	}
 
@@ -129,7 +266,7 @@ func (tp *TurnParser) flushPendingEvents() {
 func (tp *TurnParser) parseMove(parts []string) Action {
 	// |move|p1a: Gengar|Shadow Ball|p2a: Dusclops
 	playerID := extractPlayerIDFromRef(parts[2])
-	pokemonName := extractPokemonName(parts[2])
+	pokemonName := extractNickname(parts[2])
 	moveName := strings.TrimSpace(parts[3])
 
 	action := Action{
@@ -155,37 +292,45 @@ func (tp *TurnParser) parseMove(parts []string) Action {
 func (tp *TurnParser) parseSwitch(parts []string) Action {
 	// |switch|p1b: Typhlosion|Typhlosion-Hisui, L50, M|100/100
 	playerID := extractPlayerIDFromRef(parts[2])
-	pokemonName := extractPokemonName(parts[2])
+	pokemonName := extractNickname(parts[2])
 	switchToPoke := extractPokemonName(parts[3])
 
 	return Action{
 		Player:     playerID,
 		ActionType: "switch",
 		Pokemon:    pokemonName,
+		Species:    switchToPoke,
 		SwitchTo:   switchToPoke,
 	}
 }
 
 // ParseEnhancedShowdownLog is an enhanced version of ParseShowdownLog with better turn tracking
 func ParseEnhancedShowdownLog(logContent string) (*BattleSummary, error) {
+	return ParseEnhancedShowdownLogWithOptions(logContent, ParseOptions{})
+}
+
+// ParseEnhancedShowdownLogWithOptions is ParseEnhancedShowdownLog with configurable limits.
+func ParseEnhancedShowdownLogWithOptions(logContent string, opts ParseOptions) (*BattleSummary, error) {
 	// First do the basic parsing
-	summary, err := ParseShowdownLog(logContent)
+	summary, err := ParseShowdownLogWithOptions(logContent, opts)
 	if err != nil {
 		return nil, err
 	}
+	maxTurns := opts.maxTurns()
 
 	// Now do enhanced turn parsing for more detailed action tracking
-	lines := strings.Split(logContent, "\n")
+	lines := splitLogLines(logContent)
+	fields := splitLogFields(lines)
 	tracker := NewStateTracker()
 	turnParser := NewTurnParser()
 
 	// First pass: set up tracker
-	for _, line := range lines {
+	for i, line := range lines {
 		if line == "" || !strings.HasPrefix(line, "|") {
 			continue
 		}
 
-		parts := strings.Split(line, "|")
+		parts := fields[i]
 		if len(parts) < 2 {
 			continue
 		}
@@ -205,6 +350,9 @@ func ParseEnhancedShowdownLog(logContent string) (*BattleSummary, error) {
 				teamSize := parseInt(parts[3])
 				tracker.SetTeamSize(playerID, teamSize)
 			}
+		case "clearpoke":
+			// A new preview follows; drop any earlier (superseded) list
+			tracker.ClearTeams()
 		case "poke":
 			if len(parts) > 3 {
 				playerID := parts[2]
@@ -218,13 +366,16 @@ func ParseEnhancedShowdownLog(logContent string) (*BattleSummary, error) {
 	// Second pass: detailed turn parsing
 	var enhancedTurns []Turn
 	var currentTurnNumber int
+	turnsSeen := 0
+	var lastTimestamp time.Time
 
-	for _, line := range lines {
+events:
+	for i, line := range lines {
 		if line == "" || !strings.HasPrefix(line, "|") {
 			continue
 		}
 
-		parts := strings.Split(line, "|")
+		parts := fields[i]
 		if len(parts) < 2 {
 			continue
 		}
@@ -232,7 +383,18 @@ func ParseEnhancedShowdownLog(logContent string) (*BattleSummary, error) {
 		command := parts[1]
 
 		switch command {
+		case "t:":
+			if len(parts) > 2 {
+				lastTimestamp = parseUnixTime(parts[2])
+			}
+
 		case "turn":
+			// The basic pass already recorded the warning
+			turnsSeen++
+			if turnsSeen > maxTurns {
+				break events
+			}
+
 			// Finalize previous turn
 			if currentTurnNumber > 0 {
 				turn := turnParser.FinalizeTurn(tracker)
@@ -243,30 +405,51 @@ func ParseEnhancedShowdownLog(logContent string) (*BattleSummary, error) {
 
 			// Start new turn
 			currentTurnNumber = parseInt(parts[2])
-			turnParser.StartNewTurn(currentTurnNumber)
+			turnParser.StartNewTurn(currentTurnNumber).Timestamp = lastTimestamp
 
-		case "switch":
-			turnParser.ProcessTurnEvent(line, tracker)
+		case "switch", "drag":
+			turnParser.processEvent(line, parts, tracker)
 			// Update tracker
 			if len(parts) >= 4 {
 				playerID := extractRawPlayerID(parts[2])
 				pokeName := extractPokemonName(parts[3])
-				pokehp := extractHPFromSwitch(parts)
-				tracker.SwitchPokemon(playerID, pokeName, pokehp)
+				hp, maxHP := extractHPFromSwitch(parts)
+				tracker.SwitchPokemon(playerID, pokeName, hp, maxHP)
+				tracker.RegisterSpecies(parts[2], pokeName)
 			}
 
-		case "move", "-damage", "-heal", "-status", "faint", "-crit",
-			"-supereffective", "-resisted", "-immune", "-miss", "-weather",
-			"-fieldstart", "-boost", "-unboost":
-			turnParser.ProcessTurnEvent(line, tracker)
+		case "-terastallize":
+			if len(parts) > 3 && mechanicAvailable(summary.Generation, "terastallize") {
+				tracker.SetTeraType(parts[2], parts[3])
+			}
 
-			// Update tracker for damage/healing
-			if command == "-damage" && len(parts) >= 4 {
-				playerID := extractRawPlayerID(parts[2])
-				hpStr := parts[3]
-				hp, maxHP := parseHP(hpStr)
-				tracker.UpdatePokemonHP(playerID, hp, maxHP)
+		case "-transform":
+			if len(parts) > 3 {
+				tracker.MarkTransformed(parts[2], tracker.SpeciesFor(parts[3]))
 			}
+
+		case "move", "-damage", "-heal", "-status", "faint", "-crit", "-hitcount",
+			"-supereffective", "-resisted", "-immune", "-miss", "-weather",
+			"-fieldstart", "-boost", "-unboost", "-singleturn", "-activate", "-fail",
+			"-ability", "-start", "-end", "-clearboost", "-clearallboost", "upkeep", "swap",
+			"-message", "-enditem":
+			turnParser.processEvent(line, parts, tracker)
+
+			// Update tracker for damage/healing and the field
+			switch command {
+			case "-damage":
+				if len(parts) >= 4 {
+					playerID := extractRawPlayerID(parts[2])
+					hpStr := parts[3]
+					hp, maxHP := parseHP(hpStr)
+					tracker.UpdatePokemonHP(playerID, hp, maxHP)
+				}
+			case "-weather", "-fieldstart":
+				tracker.UpdateField(parts)
+			}
+
+		case "-fieldend":
+			tracker.UpdateField(parts)
 		}
 	}
 
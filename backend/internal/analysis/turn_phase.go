@@ -0,0 +1,97 @@
+package analysis
+
+import "strings"
+
+// turnPhase is where the log is within a turn. Showdown runs the chosen
+// moves and switches, then end-of-turn residual effects (weather, poison,
+// Leftovers), then |upkeep|; switches after |upkeep| replace fainted Pokémon
+// before the next |turn|. Everything up to that |turn| belongs to the turn
+// that is ending.
+type turnPhase int
+
+const (
+	phaseLeads    turnPhase = iota // Before the first |turn|
+	phaseActions                   // Moves and switches chosen this turn
+	phaseResidual                  // End-of-turn effects, before |upkeep|
+	phaseUpkeep                    // After |upkeep|: replacements and their switch-in effects
+)
+
+// residualSources are [from] effects that only happen at the end of a turn,
+// lower-cased with any "item:", "ability:" or "move:" prefix removed.
+var residualSources = map[string]bool{
+	"psn": true, "tox": true, "brn": true,
+	"sandstorm": true, "hail": true,
+	"leech seed": true, "salt cure": true, "curse": true, "nightmare": true,
+	"partiallytrapped": true, "aqua ring": true, "ingrain": true,
+	"grassy terrain": true,
+	"leftovers":      true, "black sludge": true, "toxic orb": true, "flame orb": true,
+	"rain dish": true, "ice body": true, "dry skin": true, "solar power": true,
+	"poison heal": true, "bad dreams": true,
+}
+
+// turnBoundary tracks the phase of the current turn.
+type turnBoundary struct {
+	phase turnPhase
+}
+
+func (b *turnBoundary) startTurn() { b.phase = phaseActions }
+
+func (b *turnBoundary) upkeep() { b.phase = phaseUpkeep }
+
+// residual moves the turn into its residual phase on an end-of-turn effect
+// that has no [from] source, such as Future Sight landing.
+func (b *turnBoundary) residual() {
+	if b.phase == phaseActions {
+		b.phase = phaseResidual
+	}
+}
+
+// afterUpkeep reports whether the turn's |upkeep| has passed.
+func (b *turnBoundary) afterUpkeep() bool { return b.phase == phaseUpkeep }
+
+// endOfTurn reports whether a -damage, -heal or -status line is an
+// end-of-turn effect rather than the result of an action. The first
+// residual effect moves the turn into its residual phase; nothing the
+// players chose happens after that.
+func (b *turnBoundary) endOfTurn(parts []string) bool {
+	switch b.phase {
+	case phaseResidual, phaseUpkeep:
+		return true
+	case phaseActions:
+		if source, ok := passiveDamageSource(parts); ok && residualSources[residualKey(source)] {
+			b.phase = phaseResidual
+			return true
+		}
+	}
+	return false
+}
+
+// residualKey normalizes "item: Leftovers" to "leftovers".
+func residualKey(source string) string {
+	source = strings.ToLower(strings.TrimSpace(source))
+	for _, prefix := range []string{"item:", "ability:", "move:"} {
+		source = strings.TrimPrefix(source, prefix)
+	}
+	return strings.TrimSpace(source)
+}
+
+// recordResidual adds an end-of-turn effect to the turn it happened in.
+func recordResidual(turn *Turn, parts []string) {
+	if turn == nil || len(parts) < 4 {
+		return
+	}
+	event := ResidualEvent{
+		Player:  extractPlayerIDFromRef(parts[2]),
+		Pokemon: extractNickname(parts[2]),
+		Kind:    strings.TrimPrefix(parts[1], "-"),
+	}
+	if source, ok := passiveDamageSource(parts); ok {
+		event.Source = strings.TrimSpace(strings.TrimPrefix(strings.TrimPrefix(source, "item:"), "ability:"))
+	}
+	if event.Kind == "status" {
+		event.Status = strings.TrimSpace(parts[3])
+	} else {
+		event.HP = hpPercent(parts[3])
+	}
+	turn.Residual = append(turn.Residual, event)
+}
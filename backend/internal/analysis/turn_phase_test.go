@@ -0,0 +1,93 @@
+package analysis
+
+import "testing"
+
+const residualLog = `|player|p1|Alice|1|
+|player|p2|Bob|2|
+|start
+|switch|p1a: Gengar|Gengar, L50, M|100/100
+|switch|p2a: Blastoise|Blastoise, L50, M|100/100
+|turn|1
+|move|p1a: Gengar|Sludge Bomb|p2a: Blastoise
+|-damage|p2a: Blastoise|60/100
+|-status|p2a: Blastoise|psn
+|move|p2a: Blastoise|Scald|p1a: Gengar
+|-damage|p1a: Gengar|50/100
+|-damage|p2a: Blastoise|48/100 psn|[from] psn
+|-heal|p1a: Gengar|56/100|[from] item: Leftovers
+|upkeep
+|turn|2
+|move|p1a: Gengar|Shadow Ball|p2a: Blastoise
+|-damage|p2a: Blastoise|0 fnt
+|faint|p2a: Blastoise
+|upkeep
+|
+|switch|p2a: Snorlax|Snorlax, L50, M|100/100
+|-damage|p2a: Snorlax|88/100|[from] Stealth Rock
+|turn|3
+|move|p1a: Gengar|Protect|p1a: Gengar
+|upkeep
+|win|Alice
+`
+
+func TestParseShowdownLogResidualEffects(t *testing.T) {
+	for name, parse := range map[string]func(string) (*BattleSummary, error){
+		"basic":    ParseShowdownLog,
+		"enhanced": ParseEnhancedShowdownLog,
+	} {
+		t.Run(name, func(t *testing.T) {
+			summary, err := parse(residualLog)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(summary.Turns) != 3 {
+				t.Fatalf("expected 3 turns, got %d", len(summary.Turns))
+			}
+
+			// End-of-turn poison and Leftovers land on turn 1
+			residual := summary.Turns[0].Residual
+			if len(residual) != 2 {
+				t.Fatalf("expected 2 residual effects on turn 1, got %+v", residual)
+			}
+			poison := residual[0]
+			if poison.Pokemon != "Blastoise" || poison.Kind != "damage" || poison.Source != "psn" || poison.HP != 48 {
+				t.Errorf("expected Blastoise's poison damage to 48%%, got %+v", poison)
+			}
+			if residual[1].Kind != "heal" || residual[1].Source != "Leftovers" || residual[1].Player != "player1" {
+				t.Errorf("expected Gengar's Leftovers heal, got %+v", residual[1])
+			}
+
+			// Hazard damage on a replacement after |upkeep| stays on turn 2
+			residual = summary.Turns[1].Residual
+			if len(residual) != 1 || residual[0].Pokemon != "Snorlax" || residual[0].Source != "Stealth Rock" {
+				t.Errorf("expected Snorlax's Stealth Rock damage on turn 2, got %+v", residual)
+			}
+			if len(summary.Turns[2].Residual) != 0 {
+				t.Errorf("expected no residual effects on turn 3, got %+v", summary.Turns[2].Residual)
+			}
+		})
+	}
+}
+
+func TestEnhancedParserKeepsResidualOutOfImpact(t *testing.T) {
+	summary, err := ParseEnhancedShowdownLog(residualLog)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Scald was the last action before Gengar's Leftovers heal
+	actions := summary.Turns[0].Actions
+	scald := actions[len(actions)-1]
+	if scald.Move == nil || scald.Move.Name != "Scald" {
+		t.Fatalf("expected Scald to be the last action, got %+v", scald)
+	}
+	if scald.Impact == nil || scald.Impact.HealingDone != 0 {
+		t.Errorf("expected no healing credited to Scald, got %+v", scald.Impact)
+	}
+
+	// Effects of the move itself still count
+	sludgeBomb := actions[len(actions)-2]
+	if sludgeBomb.Impact == nil || sludgeBomb.Impact.DamageDealt != 40 {
+		t.Errorf("expected Sludge Bomb to deal 40, got %+v", sludgeBomb.Impact)
+	}
+}
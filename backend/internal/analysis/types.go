@@ -5,15 +5,33 @@ import "time"
 // BattleSummary represents the complete analysis of a Pokémon battle.
 type BattleSummary struct {
 	// Metadata about the battle
-	ID        string    `json:"id"`
-	Format    string    `json:"format"` // e.g., "Regulation H"
-	Timestamp time.Time `json:"timestamp"`
-	Duration  int       `json:"duration"` // in seconds
+	ID         string    `json:"id"`
+	Format     string    `json:"format"`     // e.g., "Regulation H"
+	Generation int       `json:"generation"` // From |gen|, 0 if absent
+	Timestamp  time.Time `json:"timestamp"`
+	Duration   int       `json:"duration"` // in seconds
+	Rules      []string  `json:"rules"`    // Human-readable |rule| lines, e.g. "Species Clause: Limit one of each Pokémon"
+	Warnings   []string  `json:"warnings"` // Problems that made the summary partial, e.g. exceeding ParseOptions.MaxTurns
 
-	// Player information
-	Player1 Player `json:"player1"`
-	Player2 Player `json:"player2"`
-	Winner  string `json:"winner"` // "player1", "player2", or "draw"
+	// Team preview: Pokémon shown per player (the larger side) and how many
+	// each may bring from |teampreview|N; both 0 without a preview
+	PreviewSize int `json:"previewSize"`
+	BringCount  int `json:"bringCount"`
+
+	// Player information. Player3 and Player4 are only seated in free-for-all
+	// and multi battles. Players holds every seat keyed "p1".."p4", pointing
+	// at the fields above; it isn't serialized, so no seat is written twice.
+	Player1 Player             `json:"player1"`
+	Player2 Player             `json:"player2"`
+	Player3 *Player            `json:"player3,omitempty"`
+	Player4 *Player            `json:"player4,omitempty"`
+	Players map[string]*Player `json:"-"`
+	Winner  string             `json:"winner"` // "player1".."player4"; empty for ties and unfinished battles
+	Draw    bool               `json:"draw"`   // Battle ended in a |tie|
+
+	// Pokémon left per side at the end, winner first, e.g. "2-0"; seat order
+	// for draws and empty for unfinished battles
+	ScoreLine string `json:"scoreLine"`
 
 	// Battle progression
 	Turns []Turn `json:"turns"`
@@ -23,24 +41,55 @@ type BattleSummary struct {
 
 	// Key moments and highlights
 	KeyMoments []KeyMoment `json:"keyMoments"`
+
+	// Screens and speed control, in the order they were set
+	SideConditions []SideCondition `json:"sideConditions"`
+
+	// Weather and terrain changes, in the order they were set
+	WeatherSetters []WeatherSetter `json:"weatherSetters"`
+
+	// Items forcibly removed by an opposing move, in battle order
+	ItemsRemoved []ItemRemoved `json:"itemsRemoved"`
+
+	// Battle timer warnings, in battle order
+	ClockEvents []ClockEvent `json:"clockEvents"`
+
+	// Every faint and who or what caused it, in battle order
+	KOEvents []KOEvent `json:"koEvents"`
+
+	// Turns a Pokémon couldn't act (|cant|), in battle order
+	LostTurns []LostTurn `json:"lostTurns"`
+
+	// Protosynthesis and Quark Drive activations, in battle order
+	ParadoxBoosts []ParadoxBoost `json:"paradoxBoosts"`
 }
 
 // Player represents a single player in the battle.
 type Player struct {
-	Name           string             `json:"name"`
-	Team           []Pokémon          `json:"team"`
-	Active         *Pokémon           `json:"active"`         // Currently active Pokémon
-	Losses         int                `json:"losses"`         // Number of fainted Pokémon
-	TotalLeft      int                `json:"totalLeft"`      // Total Pokémon still in battle
-	ActiveIndex    int                `json:"activeIndex"`    // Index in team of active Pokémon
-	TeamArchetype  string             `json:"teamArchetype"`  // e.g., "Hard Trick Room", "Tailwind Hyper Offense"
-	Classification TeamClassification `json:"classification"` // Detailed team classification
+	Name              string              `json:"name"`
+	Team              []Pokémon           `json:"team"`              // In team preview order
+	Active            *Pokémon            `json:"active"`            // Currently active Pokémon
+	Losses            int                 `json:"losses"`            // Number of fainted Pokémon
+	TotalLeft         int                 `json:"totalLeft"`         // Total Pokémon still in battle
+	ActiveIndex       int                 `json:"activeIndex"`       // Index in team of active Pokémon
+	TeamArchetype     string              `json:"teamArchetype"`     // e.g., "Hard Trick Room", "Tailwind Hyper Offense"
+	Classification    TeamClassification  `json:"classification"`    // Detailed team classification
+	RevealedMoves     map[string][]string `json:"revealedMoves"`     // Species -> distinct moves used, in first-seen order
+	RevealedAbilities map[string]string   `json:"revealedAbilities"` // Species -> ability shown by |-ability| or by weather/terrain it set
+	Leads             []string            `json:"leads"`             // Species on the field when the battle started
+	PreviewOrder      []string            `json:"previewOrder"`      // Species in the order |poke| showed them
+	BringOrder        []string            `json:"bringOrder"`        // Species in the order they first switched in, leads first
+	Benched           []string            `json:"benched"`           // Previewed species that never switched in, in preview order; brought but unused looks the same
+	Rating            int                 `json:"rating"`            // Ladder rating from |player|, 0 if unrated
+	KOs               map[string]int      `json:"kos"`               // Species -> opposing Pokémon it knocked out
+	IndirectDamage    map[string]float64  `json:"indirectDamage"`    // Species -> HP% dealt via abilities/items named by [of] (e.g. Rough Skin) and delayed moves like Future Sight
 }
 
 // Pokémon represents a single Pokémon with its stats and moves.
 type Pokémon struct {
 	ID        string `json:"id"` // e.g., "pikachu"
 	Name      string `json:"name"`
+	Nickname  string `json:"nickname,omitempty"` // Name shown in battle once it switches in, if it differs from the species
 	Level     int    `json:"level"`
 	Gender    string `json:"gender"` // "M", "F", or ""
 	Ability   string `json:"ability"`
@@ -77,12 +126,52 @@ type Stats struct {
 
 // Turn represents a single turn in the battle.
 type Turn struct {
-	TurnNumber    int            `json:"turnNumber"`
-	Actions       []Action       `json:"actions"`
-	StateAfter    BattleState    `json:"stateAfter"`
-	DamageDealt   map[string]int `json:"damageDealt"`   // Player name -> damage dealt
-	HealingDone   map[string]int `json:"healingDone"`   // Player name -> healing done
-	PositionScore *PositionScore `json:"positionScore"` // Evaluation of positions after this turn
+	TurnNumber    int             `json:"turnNumber"`
+	Actions       []Action        `json:"actions"`
+	StateAfter    BattleState     `json:"stateAfter"`
+	DamageDealt   map[string]int  `json:"damageDealt"`   // Player name -> damage dealt
+	HealingDone   map[string]int  `json:"healingDone"`   // Player name -> healing done
+	PositionScore *PositionScore  `json:"positionScore"` // Evaluation of positions after this turn
+	Redirects     []RedirectEvent `json:"redirects"`     // Follow Me / Rage Powder / Ally Switch used this turn
+	Timestamp     time.Time       `json:"timestamp"`     // Latest |t:| before the turn started, zero if the log has none
+
+	Residual []ResidualEvent `json:"residual"` // End-of-turn damage, healing and status, including after |upkeep|
+
+	DoubleSwitch bool `json:"doubleSwitch,omitempty"` // Every player's first action this turn was a switch
+
+	Weather string `json:"weather,omitempty"` // Weather after this turn with its countdown, e.g. "Sun (3 turns left)"
+	Terrain string `json:"terrain,omitempty"` // Terrain after this turn with its countdown, e.g. "Electric Terrain (4 turns left)"
+
+	Abilities           []AbilityActivation `json:"abilities"`           // Abilities announced with |-ability| this turn
+	SuppressedAbilities []string            `json:"suppressedAbilities"` // Neutralizing Gas / Mold Breaker in effect, e.g. "Neutralizing Gas (Weezing)"
+}
+
+// ResidualEvent is an end-of-turn effect: weather or poison damage, Leftovers
+// healing, a Toxic Orb activating, or hazard damage on a replacement.
+type ResidualEvent struct {
+	Player  string `json:"player"`
+	Pokemon string `json:"pokemon"`
+	Kind    string `json:"kind"`              // "damage", "heal", or "status"
+	Source  string `json:"source,omitempty"`  // [from] effect, e.g. "psn", "Leftovers", "Stealth Rock"
+	HP      int    `json:"hp,omitempty"`      // HP% afterwards, for damage and heal
+	Status  string `json:"status,omitempty"`  // Status inflicted, for status
+	Delayed bool   `json:"delayed,omitempty"` // Future Sight or Doom Desire used turns earlier
+	User    string `json:"user,omitempty"`    // Species that used the delayed move
+}
+
+// AbilityActivation is an ability announcing itself, e.g. Intimidate on switch-in.
+type AbilityActivation struct {
+	Player  string `json:"player"`
+	Pokemon string `json:"pokemon"`
+	Ability string `json:"ability"`
+}
+
+// RedirectEvent records a Pokémon drawing or shifting attacks in doubles.
+type RedirectEvent struct {
+	Player  string `json:"player"`            // "player1" or "player2"
+	Pokemon string `json:"pokemon"`           // Pokémon that redirected
+	Move    string `json:"move"`              // "Follow Me", "Rage Powder", "Spotlight", or "Ally Switch"
+	Partner string `json:"partner,omitempty"` // Ally swapped with, for Ally Switch
 }
 
 // PositionScore represents the evaluated position for both players after a turn.
@@ -96,7 +185,8 @@ type PositionScore struct {
 type Action struct {
 	Player      string      `json:"player"`     // "player1" or "player2"
 	ActionType  string      `json:"actionType"` // "move", "switch", "item"
-	Pokemon     string      `json:"pokemon"`    // Pokémon performing the action
+	Pokemon     string      `json:"pokemon"`    // Nickname of the Pokémon performing the action, as shown in its slot
+	Species     string      `json:"species"`    // True species behind Pokemon, e.g. "Pikachu" for "Sparky"
 	Move        *Move       `json:"move,omitempty"`
 	SwitchTo    string      `json:"switchTo,omitempty"` // Pokémon name if switch
 	Item        string      `json:"item,omitempty"`     // Item used if item action
@@ -105,6 +195,23 @@ type Action struct {
 	Details     string      `json:"details,omitempty"`  // Additional details
 	Impact      *MoveImpact `json:"impact,omitempty"`   // Detailed impact of the action
 	OrderInTurn int         `json:"orderInTurn"`        // Order within the turn (0-based)
+	Redirect    bool        `json:"redirect,omitempty"` // Move redirected attacks (Follow Me, Ally Switch, ...)
+	Immune      bool        `json:"immune,omitempty"`   // Move hit a type or ability immunity
+
+	ConsecutiveProtect bool   `json:"consecutiveProtect,omitempty"` // Protect-family move right after the user's last one
+	BlockedBy          string `json:"blockedBy,omitempty"`          // Terrain, Safeguard, ability, or clause that stopped the move, e.g. "Electric Terrain", "Sleep Clause Mod"
+	Source             string `json:"source,omitempty"`             // Move or ability that called this move, e.g. "Sleep Talk", "Dancer", "Magic Bounce"
+	Hits               int    `json:"hits,omitempty"`               // Times a multi-hit move struck; 0 for single-hit moves
+	Still              bool   `json:"still,omitempty"`              // Move shown without executing ([still]), e.g. a Solar Beam charge turn
+	PursuitPunish      bool   `json:"pursuitPunish,omitempty"`      // Pursuit that caught a switching target, and that target's switch
+	TransformedInto    string `json:"transformedInto,omitempty"`    // Species the user had transformed into (Transform/Imposter); Species stays its own
+	PriorityReason     string `json:"priorityReason,omitempty"`     // Why the user moved first in its priority bracket: "Quick Claw", "Custap Berry", or "Quick Draw"
+	ParadoxBoost       string `json:"paradoxBoost,omitempty"`       // Stat the user's Protosynthesis or Quark Drive was boosting, e.g. "spe"
+
+	BatonPass bool           `json:"batonPass,omitempty"` // Switch made by Baton Pass
+	Forced    bool           `json:"forced,omitempty"`    // Switch forced by a |drag| rather than chosen
+	ForcedBy  string         `json:"forcedBy,omitempty"`  // What forced it: "Roar", "Whirlwind", "Dragon Tail", "Circle Throw", or "Red Card"
+	Boosts    map[string]int `json:"boosts,omitempty"`    // Stat stages the switch-in received by Baton Pass, e.g. "atk": 2
 }
 
 // BattleState represents the state of the battle at a point in time.
@@ -117,18 +224,31 @@ type BattleState struct {
 
 // BattleStats represents aggregate statistics about the battle.
 type BattleStats struct {
-	TotalTurns       int            `json:"totalTurns"`
-	MoveFrequency    map[string]int `json:"moveFrequency"` // Move ID -> count
-	TypeCoverage     map[string]int `json:"typeCoverage"`  // Type -> count
-	Switch           int            `json:"switches"`      // Total switches by both players
-	CriticalHits     int            `json:"criticalHits"`
-	SuperEffective   int            `json:"superEffective"`
-	NotVeryEffective int            `json:"notVeryEffective"`
-	AvgDamagePerTurn float64        `json:"avgDamagePerTurn"`
-	AvgHealPerTurn   float64        `json:"avgHealPerTurn"`
-	Player1Stats     PlayerStats    `json:"player1Stats"`
-	Player2Stats     PlayerStats    `json:"player2Stats"`
-	TurningPoints    []TurningPoint `json:"turningPoints"` // Key moments where momentum shifted
+	TotalTurns            int                          `json:"totalTurns"`
+	MoveFrequency         map[string]int               `json:"moveFrequency"`         // Move ID -> count
+	TypeCoverage          map[string]int               `json:"typeCoverage"`          // Type -> count
+	Switch                int                          `json:"switches"`              // Total switches by both players, excluding forced ones (|drag|)
+	SwitchCount           map[string]int               `json:"switchCount"`           // Player -> voluntary switches (excludes leads and replacements)
+	ReplacementCount      map[string]int               `json:"replacementCount"`      // Player -> switches replacing a fainted Pokémon
+	PassiveDamage         map[string]float64           `json:"passiveDamage"`         // Player -> HP% lost to weather, status, hazards, items and other non-move sources
+	PassiveDamageBySource map[string]float64           `json:"passiveDamageBySource"` // "weather", "status", "hazard", "item", "ability", "recoil", "other" -> HP% lost
+	DamageTakenByPokemon  map[string]float64           `json:"damageTakenByPokemon"`  // "p2: Blastoise" (side and species) -> HP% lost to moves and passive damage
+	StatusTurns           map[string]int               `json:"statusTurns"`           // "p2: Snorlax" -> turns spent asleep or frozen, excluding Rest
+	RestTurns             map[string]int               `json:"restTurns"`             // "p2: Snorlax" -> turns spent asleep from its own Rest
+	MinTimeLeft           map[string]int               `json:"minTimeLeft"`           // Player -> fewest seconds left in any timer warning; absent if never warned
+	CriticalHits          int                          `json:"criticalHits"`
+	SuperEffective        int                          `json:"superEffective"`
+	NotVeryEffective      int                          `json:"notVeryEffective"`
+	ImmuneCount           map[string]int               `json:"immuneCount"`        // Player -> moves that hit an opposing immunity
+	AbilityImmuneCount    map[string]int               `json:"abilityImmuneCount"` // Player -> subset of ImmuneCount caused by an ability (Levitate, Flash Fire, ...)
+	ItemsKnockedOff       map[string]int               `json:"itemsKnockedOff"`    // Player -> opposing items removed by Knock Off, Thief, etc.
+	LostTurns             map[string]int               `json:"lostTurns"`          // Player -> turns a Pokémon couldn't act (flinch, full paralysis, sleep, ...)
+	TypeEffectiveness     map[string]TypeEffectiveness `json:"typeEffectiveness"`  // Attacking type -> how its hits on opposing Pokémon landed
+	AvgDamagePerTurn      float64                      `json:"avgDamagePerTurn"`
+	AvgHealPerTurn        float64                      `json:"avgHealPerTurn"`
+	Player1Stats          PlayerStats                  `json:"player1Stats"`
+	Player2Stats          PlayerStats                  `json:"player2Stats"`
+	TurningPoints         []TurningPoint               `json:"turningPoints"` // Key moments where momentum shifted
 }
 
 // TurningPoint represents a turn where the battle's momentum shifted significantly.
@@ -162,12 +282,103 @@ type EffectivenessStats struct {
 	Neutral          int `json:"neutral"`
 }
 
+// TypeEffectiveness counts how an attacking type's moves landed, once per
+// move and target.
+type TypeEffectiveness struct {
+	SuperEffective int `json:"superEffective"`
+	Neutral        int `json:"neutral"`
+	Resisted       int `json:"resisted"`
+	Immune         int `json:"immune"`
+}
+
 // KeyMoment represents a significant moment in the battle.
 type KeyMoment struct {
 	TurnNumber   int    `json:"turnNumber"`
-	Description  string `json:"description"`  // e.g., "Player 2 switched to Charizard"
-	Type         string `json:"type"`         // "switch", "kO", "status", "weather", etc.
-	Significance int    `json:"significance"` // 1-10 scale
+	Description  string `json:"description"`      // e.g., "Player 2 switched to Charizard"
+	Type         string `json:"type"`             // "switch", "kO", "status", "weather", etc.
+	Significance int    `json:"significance"`     // 1-10 scale
+	Source       string `json:"source,omitempty"` // Item/ability/move responsible, e.g. "item: Focus Sash"
+}
+
+// SideCondition records the lifetime of a screen or speed-control effect.
+type SideCondition struct {
+	Condition string `json:"condition"`       // "Tailwind", "Reflect", "Light Screen", "Aurora Veil", "Trick Room", "Gravity", "Wonder Room", "Magic Room"
+	Side      string `json:"side"`            // "player1", "player2", or "field" for Trick Room and the other rooms
+	StartTurn int    `json:"startTurn"`       // Turn it was set on
+	EndTurn   int    `json:"endTurn"`         // Turn it wore off, 0 if still active when the battle ended
+	SetBy     string `json:"setBy,omitempty"` // Pokémon whose move set it
+	Move      string `json:"move,omitempty"`  // Move that set it
+}
+
+// WeatherSetter records who set a weather or terrain and how. Ability-set
+// weather (Drought) comes from a Pokémon switching in; move-set weather
+// (Sunny Day) was a choice made that turn.
+type WeatherSetter struct {
+	Effect  string `json:"effect"`            // "SunnyDay", "RainDance", "Electric Terrain", ...
+	Kind    string `json:"kind"`              // "weather" or "terrain"
+	Turn    int    `json:"turn"`              // Turn it was set on, 0 before the first turn
+	Source  string `json:"source"`            // "ability", "move", or "" when unknown
+	Ability string `json:"ability,omitempty"` // Ability that set it, for ability sources
+	Move    string `json:"move,omitempty"`    // Move that set it, for move sources
+	SetBy   string `json:"setBy,omitempty"`   // Pokémon that set it
+	Player  string `json:"player,omitempty"`  // "player1" or "player2"
+}
+
+// ItemRemoved records an item taken away by a move such as Knock Off or
+// Thief, as opposed to one its holder consumed.
+type ItemRemoved struct {
+	Turn     int    `json:"turn"`
+	Player   string `json:"player"`  // Side that lost the item
+	Pokemon  string `json:"pokemon"` // Holder
+	Item     string `json:"item"`
+	Move     string `json:"move"`     // "Knock Off", "Thief", ...
+	By       string `json:"by"`       // Pokémon that removed it
+	ByPlayer string `json:"byPlayer"` // Side credited in Stats.ItemsKnockedOff
+}
+
+// ClockEvent is a battle timer warning from an |inactive| line, or the timer
+// being turned off by |inactiveoff|.
+type ClockEvent struct {
+	Turn        int    `json:"turn"`
+	Player      string `json:"player,omitempty"` // Player warned; empty when TimerOff
+	SecondsLeft int    `json:"secondsLeft"`
+	TimerOff    bool   `json:"timerOff,omitempty"`
+}
+
+// KOEvent is a Pokémon fainting. By is the species credited with the KO:
+// the attacker, the Destiny Bond user, the Pokémon whose ability or item
+// (Rough Skin, Rocky Helmet) dealt the damage, or the fainted Pokémon itself
+// for a self-destruct.
+type KOEvent struct {
+	Turn     int    `json:"turn"`
+	Player   string `json:"player"`  // Side that lost the Pokémon
+	Pokemon  string `json:"pokemon"` // Nickname of the fainted Pokémon
+	Species  string `json:"species"`
+	By       string `json:"by,omitempty"`
+	ByPlayer string `json:"byPlayer,omitempty"`
+	Cause    string `json:"cause,omitempty"` // "move", "destinybond", "selfdestruct", "indirect", or "" when uncredited (residual damage, recoil, ...)
+}
+
+// LostTurn is a turn a Pokémon couldn't act, from a |cant| line.
+type LostTurn struct {
+	Turn    int    `json:"turn"`
+	Player  string `json:"player"`
+	Pokemon string `json:"pokemon"` // Nickname, as shown in its slot
+	Species string `json:"species"`
+	Reason  string `json:"reason"`         // "flinch", "par" (full paralysis), "slp", "frz", or the blocking effect, e.g. "taunt", "recharge"
+	Move    string `json:"move,omitempty"` // Move it was prevented from using, when the log says
+}
+
+// ParadoxBoost is a Protosynthesis or Quark Drive activation, from the
+// |-start| line naming the boosted stat.
+type ParadoxBoost struct {
+	Turn    int    `json:"turn"`
+	Player  string `json:"player"`
+	Pokemon string `json:"pokemon"` // Nickname, as shown in its slot
+	Species string `json:"species"`
+	Ability string `json:"ability"` // "Protosynthesis" or "Quark Drive"
+	Stat    string `json:"stat"`    // "atk", "def", "spa", "spd", or "spe"
+	Source  string `json:"source"`  // "Booster Energy", or the field that activated it: "Sun" or "Electric Terrain"
 }
 
 // TeamClassification contains detailed information about a team's archetype
@@ -190,19 +401,28 @@ type TeamClassification struct {
 
 // MoveImpact represents the detailed impact of a move or action
 type MoveImpact struct {
-	DamageDealt     int          `json:"damageDealt"`     // Damage dealt to opponent
-	HealingDone     int          `json:"healingDone"`     // Healing done
-	StatusInflicted string       `json:"statusInflicted"` // Status condition inflicted
-	SpeedControl    string       `json:"speedControl"`    // "trick-room", "tailwind", "paralysis", etc.
-	WeatherSet      string       `json:"weatherSet"`      // Weather set by this move
-	TerrainSet      string       `json:"terrainSet"`      // Terrain set by this move
-	FakeOut         bool         `json:"fakeOut"`         // Was this a Fake Out?
-	Protect         bool         `json:"protect"`         // Was this a Protect/Detect?
-	StatChanges     []StatChange `json:"statChanges"`     // Stat changes caused
-	Fainted         []string     `json:"fainted"`         // List of Pokémon that fainted
-	Critical        bool         `json:"critical"`        // Was this a critical hit?
-	Effectiveness   string       `json:"effectiveness"`   // "super-effective", "not-very-effective", "immune"
-	Missed          bool         `json:"missed"`          // Did the move miss?
+	DamageDealt     int          `json:"damageDealt"`             // Damage dealt to opponent
+	HealingDone     int          `json:"healingDone"`             // Healing done
+	StatusInflicted string       `json:"statusInflicted"`         // Status condition inflicted
+	SpeedControl    string       `json:"speedControl"`            // "trick-room", "tailwind", "paralysis", etc.
+	WeatherSet      string       `json:"weatherSet"`              // Weather set by this move
+	TerrainSet      string       `json:"terrainSet"`              // Terrain set by this move
+	FakeOut         bool         `json:"fakeOut"`                 // Was this a Fake Out?
+	Protect         bool         `json:"protect"`                 // Was this a Protect/Detect?
+	StatChanges     []StatChange `json:"statChanges"`             // Stat changes caused
+	Fainted         []string     `json:"fainted"`                 // List of Pokémon that fainted
+	Critical        bool         `json:"critical"`                // Was this a critical hit?
+	Effectiveness   string       `json:"effectiveness"`           // "super-effective", "not-very-effective", "immune"
+	ImmuneAbility   string       `json:"immuneAbility,omitempty"` // Ability that granted immunity, "" for type immunity
+	Missed          bool         `json:"missed"`                  // Did the move miss?
+	Targets         []TargetHit  `json:"targets"`                 // Direct damage per Pokémon hit
+}
+
+// TargetHit is the direct damage one move dealt to one Pokémon.
+type TargetHit struct {
+	Pokemon   string `json:"pokemon"`
+	Damage    int    `json:"damage"`              // HP% lost
+	SpreadHit bool   `json:"spreadHit,omitempty"` // Move hit 2+ targets, so each took 0.75x damage
 }
 
 // StatChange represents a stat modification
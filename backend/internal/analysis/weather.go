@@ -0,0 +1,173 @@
+package analysis
+
+import (
+	"strconv"
+	"strings"
+)
+
+// weatherMoves maps weather IDs from |-weather| lines to the moves that set them.
+var weatherMoves = map[string]string{
+	"sunnyday":  "Sunny Day",
+	"raindance": "Rain Dance",
+	"sandstorm": "Sandstorm",
+	"snow":      "Snowscape",
+	"hail":      "Hail",
+}
+
+// Weather and terrain last fieldDuration turns, counting the turn they're
+// set, or extendedFieldDuration with Heat Rock, Terrain Extender and the like.
+// The log doesn't reveal the item, so an effect still up when its countdown
+// runs out is taken to be extended.
+const (
+	fieldDuration         = 5
+	extendedFieldDuration = 8
+)
+
+// weatherNames maps weather IDs to the names shown with their countdown.
+var weatherNames = map[string]string{
+	"sunnyday":      "Sun",
+	"raindance":     "Rain",
+	"sandstorm":     "Sandstorm",
+	"snow":          "Snow",
+	"hail":          "Hail",
+	"desolateland":  "Extremely Harsh Sunlight",
+	"primordialsea": "Heavy Rain",
+	"deltastream":   "Strong Winds",
+}
+
+// primalWeather lasts while its setter stays in rather than counting down.
+var primalWeather = map[string]bool{
+	"desolateland":  true,
+	"primordialsea": true,
+	"deltastream":   true,
+}
+
+// fieldTimer counts down an active weather or terrain.
+type fieldTimer struct {
+	name string // "Sun", "Electric Terrain"; "" when none is active
+	left int    // Turns left including the current one; 0 for no countdown
+}
+
+// label describes the effect with its countdown, e.g. "Sun (3 turns left)".
+func (ft fieldTimer) label() string {
+	switch {
+	case ft.name == "" || ft.left == 0:
+		return ft.name
+	case ft.left == 1:
+		return ft.name + " (1 turn left)"
+	default:
+		return ft.name + " (" + strconv.Itoa(ft.left) + " turns left)"
+	}
+}
+
+// tick ends a turn: the countdown drops by one, and an effect that is still
+// active when it reaches zero had an extending item.
+func (ft *fieldTimer) tick() {
+	if ft.name == "" || ft.left == 0 {
+		return
+	}
+	ft.left--
+	if ft.left == 0 {
+		ft.left = extendedFieldDuration - fieldDuration
+	}
+}
+
+// UpdateField tracks the active weather and terrain from |-weather|,
+// |-fieldstart| and |-fieldend| lines. Setting an effect, even one already
+// active, restarts its countdown; the [upkeep] line repeating the weather at
+// the end of each turn only confirms it's still up.
+func (st *StateTracker) UpdateField(parts []string) {
+	if len(parts) < 3 {
+		return
+	}
+	name := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(parts[2]), "move:"))
+	effect := strings.ToLower(name)
+	switch parts[1] {
+	case "-weather":
+		switch {
+		case effect == "none":
+			effect = ""
+			st.weatherTimer = fieldTimer{}
+		case hasTag(parts, "[upkeep]") && effect == st.weather:
+			// Still up; the countdown carries on
+		case primalWeather[effect]:
+			st.weatherTimer = fieldTimer{name: weatherName(effect, name)}
+		default:
+			st.weatherTimer = fieldTimer{name: weatherName(effect, name), left: fieldDuration}
+		}
+		st.weather = effect
+	case "-fieldstart":
+		if strings.HasSuffix(effect, "terrain") {
+			st.terrain = effect
+			st.terrainTimer = fieldTimer{name: name, left: fieldDuration}
+		}
+	case "-fieldend":
+		if effect == st.terrain {
+			st.terrain = ""
+			st.terrainTimer = fieldTimer{}
+		}
+	}
+}
+
+// EndTurnField counts down the weather and terrain at the end of a turn and
+// returns what's left of each, e.g. "Sun (3 turns left)", or "" for none.
+func (st *StateTracker) EndTurnField() (weather, terrain string) {
+	st.weatherTimer.tick()
+	st.terrainTimer.tick()
+	return st.weatherTimer.label(), st.terrainTimer.label()
+}
+
+// weatherName returns the display name for a weather ID, falling back to the
+// name as logged.
+func weatherName(id, logged string) string {
+	if name, ok := weatherNames[id]; ok {
+		return name
+	}
+	return logged
+}
+
+// recordWeatherSetter records a new weather (|-weather|SunnyDay|...) or terrain
+// (|-fieldstart|move: Electric Terrain|...) and attributes it to the ability
+// named by [from] and the Pokémon named by [of], or else to the move on the
+// most recent |move| line. Weather ending ("none") and per-turn [upkeep]
+// continuations aren't changes and are skipped.
+func recordWeatherSetter(summary *BattleSummary, kind string, turn int, lastMove []string, parts []string) {
+	if len(parts) < 3 {
+		return
+	}
+	effect := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(parts[2]), "move:"))
+	if effect == "" || strings.EqualFold(effect, "none") {
+		return
+	}
+	for _, part := range parts[3:] {
+		if strings.TrimSpace(part) == "[upkeep]" {
+			return
+		}
+	}
+
+	setter := WeatherSetter{Effect: effect, Kind: kind, Turn: turn}
+	if ability := fromAbility(parts); ability != "" {
+		setter.Source = "ability"
+		setter.Ability = ability
+		if of := ofSource(parts); of != "" {
+			setter.SetBy = extractNickname(of)
+			setter.Player = extractPlayerIDFromRef(of)
+		}
+	} else if move := weatherSetterMove(effect, kind); len(lastMove) >= 4 && strings.EqualFold(strings.TrimSpace(lastMove[3]), move) {
+		setter.Source = "move"
+		setter.Move = strings.TrimSpace(lastMove[3])
+		setter.SetBy = extractNickname(lastMove[2])
+		setter.Player = extractPlayerIDFromRef(lastMove[2])
+	}
+
+	summary.WeatherSetters = append(summary.WeatherSetters, setter)
+}
+
+// weatherSetterMove returns the move that sets a weather or terrain effect.
+// Terrains share their move's name.
+func weatherSetterMove(effect, kind string) string {
+	if kind == "terrain" {
+		return effect
+	}
+	return weatherMoves[strings.ToLower(effect)]
+}
@@ -0,0 +1,173 @@
+package analysis
+
+import "testing"
+
+const weatherSetterLog = `|player|p1|Alice|1|
+|player|p2|Bob|2|
+|start
+|switch|p1a: Torkoal|Torkoal, L50, M|100/100
+|switch|p2a: Pelipper|Pelipper, L50, F|100/100
+|-weather|SunnyDay|[from] ability: Drought|[of] p1a: Torkoal
+|-weather|RainDance|[from] ability: Drizzle|[of] p2a: Pelipper
+|turn|1
+|move|p1a: Torkoal|Sunny Day|p1a: Torkoal
+|-weather|SunnyDay
+|move|p2a: Pelipper|Grassy Terrain|p2a: Pelipper
+|-fieldstart|move: Grassy Terrain
+|-weather|SunnyDay|[upkeep]
+|upkeep
+|turn|2
+|-weather|none
+|win|Alice
+`
+
+func TestParseShowdownLogWeatherSetters(t *testing.T) {
+	summary, err := ParseShowdownLog(weatherSetterLog)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []WeatherSetter{
+		{Effect: "SunnyDay", Kind: "weather", Turn: 0, Source: "ability", Ability: "Drought", SetBy: "Torkoal", Player: "player1"},
+		{Effect: "RainDance", Kind: "weather", Turn: 0, Source: "ability", Ability: "Drizzle", SetBy: "Pelipper", Player: "player2"},
+		{Effect: "SunnyDay", Kind: "weather", Turn: 1, Source: "move", Move: "Sunny Day", SetBy: "Torkoal", Player: "player1"},
+		{Effect: "Grassy Terrain", Kind: "terrain", Turn: 1, Source: "move", Move: "Grassy Terrain", SetBy: "Pelipper", Player: "player2"},
+	}
+	if len(summary.WeatherSetters) != len(want) {
+		t.Fatalf("expected %d weather setters, got %d: %+v", len(want), len(summary.WeatherSetters), summary.WeatherSetters)
+	}
+	for i, w := range want {
+		if summary.WeatherSetters[i] != w {
+			t.Errorf("setter %d: expected %+v, got %+v", i, w, summary.WeatherSetters[i])
+		}
+	}
+}
+
+func TestParseShowdownLogAbilityTerrainOnSwitchIn(t *testing.T) {
+	log := `|player|p1|Alice|1|
+|player|p2|Bob|2|
+|gametype|doubles
+|start
+|switch|p1a: Amoonguss|Amoonguss, L50, F|100/100
+|switch|p2a: Garchomp|Garchomp, L50, M|100/100
+|turn|1
+|switch|p1a: Sparky|Pincurchin, L50, M|100/100
+|-fieldstart|move: Electric Terrain|[from] ability: Electric Surge|[of] p1a: Sparky
+|move|p2a: Garchomp|Earthquake|p1a: Sparky
+|-damage|p1a: Sparky|40/100
+|upkeep
+|turn|2
+|win|Alice
+`
+	for name, parse := range map[string]func(string) (*BattleSummary, error){
+		"basic":    ParseShowdownLog,
+		"enhanced": ParseEnhancedShowdownLog,
+	} {
+		t.Run(name, func(t *testing.T) {
+			summary, err := parse(log)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			want := WeatherSetter{Effect: "Electric Terrain", Kind: "terrain", Turn: 1, Source: "ability", Ability: "Electric Surge", SetBy: "Sparky", Player: "player1"}
+			if len(summary.WeatherSetters) != 1 || summary.WeatherSetters[0] != want {
+				t.Errorf("expected %+v, got %+v", want, summary.WeatherSetters)
+			}
+
+			abilities := summary.Turns[0].Abilities
+			if len(abilities) != 1 || abilities[0] != (AbilityActivation{Player: "player1", Pokemon: "Sparky", Ability: "Electric Surge"}) {
+				t.Errorf("expected Electric Surge to activate on the switch-in turn, got %+v", abilities)
+			}
+
+			if got := summary.Player1.RevealedAbilities["Pincurchin"]; got != "Electric Surge" {
+				t.Errorf("expected Pincurchin's Electric Surge to be revealed, got %q", got)
+			}
+		})
+	}
+}
+
+func TestParseShowdownLogFieldCountdown(t *testing.T) {
+	log := `|player|p1|Alice|1|
+|player|p2|Bob|2|
+|start
+|switch|p1a: Torkoal|Torkoal, L50, M|100/100
+|switch|p2a: Pincurchin|Pincurchin, L50, M|100/100
+|turn|1
+|move|p1a: Torkoal|Sunny Day|p1a: Torkoal
+|-weather|SunnyDay
+|move|p2a: Pincurchin|Electric Terrain|p2a: Pincurchin
+|-fieldstart|move: Electric Terrain
+|-weather|SunnyDay|[upkeep]
+|upkeep
+|turn|2
+|move|p1a: Torkoal|Protect|p1a: Torkoal
+|-weather|SunnyDay|[upkeep]
+|upkeep
+|turn|3
+|move|p2a: Pincurchin|Electric Terrain|p2a: Pincurchin
+|-fieldstart|move: Electric Terrain
+|-weather|SunnyDay|[upkeep]
+|upkeep
+|turn|4
+|-weather|SunnyDay|[upkeep]
+|upkeep
+|turn|5
+|-weather|none
+|upkeep
+|turn|6
+|upkeep
+|turn|7
+|-fieldend|move: Electric Terrain
+|upkeep
+|win|Alice
+`
+	wantWeather := []string{"Sun (4 turns left)", "Sun (3 turns left)", "Sun (2 turns left)", "Sun (1 turn left)", "", "", ""}
+	// Terrain restarts its five turns when set again on turn 3
+	wantTerrain := []string{
+		"Electric Terrain (4 turns left)", "Electric Terrain (3 turns left)", "Electric Terrain (4 turns left)",
+		"Electric Terrain (3 turns left)", "Electric Terrain (2 turns left)", "Electric Terrain (1 turn left)", "",
+	}
+
+	for name, parse := range map[string]func(string) (*BattleSummary, error){
+		"basic":    ParseShowdownLog,
+		"enhanced": ParseEnhancedShowdownLog,
+	} {
+		t.Run(name, func(t *testing.T) {
+			summary, err := parse(log)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(summary.Turns) != len(wantWeather) {
+				t.Fatalf("expected %d turns, got %d", len(wantWeather), len(summary.Turns))
+			}
+			for i, turn := range summary.Turns {
+				if turn.Weather != wantWeather[i] {
+					t.Errorf("turn %d: expected weather %q, got %q", turn.TurnNumber, wantWeather[i], turn.Weather)
+				}
+				if turn.Terrain != wantTerrain[i] {
+					t.Errorf("turn %d: expected terrain %q, got %q", turn.TurnNumber, wantTerrain[i], turn.Terrain)
+				}
+			}
+		})
+	}
+}
+
+func TestFieldCountdownExtendedWeather(t *testing.T) {
+	st := NewStateTracker()
+	st.UpdateField([]string{"", "-weather", "RainDance", "[from] ability: Drizzle", "[of] p2a: Pelipper"})
+
+	// Still raining after five turns means a Damp Rock
+	for turn := 1; turn <= 5; turn++ {
+		st.EndTurnField()
+		st.UpdateField([]string{"", "-weather", "RainDance", "[upkeep]"})
+	}
+	if weather, _ := st.EndTurnField(); weather != "Rain (2 turns left)" {
+		t.Errorf("expected an extended rain with 2 turns left after turn 6, got %q", weather)
+	}
+
+	// Primal weather has no countdown
+	st.UpdateField([]string{"", "-weather", "DesolateLand", "[from] ability: Desolate Land", "[of] p1a: Groudon"})
+	if weather, _ := st.EndTurnField(); weather != "Extremely Harsh Sunlight" {
+		t.Errorf("expected primal sun without a countdown, got %q", weather)
+	}
+}
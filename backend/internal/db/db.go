@@ -4,11 +4,25 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"sync"
+	"time"
+
+	"github.com/dtsong/vgccorner/backend/internal/observability"
 )
 
 // Database wraps a SQL database connection with helper methods.
 type Database struct {
-	conn *sql.DB
+	conn       *sql.DB
+	connString string // Empty when wrapping an existing pool
+
+	notifyChanges bool
+	subscribersMu sync.Mutex
+	subscribers   []func(battleID string)
+
+	closeOnce sync.Once
+
+	slowQueryLogger    *observability.Logger
+	slowQueryThreshold time.Duration // 0 disables slow-query logging
 }
 
 // NewDatabase creates a new Database instance.
@@ -22,12 +36,49 @@ func NewDatabase(connString string) (*Database, error) {
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
-	return &Database{conn: conn}, nil
+	return &Database{conn: conn, connString: connString}, nil
+}
+
+// NewDatabaseFromConn wraps an already-open connection pool, e.g. one shared
+// with another component or a test double.
+func NewDatabaseFromConn(conn *sql.DB) *Database {
+	return &Database{conn: conn}
+}
+
+// ConfigurePool bounds the connection pool: at most maxOpen connections, up to
+// maxIdle of them kept idle, each reused for at most maxLifetime. A zero
+// leaves database/sql's default (unlimited open, 2 idle, no lifetime).
+func (db *Database) ConfigurePool(maxOpen, maxIdle int, maxLifetime time.Duration) {
+	if maxOpen > 0 {
+		db.conn.SetMaxOpenConns(maxOpen)
+	}
+	if maxIdle > 0 {
+		db.conn.SetMaxIdleConns(maxIdle)
+	}
+	if maxLifetime > 0 {
+		db.conn.SetConnMaxLifetime(maxLifetime)
+	}
+}
+
+// PoolStats returns the connection pool's statistics, for spotting a
+// saturated pool: WaitCount and WaitDuration grow once every connection is in
+// use.
+func (db *Database) PoolStats() sql.DBStats {
+	return db.conn.Stats()
 }
 
-// Close closes the database connection.
+// Close closes the database connection. It is safe to call more than once,
+// and on a nil or unconnected Database; only the first call can return an
+// error.
 func (db *Database) Close() error {
-	return db.conn.Close()
+	if db == nil || db.conn == nil {
+		return nil
+	}
+	var err error
+	db.closeOnce.Do(func() {
+		err = db.conn.Close()
+	})
+	return err
 }
 
 // WithTx executes a function within a database transaction.
@@ -51,26 +102,38 @@ func (db *Database) WithTx(ctx context.Context, fn func(*sql.Tx) error) error {
 
 // Exec executes a query without returning rows.
 func (db *Database) Exec(ctx context.Context, query string, args ...interface{}) error {
+	start := db.queryStart()
 	_, err := db.conn.ExecContext(ctx, query, args...)
+	db.logSlowQuery(start, query)
 	return err
 }
 
 // QueryRow queries a single row.
 func (db *Database) QueryRow(ctx context.Context, query string, args ...interface{}) *sql.Row {
-	return db.conn.QueryRowContext(ctx, query, args...)
+	start := db.queryStart()
+	row := db.conn.QueryRowContext(ctx, query, args...)
+	db.logSlowQuery(start, query)
+	return row
 }
 
 // Query queries multiple rows.
 func (db *Database) Query(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
-	return db.conn.QueryContext(ctx, query, args...)
+	start := db.queryStart()
+	rows, err := db.conn.QueryContext(ctx, query, args...)
+	db.logSlowQuery(start, query)
+	return rows, err
 }
 
+// storeBattleRetries is how many times StoreBattle retries a transaction
+// aborted by a concurrent write.
+const storeBattleRetries = 3
+
 // StoreBattle saves a battle and related data to the database.
 // Returns the battle ID.
 func (db *Database) StoreBattle(ctx context.Context, battle *Battle) (string, error) {
 	var battleID string
 
-	err := db.WithTx(ctx, func(tx *sql.Tx) error {
+	err := db.WithTxRetry(ctx, storeBattleRetries, func(tx *sql.Tx) error {
 		// Insert battle
 		err := tx.QueryRowContext(ctx,
 			`INSERT INTO battles (format, timestamp, duration_sec, winner, player1_id, player2_id, battle_log, is_private, created_at, updated_at)
@@ -100,7 +163,16 @@ func (db *Database) StoreBattle(ctx context.Context, battle *Battle) (string, er
 			}
 		}
 
-		return nil
+		// Insert revealed teams
+		for _, poke := range battle.Teams {
+			err = insertBattlePokemon(ctx, tx, battleID, poke)
+			if err != nil {
+				return err
+			}
+		}
+
+		// Tell other instances to drop cached aggregates
+		return db.notifyBattleStored(ctx, tx, battleID)
 	})
 
 	return battleID, err
@@ -136,27 +208,43 @@ func (db *Database) GetBattle(ctx context.Context, battleID string) (*Battle, er
 	}
 	b.KeyMoments = moments
 
+	// Get revealed teams
+	teams, err := getBattlePokemon(ctx, db, battleID)
+	if err != nil {
+		return nil, err
+	}
+	b.Teams = teams
+
 	return &b, nil
 }
 
+// GetBattleAnalysis retrieves just the computed statistics for a battle. It
+// returns nil when the battle doesn't exist, and a zeroed BattleAnalysis when
+// the battle was stored without one.
+func (db *Database) GetBattleAnalysis(ctx context.Context, battleID string) (*BattleAnalysis, error) {
+	var exists bool
+	err := db.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM battles WHERE id = $1)`, battleID).Scan(&exists)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up battle: %w", err)
+	}
+	if !exists {
+		return nil, nil
+	}
+
+	analysis, err := getBattleAnalysis(ctx, db, battleID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get battle analysis: %w", err)
+	}
+	if analysis == nil {
+		return &BattleAnalysis{BattleID: battleID}, nil
+	}
+	return analysis, nil
+}
+
 // ListBattles retrieves battles with optional filtering.
 func (db *Database) ListBattles(ctx context.Context, filter *BattleFilter, limit int, offset int) ([]*Battle, int, error) {
-	query := `SELECT id, format, timestamp, duration_sec, winner, player1_id, player2_id, is_private FROM battles WHERE 1=1`
-	var args []interface{}
-	argIndex := 1
-
-	if filter != nil {
-		if filter.Format != "" {
-			query += fmt.Sprintf(" AND format = $%d", argIndex)
-			args = append(args, filter.Format)
-			argIndex++
-		}
-		if filter.IsPrivate != nil {
-			query += fmt.Sprintf(" AND is_private = $%d", argIndex)
-			args = append(args, *filter.IsPrivate)
-			argIndex++
-		}
-	}
+	query, args := applyBattleFilter(`SELECT id, format, timestamp, duration_sec, winner, player1_id, player2_id, is_private FROM battles WHERE 1=1`, nil, filter)
+	argIndex := len(args) + 1
 
 	// Get total count
 	countQuery := "SELECT COUNT(*) FROM battles WHERE 1=1"
@@ -195,8 +283,87 @@ func (db *Database) ListBattles(ctx context.Context, filter *BattleFilter, limit
 	return battles, total, rows.Err()
 }
 
+// ListBattleLogs retrieves the raw logs of the most recent battles matching the filter.
+func (db *Database) ListBattleLogs(ctx context.Context, filter *BattleFilter, limit int) ([]string, error) {
+	query, args := applyBattleFilter(`SELECT battle_log FROM battles WHERE battle_log IS NOT NULL`, nil, filter)
+
+	query += fmt.Sprintf(" ORDER BY timestamp DESC LIMIT $%d", len(args)+1)
+	args = append(args, limit)
+
+	rows, err := db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var logs []string
+	for rows.Next() {
+		var battleLog string
+		if err := rows.Scan(&battleLog); err != nil {
+			return nil, err
+		}
+		logs = append(logs, battleLog)
+	}
+
+	return logs, rows.Err()
+}
+
+// CountBattlesByFormat returns the number of battles per format. The filter's
+// Since/Until bound the battle timestamp; an empty map means no battles.
+func (db *Database) CountBattlesByFormat(ctx context.Context, filter *BattleFilter) (map[string]int, error) {
+	query, args := applyBattleFilter(`SELECT format, COUNT(*) FROM battles WHERE 1=1`, nil, filter)
+	query += " GROUP BY format"
+
+	rows, err := db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count battles by format: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var format string
+		var count int
+		if err := rows.Scan(&format, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan format count: %w", err)
+		}
+		counts[format] = count
+	}
+
+	return counts, rows.Err()
+}
+
 // Helper functions
 
+// applyBattleFilter appends the filter's conditions to a query ending in a
+// WHERE clause, numbering placeholders after the existing args.
+func applyBattleFilter(query string, args []interface{}, filter *BattleFilter) (string, []interface{}) {
+	if filter == nil {
+		return query, args
+	}
+	if filter.Format != "" {
+		args = append(args, filter.Format)
+		query += fmt.Sprintf(" AND format = $%d", len(args))
+	}
+	if filter.IsPrivate != nil {
+		args = append(args, *filter.IsPrivate)
+		query += fmt.Sprintf(" AND is_private = $%d", len(args))
+	}
+	if !filter.Since.IsZero() {
+		args = append(args, filter.Since)
+		query += fmt.Sprintf(" AND timestamp >= $%d", len(args))
+	}
+	if !filter.Until.IsZero() {
+		args = append(args, filter.Until)
+		query += fmt.Sprintf(" AND timestamp < $%d", len(args))
+	}
+	return query, args
+}
+
 func insertBattleAnalysis(ctx context.Context, tx *sql.Tx, battleID string, analysis *BattleAnalysis) error {
 	_, err := tx.ExecContext(ctx,
 		`INSERT INTO battle_analysis (battle_id, total_turns, avg_damage_per_turn, avg_heal_per_turn, moves_used_count, switches_count, super_effective_moves, not_very_effective_moves, critical_hits, player1_damage_dealt, player1_damage_taken, player1_healing_done, player2_damage_dealt, player2_damage_taken, player2_healing_done, created_at)
@@ -265,3 +432,64 @@ func getKeyMoments(ctx context.Context, db *Database, battleID string) ([]*KeyMo
 
 	return moments, rows.Err()
 }
+
+func insertBattlePokemon(ctx context.Context, tx *sql.Tx, battleID string, poke *BattlePokemon) error {
+	_, err := tx.ExecContext(ctx,
+		`INSERT INTO battle_pokemon (battle_id, player_number, slot, species, ability, item, tera_type, lead, created_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW())`,
+		battleID, playerNumber(poke.Player), poke.Slot, poke.Species,
+		nullIfEmpty(poke.Ability), nullIfEmpty(poke.Item), nullIfEmpty(poke.TeraType), poke.Lead,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert battle pokemon: %w", err)
+	}
+	return nil
+}
+
+// getBattlePokemon returns the battle's revealed team members, or an empty
+// slice if none were stored.
+func getBattlePokemon(ctx context.Context, db *Database, battleID string) ([]*BattlePokemon, error) {
+	rows, err := db.Query(ctx,
+		`SELECT player_number, slot, species, ability, item, tera_type, lead FROM battle_pokemon WHERE battle_id = $1 ORDER BY player_number, slot`,
+		battleID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	team := []*BattlePokemon{}
+	for rows.Next() {
+		var p BattlePokemon
+		var playerNum int
+		var ability, item, teraType sql.NullString
+		if err := rows.Scan(&playerNum, &p.Slot, &p.Species, &ability, &item, &teraType, &p.Lead); err != nil {
+			return nil, err
+		}
+		p.Player = fmt.Sprintf("player%d", playerNum)
+		p.Ability = ability.String
+		p.Item = item.String
+		p.TeraType = teraType.String
+		team = append(team, &p)
+	}
+
+	return team, rows.Err()
+}
+
+// playerNumber converts "player1"/"player2" to the player_number column value.
+func playerNumber(player string) int {
+	if player == "player2" {
+		return 2
+	}
+	return 1
+}
+
+// nullIfEmpty stores empty strings as NULL.
+func nullIfEmpty(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
@@ -50,6 +50,55 @@ func TestClose(t *testing.T) {
 	}
 }
 
+func TestPoolStats(t *testing.T) {
+	db, _, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	database := &Database{conn: db}
+	database.ConfigurePool(7, 0, 0)
+
+	stats := database.PoolStats()
+	if stats.MaxOpenConnections != 7 {
+		t.Errorf("expected MaxOpenConnections 7, got %d", stats.MaxOpenConnections)
+	}
+	if stats.InUse != 0 || stats.WaitCount != 0 {
+		t.Errorf("expected an unused pool, got %+v", stats)
+	}
+}
+
+func TestCloseTwice(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+
+	database := &Database{conn: db}
+
+	// A second close of the pool would fail the expectations
+	mock.ExpectClose()
+	if err := database.Close(); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if err := database.Close(); err != nil {
+		t.Errorf("expected second Close to return nil, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+
+	var unset *Database
+	if err := unset.Close(); err != nil {
+		t.Errorf("expected Close on a nil Database to return nil, got %v", err)
+	}
+	if err := (&Database{}).Close(); err != nil {
+		t.Errorf("expected Close without a connection to return nil, got %v", err)
+	}
+}
+
 func TestExec(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
@@ -283,6 +332,11 @@ func TestGetBattle(t *testing.T) {
 		WithArgs(battleID).
 		WillReturnRows(sqlmock.NewRows([]string{"turn_number", "moment_type", "description", "significance"}))
 
+	// Mock revealed teams query; none stored
+	mock.ExpectQuery("SELECT (.+) FROM battle_pokemon WHERE battle_id").
+		WithArgs(battleID).
+		WillReturnRows(sqlmock.NewRows([]string{"player_number", "slot", "species", "ability", "item", "tera_type", "lead"}))
+
 	battle, err := database.GetBattle(ctx, battleID)
 	if err != nil {
 		t.Errorf("expected no error, got %v", err)
@@ -300,6 +354,10 @@ func TestGetBattle(t *testing.T) {
 		t.Errorf("expected format 'VGC 2025', got %s", battle.Format)
 	}
 
+	if battle.Teams == nil || len(battle.Teams) != 0 {
+		t.Errorf("expected empty non-nil teams, got %v", battle.Teams)
+	}
+
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Errorf("unmet expectations: %v", err)
 	}
@@ -437,3 +495,165 @@ func TestWithTx(t *testing.T) {
 func boolPtr(b bool) *bool {
 	return &b
 }
+
+func TestListBattleLogs(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	database := NewDatabaseFromConn(db)
+	ctx := context.Background()
+
+	mock.ExpectQuery("SELECT battle_log FROM battles").
+		WithArgs("VGC 2025", false, 50).
+		WillReturnRows(sqlmock.NewRows([]string{"battle_log"}).
+			AddRow("log one").
+			AddRow("log two"))
+
+	logs, err := database.ListBattleLogs(ctx, &BattleFilter{Format: "VGC 2025", IsPrivate: boolPtr(false)}, 50)
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+
+	if len(logs) != 2 || logs[0] != "log one" {
+		t.Errorf("expected 2 logs, got %v", logs)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestCountBattlesByFormat(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	database := &Database{conn: db}
+	ctx := context.Background()
+
+	since := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(`SELECT format, COUNT\(\*\) FROM battles WHERE 1=1 AND timestamp >= \$1 GROUP BY format`).
+		WithArgs(since).
+		WillReturnRows(sqlmock.NewRows([]string{"format", "count"}).
+			AddRow("VGC 2025 Reg H", 3).
+			AddRow("VGC 2025 Reg G", 1))
+
+	counts, err := database.CountBattlesByFormat(ctx, &BattleFilter{Since: since})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if counts["VGC 2025 Reg H"] != 3 || counts["VGC 2025 Reg G"] != 1 {
+		t.Errorf("expected counts 3 and 1, got %v", counts)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestCountBattlesByFormatEmpty(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	database := &Database{conn: db}
+
+	mock.ExpectQuery("SELECT format, COUNT").
+		WillReturnRows(sqlmock.NewRows([]string{"format", "count"}))
+
+	counts, err := database.CountBattlesByFormat(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if counts == nil || len(counts) != 0 {
+		t.Errorf("expected empty non-nil map, got %v", counts)
+	}
+}
+
+func TestBattleTeamsRoundTrip(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	database := &Database{conn: db}
+	ctx := context.Background()
+	timestamp := time.Now()
+
+	battle := &Battle{
+		Format:      "VGC 2025",
+		Timestamp:   timestamp,
+		DurationSec: 300,
+		Winner:      "player1",
+		Player1ID:   "Alice",
+		Player2ID:   "Bob",
+		BattleLog:   "battle log content",
+		Teams: []*BattlePokemon{
+			{Player: "player1", Slot: 0, Species: "Incineroar", Ability: "Intimidate", Item: "Sitrus Berry", Lead: true},
+			{Player: "player2", Slot: 0, Species: "Amoonguss", Ability: "Regenerator"},
+		},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO battles").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("battle-uuid"))
+	mock.ExpectExec("INSERT INTO battle_pokemon").
+		WithArgs("battle-uuid", 1, 0, "Incineroar", "Intimidate", "Sitrus Berry", nil, true).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO battle_pokemon").
+		WithArgs("battle-uuid", 2, 0, "Amoonguss", "Regenerator", nil, nil, false).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	battleID, err := database.StoreBattle(ctx, battle)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	mock.ExpectQuery("SELECT (.+) FROM battles WHERE id").
+		WithArgs(battleID).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "format", "timestamp", "duration_sec", "winner",
+			"player1_id", "player2_id", "battle_log", "is_private",
+			"created_at", "updated_at",
+		}).AddRow(battleID, "VGC 2025", timestamp, 300, "player1", "Alice", "Bob", "battle log content", false, timestamp, timestamp))
+	mock.ExpectQuery("SELECT (.+) FROM battle_analysis WHERE battle_id").
+		WithArgs(battleID).
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery("SELECT (.+) FROM key_moments WHERE battle_id").
+		WithArgs(battleID).
+		WillReturnRows(sqlmock.NewRows([]string{"turn_number", "moment_type", "description", "significance"}))
+	mock.ExpectQuery("SELECT (.+) FROM battle_pokemon WHERE battle_id").
+		WithArgs(battleID).
+		WillReturnRows(sqlmock.NewRows([]string{"player_number", "slot", "species", "ability", "item", "tera_type", "lead"}).
+			AddRow(1, 0, "Incineroar", "Intimidate", "Sitrus Berry", nil, true).
+			AddRow(2, 0, "Amoonguss", "Regenerator", nil, nil, false))
+
+	got, err := database.GetBattle(ctx, battleID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(got.Teams) != 2 {
+		t.Fatalf("expected 2 team members, got %d", len(got.Teams))
+	}
+	for i, want := range battle.Teams {
+		if *got.Teams[i] != *want {
+			t.Errorf("team member %d: expected %+v, got %+v", i, *want, *got.Teams[i])
+		}
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
@@ -0,0 +1,44 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+// maintainedTables are the tables TableStats covers.
+var maintainedTables = []string{"battles", "battle_analysis", "key_moments"}
+
+// TableStats returns the approximate row count and on-disk size of each
+// maintained table, keyed by table name. Row counts are the planner's
+// estimates from pg_class.reltuples, refreshed by VACUUM, ANALYZE and
+// autovacuum, so reading them never scans a table; a table that has never
+// been analyzed reports 0. A table the connection may not read is reported
+// with PermissionDenied set instead of failing the whole call.
+func (db *Database) TableStats(ctx context.Context) (map[string]TableStat, error) {
+	stats := make(map[string]TableStat, len(maintainedTables))
+	for _, table := range maintainedTables {
+		var stat TableStat
+		err := db.QueryRow(ctx,
+			`SELECT GREATEST(reltuples, 0)::bigint, pg_total_relation_size(oid) FROM pg_class WHERE oid = $1::regclass`,
+			table,
+		).Scan(&stat.Rows, &stat.SizeBytes)
+		if isPermissionDenied(err) {
+			stats[table] = TableStat{PermissionDenied: true}
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read stats for %s: %w", table, err)
+		}
+		stats[table] = stat
+	}
+	return stats, nil
+}
+
+// isPermissionDenied reports whether err is insufficient_privilege (42501).
+func isPermissionDenied(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "42501"
+}
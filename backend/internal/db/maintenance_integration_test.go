@@ -0,0 +1,60 @@
+//go:build integration
+
+package db
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+)
+
+// Run with: TEST_DATABASE_URL=postgres://... go test -tags integration ./internal/db/
+func TestTableStatsIntegration(t *testing.T) {
+	connString := os.Getenv("TEST_DATABASE_URL")
+	if connString == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+
+	database, err := NewDatabase(connString)
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	defer func() { _ = database.Close() }()
+	ctx := context.Background()
+
+	if err := database.Exec(ctx, `TRUNCATE battles CASCADE`); err != nil {
+		t.Fatalf("failed to truncate battles: %v", err)
+	}
+
+	const inserted = 3
+	for i := 0; i < inserted; i++ {
+		_, err := database.StoreBattle(ctx, &Battle{
+			Format:    "gen9vgc2024regg",
+			Timestamp: time.Now(),
+			Player1ID: "alice",
+			Player2ID: "bob",
+			Winner:    "player1",
+			BattleLog: "|win|alice",
+		})
+		if err != nil {
+			t.Fatalf("failed to store battle: %v", err)
+		}
+	}
+
+	// Row counts are planner estimates; ANALYZE makes them exact for a table this small
+	if err := database.Exec(ctx, `ANALYZE battles`); err != nil {
+		t.Fatalf("failed to analyze battles: %v", err)
+	}
+
+	stats, err := database.TableStats(ctx)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got := stats["battles"]; got.Rows != inserted {
+		t.Errorf("expected %d battles, got %+v", inserted, got)
+	}
+	if got := stats["battles"]; got.SizeBytes <= 0 {
+		t.Errorf("expected a non-zero table size, got %+v", got)
+	}
+}
@@ -0,0 +1,61 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+)
+
+func TestTableStats(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+	database := &Database{conn: db}
+
+	mock.ExpectQuery(`SELECT GREATEST\(reltuples, 0\)::bigint, pg_total_relation_size\(oid\) FROM pg_class WHERE oid = \$1::regclass`).
+		WithArgs("battles").
+		WillReturnRows(sqlmock.NewRows([]string{"reltuples", "size"}).AddRow(12, 81920))
+	mock.ExpectQuery(`FROM pg_class`).
+		WithArgs("battle_analysis").
+		WillReturnError(&pq.Error{Code: "42501", Message: "permission denied for table battle_analysis"})
+	mock.ExpectQuery(`FROM pg_class`).
+		WithArgs("key_moments").
+		WillReturnRows(sqlmock.NewRows([]string{"reltuples", "size"}).AddRow(40, 16384))
+
+	stats, err := database.TableStats(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if got := stats["battles"]; got.Rows != 12 || got.SizeBytes != 81920 {
+		t.Errorf("expected battles 12 rows/81920 bytes, got %+v", got)
+	}
+	if got := stats["battle_analysis"]; !got.PermissionDenied {
+		t.Errorf("expected battle_analysis to be marked permission denied, got %+v", got)
+	}
+	if got := stats["key_moments"]; got.Rows != 40 {
+		t.Errorf("expected 40 key_moments rows, got %+v", got)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestTableStatsQueryError(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+	database := &Database{conn: db}
+
+	mock.ExpectQuery(`FROM pg_class`).WillReturnError(context.DeadlineExceeded)
+
+	if _, err := database.TableStats(context.Background()); err == nil {
+		t.Error("expected an error")
+	}
+}
@@ -0,0 +1,94 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/dtsong/vgccorner/backend/internal/analysis"
+)
+
+// StoreMetaStats saves precomputed meta stats under their format, replacing
+// any earlier computation. An empty format is the all-formats aggregate.
+func (db *Database) StoreMetaStats(ctx context.Context, meta analysis.MetaStats) error {
+	stats, err := json.Marshal(meta)
+	if err != nil {
+		return fmt.Errorf("failed to encode meta stats: %w", err)
+	}
+	err = db.Exec(ctx,
+		`INSERT INTO meta_stats (format, stats, battle_count, computed_at)
+		 VALUES ($1, $2, $3, NOW())
+		 ON CONFLICT (format) DO UPDATE
+		 SET stats = EXCLUDED.stats, battle_count = EXCLUDED.battle_count, computed_at = EXCLUDED.computed_at`,
+		meta.Format, stats, meta.BattleCount,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to store meta stats: %w", err)
+	}
+	return nil
+}
+
+// GetMetaStats returns the stored meta stats for a format, or nil if none
+// have been computed yet.
+func (db *Database) GetMetaStats(ctx context.Context, format string) (*StoredMetaStats, error) {
+	var stored StoredMetaStats
+	var stats []byte
+	err := db.QueryRow(ctx,
+		`SELECT stats, computed_at FROM meta_stats WHERE format = $1`,
+		format,
+	).Scan(&stats, &stored.ComputedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get meta stats: %w", err)
+	}
+	if err := json.Unmarshal(stats, &stored.Stats); err != nil {
+		return nil, fmt.Errorf("failed to decode meta stats: %w", err)
+	}
+	return &stored, nil
+}
+
+// StoreTypeEffectiveness saves precomputed type effectiveness stats under
+// their format, replacing any earlier computation. An empty format is the
+// all-formats aggregate.
+func (db *Database) StoreTypeEffectiveness(ctx context.Context, matchups analysis.TypeMatchupStats) error {
+	stats, err := json.Marshal(matchups)
+	if err != nil {
+		return fmt.Errorf("failed to encode type effectiveness stats: %w", err)
+	}
+	err = db.Exec(ctx,
+		`INSERT INTO type_effectiveness_stats (format, stats, battle_count, computed_at)
+		 VALUES ($1, $2, $3, NOW())
+		 ON CONFLICT (format) DO UPDATE
+		 SET stats = EXCLUDED.stats, battle_count = EXCLUDED.battle_count, computed_at = EXCLUDED.computed_at`,
+		matchups.Format, stats, matchups.BattleCount,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to store type effectiveness stats: %w", err)
+	}
+	return nil
+}
+
+// GetTypeEffectiveness returns the stored type effectiveness stats for a
+// format, or nil if none have been computed yet.
+func (db *Database) GetTypeEffectiveness(ctx context.Context, format string) (*StoredTypeEffectiveness, error) {
+	var stored StoredTypeEffectiveness
+	var stats []byte
+	err := db.QueryRow(ctx,
+		`SELECT stats, computed_at FROM type_effectiveness_stats WHERE format = $1`,
+		format,
+	).Scan(&stats, &stored.ComputedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get type effectiveness stats: %w", err)
+	}
+	if err := json.Unmarshal(stats, &stored.Stats); err != nil {
+		return nil, fmt.Errorf("failed to decode type effectiveness stats: %w", err)
+	}
+	return &stored, nil
+}
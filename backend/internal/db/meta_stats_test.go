@@ -0,0 +1,72 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/dtsong/vgccorner/backend/internal/analysis"
+)
+
+func TestStoreMetaStats(t *testing.T) {
+	conn, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+	database := &Database{conn: conn}
+
+	mock.ExpectExec("INSERT INTO meta_stats .* ON CONFLICT \\(format\\) DO UPDATE").
+		WithArgs("gen9vgc2025regh", sqlmock.AnyArg(), 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	meta := analysis.MetaStats{Format: "gen9vgc2025regh", BattleCount: 3}
+	if err := database.StoreMetaStats(context.Background(), meta); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestGetMetaStatsMissing(t *testing.T) {
+	conn, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+	database := &Database{conn: conn}
+
+	mock.ExpectQuery("SELECT stats, computed_at FROM meta_stats").
+		WithArgs("gen9vgc2025regh").
+		WillReturnRows(sqlmock.NewRows([]string{"stats", "computed_at"}))
+
+	stored, err := database.GetMetaStats(context.Background(), "gen9vgc2025regh")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stored != nil {
+		t.Errorf("expected nil before the first computation, got %+v", stored)
+	}
+}
+
+func TestStoreTypeEffectiveness(t *testing.T) {
+	conn, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+	database := &Database{conn: conn}
+
+	mock.ExpectExec("INSERT INTO type_effectiveness_stats .* ON CONFLICT \\(format\\) DO UPDATE").
+		WithArgs("gen9vgc2025regh", sqlmock.AnyArg(), 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	matchups := analysis.TypeMatchupStats{Format: "gen9vgc2025regh", BattleCount: 3}
+	if err := database.StoreTypeEffectiveness(context.Background(), matchups); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
@@ -0,0 +1,96 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// battlesChangedChannel is the Postgres NOTIFY channel announcing stored
+// battles. The payload is the battle ID.
+const battlesChangedChannel = "battles_changed"
+
+// ErrNotificationsUnsupported is returned by ListenForChanges when the
+// database wasn't opened from a connection string (e.g. NewDatabaseFromConn).
+var ErrNotificationsUnsupported = errors.New("change notifications require a database opened with NewDatabase")
+
+// EnableChangeNotifications makes StoreBattle NOTIFY other instances so they
+// can invalidate their caches.
+func (db *Database) EnableChangeNotifications() {
+	db.notifyChanges = true
+}
+
+// OnBattlesChanged registers fn to run when any instance stores a battle.
+// battleID is empty when notifications may have been missed (e.g. after a
+// reconnect), meaning everything should be treated as stale.
+func (db *Database) OnBattlesChanged(fn func(battleID string)) {
+	db.subscribersMu.Lock()
+	defer db.subscribersMu.Unlock()
+	db.subscribers = append(db.subscribers, fn)
+}
+
+func (db *Database) dispatchBattlesChanged(battleID string) {
+	db.subscribersMu.Lock()
+	subscribers := append([]func(string){}, db.subscribers...)
+	db.subscribersMu.Unlock()
+
+	for _, fn := range subscribers {
+		fn(battleID)
+	}
+}
+
+// notifyBattleStored queues a NOTIFY in the transaction; Postgres only
+// delivers it if the transaction commits.
+func (db *Database) notifyBattleStored(ctx context.Context, tx *sql.Tx, battleID string) error {
+	if !db.notifyChanges {
+		return nil
+	}
+	if _, err := tx.ExecContext(ctx, `SELECT pg_notify($1, $2)`, battlesChangedChannel, battleID); err != nil {
+		return fmt.Errorf("failed to notify battle change: %w", err)
+	}
+	return nil
+}
+
+// ListenForChanges subscribes to battle change notifications from all
+// instances until ctx is cancelled, dispatching them to OnBattlesChanged
+// callbacks.
+func (db *Database) ListenForChanges(ctx context.Context, onError func(error)) error {
+	if db.connString == "" {
+		return ErrNotificationsUnsupported
+	}
+
+	listener := pq.NewListener(db.connString, 10*time.Second, time.Minute, func(_ pq.ListenerEventType, err error) {
+		if err != nil && onError != nil {
+			onError(err)
+		}
+	})
+	if err := listener.Listen(battlesChangedChannel); err != nil {
+		_ = listener.Close()
+		return fmt.Errorf("failed to listen for battle changes: %w", err)
+	}
+
+	go func() {
+		defer func() { _ = listener.Close() }()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case n := <-listener.Notify:
+				if n == nil {
+					// Reconnected; anything sent meanwhile was lost
+					db.dispatchBattlesChanged("")
+					continue
+				}
+				db.dispatchBattlesChanged(n.Extra)
+			case <-time.After(90 * time.Second):
+				go func() { _ = listener.Ping() }()
+			}
+		}
+	}()
+
+	return nil
+}
@@ -0,0 +1,70 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestStoreBattleNotifiesChange(t *testing.T) {
+	conn, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	database := NewDatabaseFromConn(conn)
+	database.EnableChangeNotifications()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO battles").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("battle-uuid"))
+	mock.ExpectExec(`SELECT pg_notify\(\$1, \$2\)`).
+		WithArgs(battlesChangedChannel, "battle-uuid").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	_, err = database.StoreBattle(context.Background(), &Battle{
+		Format:    "VGC 2025",
+		Timestamp: time.Now(),
+		Player1ID: "Alice",
+		Player2ID: "Bob",
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestOnBattlesChanged(t *testing.T) {
+	database := &Database{}
+
+	var got []string
+	database.OnBattlesChanged(func(battleID string) { got = append(got, battleID) })
+	database.OnBattlesChanged(func(battleID string) { got = append(got, "second:"+battleID) })
+
+	database.dispatchBattlesChanged("battle-uuid")
+
+	if len(got) != 2 || got[0] != "battle-uuid" || got[1] != "second:battle-uuid" {
+		t.Errorf("expected both subscribers called, got %v", got)
+	}
+}
+
+func TestListenForChangesUnsupported(t *testing.T) {
+	conn, _, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	err = NewDatabaseFromConn(conn).ListenForChanges(context.Background(), nil)
+	if !errors.Is(err, ErrNotificationsUnsupported) {
+		t.Errorf("expected ErrNotificationsUnsupported, got %v", err)
+	}
+}
@@ -0,0 +1,272 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/dtsong/vgccorner/backend/internal/analysis"
+)
+
+const (
+	// playerTopPokemonLimit caps PlayerStats.TopPokemon.
+	playerTopPokemonLimit = 6
+
+	// playerLeadBattles bounds how many recent battles PlayerStats reads
+	// leads from.
+	playerLeadBattles = 500
+
+	// defaultRecentBattles and maxRecentBattles bound RecentBattlesForPlayer.
+	defaultRecentBattles = 10
+	maxRecentBattles     = 100
+)
+
+// HeadToHead returns the record between two players across all stored battles
+// involving both of them, regardless of which slot each player occupied.
+func (db *Database) HeadToHead(ctx context.Context, player1ID, player2ID string) (*H2HRecord, error) {
+	rows, err := db.Query(ctx,
+		`SELECT player1_id, player2_id, winner FROM battles
+		 WHERE (player1_id = $1 AND player2_id = $2) OR (player1_id = $2 AND player2_id = $1)`,
+		player1ID, player2ID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query head-to-head battles: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	record := &H2HRecord{Player1ID: player1ID, Player2ID: player2ID}
+	for rows.Next() {
+		var slot1, slot2 string
+		var winner sql.NullString
+		if err := rows.Scan(&slot1, &slot2, &winner); err != nil {
+			return nil, err
+		}
+		record.GamesPlayed++
+
+		var winnerID string
+		switch winner.String {
+		case "player1":
+			winnerID = slot1
+		case "player2":
+			winnerID = slot2
+		default:
+			record.Draws++
+			continue
+		}
+
+		switch winnerID {
+		case player1ID:
+			record.Player1Wins++
+		case player2ID:
+			record.Player2Wins++
+		}
+	}
+
+	return record, rows.Err()
+}
+
+// RecentBattlesForPlayer returns a player's latest battles in either slot,
+// newest first. A non-positive limit means defaultRecentBattles, and limits
+// above maxRecentBattles are capped. Battle logs, analysis, and teams are
+// not loaded.
+func (db *Database) RecentBattlesForPlayer(ctx context.Context, playerID string, limit int) ([]*Battle, error) {
+	if limit <= 0 {
+		limit = defaultRecentBattles
+	}
+	if limit > maxRecentBattles {
+		limit = maxRecentBattles
+	}
+
+	rows, err := db.Query(ctx,
+		`SELECT id, format, timestamp, duration_sec, winner, player1_id, player2_id, is_private FROM battles
+		 WHERE player1_id = $1 OR player2_id = $1
+		 ORDER BY timestamp DESC
+		 LIMIT $2`,
+		playerID, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query recent battles: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	battles := []*Battle{}
+	for rows.Next() {
+		var b Battle
+		var winner sql.NullString
+		if err := rows.Scan(&b.ID, &b.Format, &b.Timestamp, &b.DurationSec, &winner, &b.Player1ID, &b.Player2ID, &b.IsPrivate); err != nil {
+			return nil, err
+		}
+		b.Winner = winner.String
+		battles = append(battles, &b)
+	}
+
+	return battles, rows.Err()
+}
+
+// PlayerStats aggregates a player's record, most-registered Pokémon, and
+// favorite lead across stored public battles in either slot. A player with
+// no stored battles gets zeroed stats rather than an error.
+func (db *Database) PlayerStats(ctx context.Context, playerID string) (*PlayerStats, error) {
+	stats := &PlayerStats{
+		PlayerID:     playerID,
+		TopPokemon:   []SpeciesUsage{},
+		FavoriteLead: []string{},
+	}
+
+	var decided int
+	err := db.QueryRow(ctx,
+		`SELECT COUNT(*),
+		        COUNT(*) FILTER (WHERE (player1_id = $1 AND winner = 'player1') OR (player2_id = $1 AND winner = 'player2')),
+		        COUNT(*) FILTER (WHERE winner IN ('player1', 'player2'))
+		 FROM battles
+		 WHERE (player1_id = $1 OR player2_id = $1) AND is_private = false`,
+		playerID,
+	).Scan(&stats.GamesPlayed, &stats.Wins, &decided)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count player battles: %w", err)
+	}
+	stats.Losses = decided - stats.Wins
+	stats.Draws = stats.GamesPlayed - decided
+	if stats.GamesPlayed > 0 {
+		stats.WinRate = float64(stats.Wins) / float64(stats.GamesPlayed)
+	}
+
+	if lead, err := playerFavoriteLead(ctx, db, playerID); err != nil {
+		return nil, err
+	} else if lead != "" {
+		stats.FavoriteLead = strings.Split(lead, "|")
+	}
+
+	stats.TopPokemon, err = playerTopPokemon(ctx, db, playerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// playerFavoriteLead returns the lead a player used most across their
+// playerLeadBattles most recent public battles, as an analysis.LeadKey, or ""
+// when none of those battles have leads marked.
+func playerFavoriteLead(ctx context.Context, db *Database, playerID string) (string, error) {
+	rows, err := db.Query(ctx,
+		`WITH recent AS (
+		     SELECT id, CASE WHEN player1_id = $1 THEN 1 ELSE 2 END AS player_number FROM battles
+		     WHERE (player1_id = $1 OR player2_id = $1) AND is_private = false
+		     ORDER BY timestamp DESC
+		     LIMIT $2
+		 )
+		 SELECT bp.battle_id, bp.species FROM battle_pokemon bp
+		 JOIN recent r ON r.id = bp.battle_id AND r.player_number = bp.player_number
+		 WHERE bp.lead`,
+		playerID, playerLeadBattles,
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to query player leads: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	leads := make(map[string][]string)
+	for rows.Next() {
+		var battleID, species string
+		if err := rows.Scan(&battleID, &species); err != nil {
+			return "", err
+		}
+		leads[battleID] = append(leads[battleID], species)
+	}
+	if err := rows.Err(); err != nil {
+		return "", err
+	}
+
+	counts := make(map[string]int)
+	for _, lead := range leads {
+		counts[analysis.LeadKey(lead)]++
+	}
+	return mostCommon(counts), nil
+}
+
+// playerTopPokemon counts the species on a player's stored public teams. Teams are
+// the full team preview, so in bring-4-of-6 formats this includes Pokémon
+// that stayed in the back.
+func playerTopPokemon(ctx context.Context, db *Database, playerID string) ([]SpeciesUsage, error) {
+	rows, err := db.Query(ctx,
+		`SELECT bp.species, COUNT(*) FROM battle_pokemon bp
+		 JOIN battles b ON b.id = bp.battle_id
+		 WHERE ((b.player1_id = $1 AND bp.player_number = 1) OR (b.player2_id = $1 AND bp.player_number = 2))
+		   AND b.is_private = false
+		 GROUP BY bp.species
+		 ORDER BY COUNT(*) DESC, bp.species
+		 LIMIT $2`,
+		playerID, playerTopPokemonLimit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query player pokemon usage: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	usage := []SpeciesUsage{}
+	for rows.Next() {
+		var u SpeciesUsage
+		if err := rows.Scan(&u.Species, &u.Count); err != nil {
+			return nil, err
+		}
+		usage = append(usage, u)
+	}
+
+	return usage, rows.Err()
+}
+
+// PlayerBattleLogs returns the logs of a player's most recent public battles
+// in either slot, newest first, up to limit. The logs aren't parsed here;
+// callers aggregate them once the query has finished.
+func (db *Database) PlayerBattleLogs(ctx context.Context, playerID string, limit int) ([]PlayerBattleLog, error) {
+	rows, err := db.Query(ctx,
+		`SELECT player1_id, battle_log FROM battles
+		 WHERE (player1_id = $1 OR player2_id = $1) AND is_private = false AND battle_log IS NOT NULL
+		 ORDER BY timestamp DESC
+		 LIMIT $2`,
+		playerID, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query player battles: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	logs := []PlayerBattleLog{}
+	for rows.Next() {
+		var slot1 string
+		var l PlayerBattleLog
+		if err := rows.Scan(&slot1, &l.BattleLog); err != nil {
+			return nil, err
+		}
+		l.Slot = "player2"
+		if slot1 == playerID {
+			l.Slot = "player1"
+		}
+		logs = append(logs, l)
+	}
+
+	return logs, rows.Err()
+}
+
+// mostCommon returns the key with the highest count, breaking ties alphabetically.
+func mostCommon(counts map[string]int) string {
+	best := ""
+	for key, count := range counts {
+		if count > counts[best] || (count == counts[best] && best != "" && key < best) {
+			best = key
+		}
+	}
+	return best
+}
@@ -0,0 +1,251 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestHeadToHead(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	database := &Database{conn: db}
+	ctx := context.Background()
+
+	// Alice wins once from each slot, Bob wins once from slot 1, one draw
+	rows := sqlmock.NewRows([]string{"player1_id", "player2_id", "winner"}).
+		AddRow("Alice", "Bob", "player1").
+		AddRow("Bob", "Alice", "player2").
+		AddRow("Bob", "Alice", "player1").
+		AddRow("Alice", "Bob", nil)
+	mock.ExpectQuery("SELECT player1_id, player2_id, winner FROM battles").
+		WithArgs("Alice", "Bob").
+		WillReturnRows(rows)
+
+	record, err := database.HeadToHead(ctx, "Alice", "Bob")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if record.GamesPlayed != 4 {
+		t.Errorf("expected 4 games, got %d", record.GamesPlayed)
+	}
+	if record.Player1Wins != 2 {
+		t.Errorf("expected Alice to have 2 wins, got %d", record.Player1Wins)
+	}
+	if record.Player2Wins != 1 {
+		t.Errorf("expected Bob to have 1 win, got %d", record.Player2Wins)
+	}
+	if record.Draws != 1 {
+		t.Errorf("expected 1 draw, got %d", record.Draws)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestHeadToHeadNoBattles(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	database := &Database{conn: db}
+
+	mock.ExpectQuery("SELECT player1_id, player2_id, winner FROM battles").
+		WillReturnRows(sqlmock.NewRows([]string{"player1_id", "player2_id", "winner"}))
+
+	record, err := database.HeadToHead(context.Background(), "Alice", "Bob")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if record.GamesPlayed != 0 || record.Player1Wins != 0 || record.Player2Wins != 0 {
+		t.Errorf("expected an empty record, got %+v", record)
+	}
+}
+
+func TestPlayerStats(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	database := &Database{conn: db}
+
+	// 3 public games: 2 wins, 1 loss
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\), .* FROM battles .* is_private = false").
+		WithArgs("Alice").
+		WillReturnRows(sqlmock.NewRows([]string{"games", "wins", "decided"}).AddRow(3, 2, 3))
+	// Alice led Amoonguss + Incineroar twice and Urshifu + Incineroar once
+	mock.ExpectQuery("WITH recent AS .* SELECT bp.battle_id, bp.species FROM battle_pokemon").
+		WithArgs("Alice", playerLeadBattles).
+		WillReturnRows(sqlmock.NewRows([]string{"battle_id", "species"}).
+			AddRow("b1", "Incineroar").
+			AddRow("b1", "Amoonguss").
+			AddRow("b2", "Amoonguss").
+			AddRow("b2", "Incineroar").
+			AddRow("b3", "Urshifu").
+			AddRow("b3", "Incineroar"))
+	mock.ExpectQuery("SELECT bp.species, COUNT\\(\\*\\) FROM battle_pokemon .*b.is_private = false").
+		WithArgs("Alice", playerTopPokemonLimit).
+		WillReturnRows(sqlmock.NewRows([]string{"species", "count"}).
+			AddRow("Amoonguss", 3).
+			AddRow("Incineroar", 2))
+
+	stats, err := database.PlayerStats(context.Background(), "Alice")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if stats.GamesPlayed != 3 || stats.Wins != 2 || stats.Losses != 1 || stats.Draws != 0 {
+		t.Errorf("expected 3 games, 2 wins, 1 loss, got %+v", stats)
+	}
+	if stats.WinRate < 0.66 || stats.WinRate > 0.67 {
+		t.Errorf("expected win rate 2/3, got %f", stats.WinRate)
+	}
+	if len(stats.TopPokemon) != 2 || stats.TopPokemon[0] != (SpeciesUsage{Species: "Amoonguss", Count: 3}) {
+		t.Errorf("expected Amoonguss to be most used, got %v", stats.TopPokemon)
+	}
+	if len(stats.FavoriteLead) != 2 || stats.FavoriteLead[0] != "Amoonguss" || stats.FavoriteLead[1] != "Incineroar" {
+		t.Errorf("expected Amoonguss + Incineroar as favorite lead, got %v", stats.FavoriteLead)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestPlayerStatsNoBattles(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	database := &Database{conn: db}
+
+	mock.ExpectQuery("SELECT COUNT").
+		WillReturnRows(sqlmock.NewRows([]string{"games", "wins", "decided"}).AddRow(0, 0, 0))
+	mock.ExpectQuery("WITH recent AS").
+		WillReturnRows(sqlmock.NewRows([]string{"battle_id", "species"}))
+	mock.ExpectQuery("SELECT bp.species").
+		WillReturnRows(sqlmock.NewRows([]string{"species", "count"}))
+
+	stats, err := database.PlayerStats(context.Background(), "Nobody")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if stats.GamesPlayed != 0 || stats.Wins != 0 || stats.WinRate != 0 {
+		t.Errorf("expected zeroed stats, got %+v", stats)
+	}
+	if stats.TopPokemon == nil || stats.FavoriteLead == nil {
+		t.Error("expected empty, non-nil slices")
+	}
+}
+
+func TestRecentBattlesForPlayer(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	database := &Database{conn: db}
+	now := time.Now()
+
+	columns := []string{"id", "format", "timestamp", "duration_sec", "winner", "player1_id", "player2_id", "is_private"}
+	mock.ExpectQuery("SELECT id, format, timestamp, duration_sec, winner, player1_id, player2_id, is_private FROM battles").
+		WithArgs("Alice", 2).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow("battle-3", "gen9vgc2025regh", now, 600, "player1", "Alice", "Bob", false).
+			AddRow("battle-2", "gen9vgc2025regh", now.Add(-time.Hour), 540, nil, "Carol", "Alice", false))
+
+	battles, err := database.RecentBattlesForPlayer(context.Background(), "Alice", 2)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(battles) != 2 {
+		t.Fatalf("expected 2 battles, got %d", len(battles))
+	}
+	if battles[0].ID != "battle-3" || battles[1].ID != "battle-2" {
+		t.Errorf("expected newest first, got %s then %s", battles[0].ID, battles[1].ID)
+	}
+	if battles[1].Winner != "" {
+		t.Errorf("expected a missing winner to read as empty, got %q", battles[1].Winner)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestRecentBattlesForPlayerLimits(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	database := &Database{conn: db}
+	columns := []string{"id", "format", "timestamp", "duration_sec", "winner", "player1_id", "player2_id", "is_private"}
+
+	mock.ExpectQuery("SELECT id, format").
+		WithArgs("Nobody", maxRecentBattles).
+		WillReturnRows(sqlmock.NewRows(columns))
+	mock.ExpectQuery("SELECT id, format").
+		WithArgs("Nobody", defaultRecentBattles).
+		WillReturnRows(sqlmock.NewRows(columns))
+
+	battles, err := database.RecentBattlesForPlayer(context.Background(), "Nobody", 10000)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if battles == nil || len(battles) != 0 {
+		t.Errorf("expected an empty, non-nil slice, got %v", battles)
+	}
+	if _, err := database.RecentBattlesForPlayer(context.Background(), "Nobody", 0); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestPlayerBattleLogs(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	database := &Database{conn: db}
+
+	mock.ExpectQuery("SELECT player1_id, battle_log FROM battles .* is_private = false .* LIMIT \\$2").
+		WithArgs("Alice", 50).
+		WillReturnRows(sqlmock.NewRows([]string{"player1_id", "battle_log"}).
+			AddRow("Alice", "log-1").
+			AddRow("Bob", "log-2"))
+
+	logs, err := database.PlayerBattleLogs(context.Background(), "Alice", 50)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	want := []PlayerBattleLog{{Slot: "player1", BattleLog: "log-1"}, {Slot: "player2", BattleLog: "log-2"}}
+	if len(logs) != len(want) || logs[0] != want[0] || logs[1] != want[1] {
+		t.Errorf("expected %+v, got %+v", want, logs)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
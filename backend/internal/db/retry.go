@@ -0,0 +1,44 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// retryBackoff is the wait before the first retry; it doubles each attempt.
+var retryBackoff = 10 * time.Millisecond
+
+// WithTxRetry runs fn in a transaction like WithTx, retrying up to maxRetries
+// more times when Postgres aborts it with a serialization failure or deadlock.
+// Those transactions rolled back cleanly, so fn must only touch the database
+// through tx. Any other error is returned immediately.
+func (db *Database) WithTxRetry(ctx context.Context, maxRetries int, fn func(*sql.Tx) error) error {
+	backoff := retryBackoff
+	for attempt := 0; ; attempt++ {
+		err := db.WithTx(ctx, fn)
+		if err == nil || attempt >= maxRetries || !isRetriableTxError(err) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// isRetriableTxError reports whether err is a serialization_failure (40001)
+// or deadlock_detected (40P01), wherever it surfaced in the transaction.
+func isRetriableTxError(err error) bool {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return false
+	}
+	return pqErr.Code == "40001" || pqErr.Code == "40P01"
+}
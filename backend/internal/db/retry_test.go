@@ -0,0 +1,111 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+)
+
+func TestWithTxRetry(t *testing.T) {
+	defer func(backoff time.Duration) { retryBackoff = backoff }(retryBackoff)
+	retryBackoff = 0
+	ctx := context.Background()
+
+	t.Run("retries a serialization failure", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("failed to create mock: %v", err)
+		}
+		defer func() { _ = db.Close() }()
+		database := &Database{conn: db}
+
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+
+		calls := 0
+		err = database.WithTxRetry(ctx, 3, func(tx *sql.Tx) error {
+			calls++
+			if calls == 1 {
+				return fmt.Errorf("failed to insert battle: %w", &pq.Error{Code: "40001"})
+			}
+			return nil
+		})
+
+		if err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+		if calls != 2 {
+			t.Errorf("expected 2 attempts, got %d", calls)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("unmet expectations: %v", err)
+		}
+	})
+
+	t.Run("returns other errors immediately", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("failed to create mock: %v", err)
+		}
+		defer func() { _ = db.Close() }()
+		database := &Database{conn: db}
+
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+
+		uniqueViolation := &pq.Error{Code: "23505"}
+		calls := 0
+		err = database.WithTxRetry(ctx, 3, func(tx *sql.Tx) error {
+			calls++
+			return uniqueViolation
+		})
+
+		if !errors.Is(err, uniqueViolation) {
+			t.Errorf("expected the unique violation, got %v", err)
+		}
+		if calls != 1 {
+			t.Errorf("expected 1 attempt, got %d", calls)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("unmet expectations: %v", err)
+		}
+	})
+
+	t.Run("gives up after maxRetries", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("failed to create mock: %v", err)
+		}
+		defer func() { _ = db.Close() }()
+		database := &Database{conn: db}
+
+		for i := 0; i < 3; i++ {
+			mock.ExpectBegin()
+			mock.ExpectRollback()
+		}
+
+		calls := 0
+		err = database.WithTxRetry(ctx, 2, func(tx *sql.Tx) error {
+			calls++
+			return &pq.Error{Code: "40P01"}
+		})
+
+		if !isRetriableTxError(err) {
+			t.Errorf("expected the deadlock error, got %v", err)
+		}
+		if calls != 3 {
+			t.Errorf("expected 3 attempts, got %d", calls)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("unmet expectations: %v", err)
+		}
+	})
+}
@@ -0,0 +1,39 @@
+package db
+
+import (
+	"strings"
+	"time"
+
+	"github.com/dtsong/vgccorner/backend/internal/observability"
+)
+
+// EnableSlowQueryLog logs Exec, Query and QueryRow calls that take longer
+// than threshold, with their parameterized SQL but never the argument
+// values. For Query the time is until rows are returned, not until they are
+// read. A threshold of zero or less leaves logging off, which costs nothing.
+func (db *Database) EnableSlowQueryLog(logger *observability.Logger, threshold time.Duration) {
+	if logger == nil || threshold <= 0 {
+		return
+	}
+	db.slowQueryLogger = logger
+	db.slowQueryThreshold = threshold
+}
+
+// queryStart returns the time to pass to logSlowQuery, or the zero time when
+// slow-query logging is off.
+func (db *Database) queryStart() time.Time {
+	if db.slowQueryThreshold <= 0 {
+		return time.Time{}
+	}
+	return time.Now()
+}
+
+// logSlowQuery logs query if it ran past the slow-query threshold since start.
+func (db *Database) logSlowQuery(start time.Time, query string) {
+	if start.IsZero() {
+		return
+	}
+	if elapsed := time.Since(start); elapsed >= db.slowQueryThreshold {
+		db.slowQueryLogger.Infof("slow query (%s): %s", elapsed.Round(time.Millisecond), strings.Join(strings.Fields(query), " "))
+	}
+}
@@ -0,0 +1,56 @@
+package db
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/dtsong/vgccorner/backend/internal/observability"
+)
+
+func TestSlowQueryLog(t *testing.T) {
+	conn, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	var buf bytes.Buffer
+	database := &Database{conn: conn}
+	database.EnableSlowQueryLog(&observability.Logger{Logger: log.New(&buf, "", 0)}, 20*time.Millisecond)
+
+	mock.ExpectQuery("SELECT id FROM battles").
+		WithArgs("secret-player").
+		WillDelayFor(50 * time.Millisecond).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("battle-1"))
+	mock.ExpectExec("UPDATE battles").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	rows, err := database.Query(context.Background(), "SELECT id\n\t\tFROM battles WHERE player1_id = $1", "secret-player")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_ = rows.Close()
+	if err := database.Exec(context.Background(), "UPDATE battles SET updated_at = NOW()"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	output := buf.String()
+	if !strings.Contains(output, "slow query") || !strings.Contains(output, "SELECT id FROM battles WHERE player1_id = $1") {
+		t.Errorf("expected a slow-query line with the parameterized SQL, got %q", output)
+	}
+	if strings.Contains(output, "secret-player") {
+		t.Errorf("expected argument values to stay out of the log, got %q", output)
+	}
+	if strings.Contains(output, "UPDATE") {
+		t.Errorf("expected the fast query not to be logged, got %q", output)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
@@ -1,6 +1,10 @@
 package db
 
-import "time"
+import (
+	"time"
+
+	"github.com/dtsong/vgccorner/backend/internal/analysis"
+)
 
 // Battle represents a stored battle record.
 type Battle struct {
@@ -15,10 +19,22 @@ type Battle struct {
 	IsPrivate   bool
 	Analysis    *BattleAnalysis
 	KeyMoments  []*KeyMoment
+	Teams       []*BattlePokemon // Revealed team members, both players
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
 }
 
+// BattlePokemon is a team member revealed in a battle.
+type BattlePokemon struct {
+	Player   string // "player1" or "player2"
+	Slot     int    // Position in the player's team preview
+	Species  string
+	Ability  string
+	Item     string
+	TeraType string
+	Lead     bool // On the field when the battle started
+}
+
 // BattleAnalysis stores computed statistics for a battle.
 type BattleAnalysis struct {
 	BattleID              string
@@ -53,4 +69,62 @@ type KeyMoment struct {
 type BattleFilter struct {
 	Format    string
 	IsPrivate *bool
+	Since     time.Time // Inclusive lower bound on timestamp; zero means unbounded
+	Until     time.Time // Exclusive upper bound on timestamp; zero means unbounded
+}
+
+// H2HRecord is the head-to-head record between two players. Player1 and
+// Player2 refer to the order the players were requested in, not battle slots.
+type H2HRecord struct {
+	Player1ID   string
+	Player2ID   string
+	GamesPlayed int
+	Player1Wins int
+	Player2Wins int
+	Draws       int // Draws and battles without a recorded winner
+}
+
+// PlayerStats aggregates a player's results across stored battles in either slot.
+type PlayerStats struct {
+	PlayerID     string
+	GamesPlayed  int
+	Wins         int
+	Losses       int
+	Draws        int            // Draws and battles without a recorded winner
+	WinRate      float64        // Wins / GamesPlayed; 0 with no games
+	TopPokemon   []SpeciesUsage // Most registered (team preview) first
+	FavoriteLead []string       // Most common lead, alphabetical; empty with no marked leads
+}
+
+// PlayerBattleLog is a stored battle log and the slot a player had in it.
+type PlayerBattleLog struct {
+	Slot      string // "player1" or "player2"
+	BattleLog string
+}
+
+// SpeciesUsage counts the battles a species was registered for, i.e. shown
+// at team preview.
+type SpeciesUsage struct {
+	Species string
+	Count   int
+}
+
+// TableStat is the size of one table, for operators.
+type TableStat struct {
+	Rows             int64 // Planner estimate (pg_class.reltuples), not an exact count
+	SizeBytes        int64 // Including indexes and TOAST
+	PermissionDenied bool  // The connection may not read this table; Rows and SizeBytes are 0
+}
+
+// StoredMetaStats is a meta stats aggregation saved by StoreMetaStats.
+type StoredMetaStats struct {
+	Stats      analysis.MetaStats
+	ComputedAt time.Time
+}
+
+// StoredTypeEffectiveness is a type effectiveness aggregation saved by
+// StoreTypeEffectiveness.
+type StoredTypeEffectiveness struct {
+	Stats      analysis.TypeMatchupStats
+	ComputedAt time.Time
 }
@@ -0,0 +1,89 @@
+package httpapi
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+)
+
+// TableStat is the size of one database table.
+type TableStat struct {
+	Rows             int64 `json:"rows"` // Approximate, from planner statistics
+	SizeBytes        int64 `json:"sizeBytes"`
+	PermissionDenied bool  `json:"permissionDenied,omitempty"`
+}
+
+// TableStatsResponse is the response for table statistics requests.
+type TableStatsResponse struct {
+	Status string               `json:"status"`
+	Data   map[string]TableStat `json:"data"`
+}
+
+// PoolStatsResponse is the state of the database connection pool.
+type PoolStatsResponse struct {
+	MaxOpenConnections int   `json:"maxOpenConnections"` // 0 means unlimited
+	OpenConnections    int   `json:"openConnections"`
+	InUse              int   `json:"inUse"`
+	Idle               int   `json:"idle"`
+	WaitCount          int64 `json:"waitCount"`      // Total waits for a free connection
+	WaitDurationMs     int64 `json:"waitDurationMs"` // Total time spent waiting
+	MaxIdleClosed      int64 `json:"maxIdleClosed"`
+	MaxLifetimeClosed  int64 `json:"maxLifetimeClosed"`
+}
+
+// requireAdmin only lets through requests bearing the admin token. Without a
+// configured token admin endpoints don't exist, so they answer 404.
+func (s *Server) requireAdmin(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.adminToken == "" {
+			http.NotFound(w, r)
+			return
+		}
+
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.adminToken)) != 1 {
+			writeJSONError(w, http.StatusForbidden, "FORBIDDEN", "Admin token required")
+			return
+		}
+		next(w, r)
+	}
+}
+
+// handleGetTableStats handles GET /api/admin/tables requests.
+func (s *Server) handleGetTableStats(w http.ResponseWriter, r *http.Request) {
+	stats, err := s.db.TableStats(r.Context())
+	if err != nil {
+		s.logger.Infof("Failed to read table stats: %v", err)
+		writeJSONError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Internal server error")
+		return
+	}
+
+	data := make(map[string]TableStat, len(stats))
+	for table, stat := range stats {
+		data[table] = TableStat{
+			Rows:             stat.Rows,
+			SizeBytes:        stat.SizeBytes,
+			PermissionDenied: stat.PermissionDenied,
+		}
+	}
+
+	s.writeJSON(w, http.StatusOK, TableStatsResponse{
+		Status: "success",
+		Data:   data,
+	})
+}
+
+// handleGetPoolStats handles GET /api/admin/pool requests.
+func (s *Server) handleGetPoolStats(w http.ResponseWriter, r *http.Request) {
+	stats := s.db.PoolStats()
+	s.writeJSON(w, http.StatusOK, PoolStatsResponse{
+		MaxOpenConnections: stats.MaxOpenConnections,
+		OpenConnections:    stats.OpenConnections,
+		InUse:              stats.InUse,
+		Idle:               stats.Idle,
+		WaitCount:          stats.WaitCount,
+		WaitDurationMs:     stats.WaitDuration.Milliseconds(),
+		MaxIdleClosed:      stats.MaxIdleClosed,
+		MaxLifetimeClosed:  stats.MaxLifetimeClosed,
+	})
+}
@@ -0,0 +1,113 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/dtsong/vgccorner/backend/internal/db"
+	"github.com/dtsong/vgccorner/backend/internal/observability"
+	"github.com/lib/pq"
+)
+
+func TestGetTableStats(t *testing.T) {
+	conn, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	mock.ExpectQuery("FROM pg_class").
+		WithArgs("battles").
+		WillReturnRows(sqlmock.NewRows([]string{"reltuples", "size"}).AddRow(7, 65536))
+	mock.ExpectQuery("FROM pg_class").
+		WithArgs("battle_analysis").
+		WillReturnError(&pq.Error{Code: "42501"})
+	mock.ExpectQuery("FROM pg_class").
+		WithArgs("key_moments").
+		WillReturnRows(sqlmock.NewRows([]string{"reltuples", "size"}).AddRow(21, 8192))
+
+	router := NewRouter(observability.NewLogger(), db.NewDatabaseFromConn(conn), WithAdminToken("s3cret"))
+	req := httptest.NewRequest("GET", "/api/v1/admin/tables", nil)
+	req.Header.Set("Authorization", "Bearer s3cret")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp TableStatsResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if got := resp.Data["battles"]; got.Rows != 7 || got.SizeBytes != 65536 {
+		t.Errorf("expected battles 7 rows/65536 bytes, got %+v", got)
+	}
+	if !resp.Data["battle_analysis"].PermissionDenied {
+		t.Errorf("expected battle_analysis to be marked permission denied, got %+v", resp.Data["battle_analysis"])
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestGetPoolStats(t *testing.T) {
+	conn, _, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	database := db.NewDatabaseFromConn(conn)
+	database.ConfigurePool(12, 0, 0)
+
+	router := NewRouter(observability.NewLogger(), database, WithAdminToken("s3cret"))
+	req := httptest.NewRequest("GET", "/api/v1/admin/pool", nil)
+	req.Header.Set("Authorization", "Bearer s3cret")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp PoolStatsResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.MaxOpenConnections != 12 || resp.InUse != 0 {
+		t.Errorf("expected an idle pool capped at 12, got %+v", resp)
+	}
+}
+
+func TestAdminEndpointsRequireToken(t *testing.T) {
+	tests := []struct {
+		name           string
+		adminToken     string
+		authorization  string
+		expectedStatus int
+	}{
+		{"disabled without a configured token", "", "Bearer s3cret", http.StatusNotFound},
+		{"missing token", "s3cret", "", http.StatusForbidden},
+		{"wrong token", "s3cret", "Bearer guess", http.StatusForbidden},
+		{"no database", "s3cret", "Bearer s3cret", http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := NewRouter(observability.NewLogger(), nil, WithAdminToken(tt.adminToken))
+			req := httptest.NewRequest("GET", "/api/v1/admin/tables", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+		})
+	}
+}
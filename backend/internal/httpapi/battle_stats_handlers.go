@@ -0,0 +1,74 @@
+package httpapi
+
+import (
+	"net/http"
+
+	"github.com/dtsong/vgccorner/backend/internal/db"
+	"github.com/go-chi/chi/v5"
+)
+
+// BattleStatsResponse is the stored analysis of a single battle.
+type BattleStatsResponse struct {
+	BattleID              string            `json:"battleId"`
+	TotalTurns            int               `json:"totalTurns"`
+	AvgDamagePerTurn      float64           `json:"avgDamagePerTurn"`
+	AvgHealPerTurn        float64           `json:"avgHealPerTurn"`
+	MovesUsed             int               `json:"movesUsed"`
+	Switches              int               `json:"switches"`
+	SuperEffectiveMoves   int               `json:"superEffectiveMoves"`
+	NotVeryEffectiveMoves int               `json:"notVeryEffectiveMoves"`
+	CriticalHits          int               `json:"criticalHits"`
+	Player1               SideStatsResponse `json:"player1"`
+	Player2               SideStatsResponse `json:"player2"`
+}
+
+// SideStatsResponse is one player's damage and healing totals.
+type SideStatsResponse struct {
+	DamageDealt int `json:"damageDealt"`
+	DamageTaken int `json:"damageTaken"`
+	HealingDone int `json:"healingDone"`
+}
+
+// handleGetBattleStats handles GET /api/battles/{id}/stats requests. It reads
+// the analysis computed when the battle was stored instead of re-parsing the
+// log, so it's much cheaper than fetching the battle.
+func (s *Server) handleGetBattleStats(w http.ResponseWriter, r *http.Request) {
+	stats, err := s.db.GetBattleAnalysis(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		s.logger.Infof("Failed to retrieve battle analysis: %v", err)
+		writeJSONError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Internal server error")
+		return
+	}
+
+	if stats == nil {
+		writeJSONError(w, http.StatusNotFound, "NOT_FOUND", "Battle not found")
+		return
+	}
+
+	s.writeJSON(w, http.StatusOK, convertStoredStats(stats))
+}
+
+// convertStoredStats converts a stored analysis row to its API format.
+func convertStoredStats(stats *db.BattleAnalysis) BattleStatsResponse {
+	return BattleStatsResponse{
+		BattleID:              stats.BattleID,
+		TotalTurns:            stats.TotalTurns,
+		AvgDamagePerTurn:      stats.AvgDamagePerTurn,
+		AvgHealPerTurn:        stats.AvgHealPerTurn,
+		MovesUsed:             stats.MovesUsedCount,
+		Switches:              stats.SwitchesCount,
+		SuperEffectiveMoves:   stats.SuperEffectiveMoves,
+		NotVeryEffectiveMoves: stats.NotVeryEffectiveMoves,
+		CriticalHits:          stats.CriticalHits,
+		Player1: SideStatsResponse{
+			DamageDealt: stats.Player1DamageDealt,
+			DamageTaken: stats.Player1DamageTaken,
+			HealingDone: stats.Player1HealingDone,
+		},
+		Player2: SideStatsResponse{
+			DamageDealt: stats.Player2DamageDealt,
+			DamageTaken: stats.Player2DamageTaken,
+			HealingDone: stats.Player2HealingDone,
+		},
+	}
+}
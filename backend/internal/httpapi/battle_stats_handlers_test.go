@@ -0,0 +1,116 @@
+package httpapi
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/dtsong/vgccorner/backend/internal/db"
+	"github.com/dtsong/vgccorner/backend/internal/observability"
+)
+
+func TestGetBattleStats(t *testing.T) {
+	conn, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	mock.ExpectQuery("SELECT EXISTS").
+		WithArgs("battle-1").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectQuery("SELECT battle_id, total_turns").
+		WithArgs("battle-1").
+		WillReturnRows(sqlmock.NewRows([]string{"battle_id", "total_turns", "avg_damage_per_turn", "avg_heal_per_turn", "moves_used_count", "switches_count", "super_effective_moves", "not_very_effective_moves", "critical_hits", "player1_damage_dealt", "player1_damage_taken", "player1_healing_done", "player2_damage_dealt", "player2_damage_taken", "player2_healing_done"}).
+			AddRow("battle-1", 4, 37.5, 6.25, 8, 2, 3, 1, 1, 150, 90, 25, 90, 150, 0))
+
+	router := NewRouter(observability.NewLogger(), db.NewDatabaseFromConn(conn))
+	req := httptest.NewRequest("GET", "/api/v1/battles/battle-1/stats", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp BattleStatsResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	want := BattleStatsResponse{
+		BattleID:              "battle-1",
+		TotalTurns:            4,
+		AvgDamagePerTurn:      37.5,
+		AvgHealPerTurn:        6.3, // Rounded to one decimal place
+		MovesUsed:             8,
+		Switches:              2,
+		SuperEffectiveMoves:   3,
+		NotVeryEffectiveMoves: 1,
+		CriticalHits:          1,
+		Player1:               SideStatsResponse{DamageDealt: 150, DamageTaken: 90, HealingDone: 25},
+		Player2:               SideStatsResponse{DamageDealt: 90, DamageTaken: 150},
+	}
+	if resp != want {
+		t.Errorf("expected %+v, got %+v", want, resp)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestGetBattleStatsWithoutAnalysis(t *testing.T) {
+	conn, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	mock.ExpectQuery("SELECT EXISTS").
+		WithArgs("battle-1").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectQuery("SELECT battle_id, total_turns").
+		WithArgs("battle-1").
+		WillReturnError(sql.ErrNoRows)
+
+	router := NewRouter(observability.NewLogger(), db.NewDatabaseFromConn(conn))
+	req := httptest.NewRequest("GET", "/api/v1/battles/battle-1/stats", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp BattleStatsResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp != (BattleStatsResponse{BattleID: "battle-1"}) {
+		t.Errorf("expected zeroed stats, got %+v", resp)
+	}
+}
+
+func TestGetBattleStatsNotFound(t *testing.T) {
+	conn, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	mock.ExpectQuery("SELECT EXISTS").
+		WithArgs("missing").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+
+	router := NewRouter(observability.NewLogger(), db.NewDatabaseFromConn(conn))
+	req := httptest.NewRequest("GET", "/api/v1/battles/missing/stats", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status 404, got %d", w.Code)
+	}
+}
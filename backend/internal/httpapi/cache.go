@@ -0,0 +1,146 @@
+package httpapi
+
+import (
+	"sync"
+	"time"
+)
+
+// ttlCache is a small in-memory cache whose entries expire after a fixed TTL.
+// A nil *ttlCache is valid and never caches anything.
+type ttlCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]ttlEntry
+	now     func() time.Time
+
+	stopJanitor chan struct{} // closed to stop the janitor, nil if none runs
+	janitorDone chan struct{} // closed once the janitor has exited
+}
+
+type ttlEntry struct {
+	value     interface{}
+	expiresAt time.Time
+}
+
+func newTTLCache(ttl time.Duration) *ttlCache {
+	return &ttlCache{
+		ttl:     ttl,
+		entries: make(map[string]ttlEntry),
+		now:     time.Now,
+	}
+}
+
+// Get returns the cached value for key if it is present and not expired.
+func (c *ttlCache) Get(key string) (interface{}, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if c.now().After(entry.expiresAt) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return entry.value, true
+}
+
+// Set stores value under key for the cache's TTL.
+func (c *ttlCache) Set(key string, value interface{}) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[key] = ttlEntry{value: value, expiresAt: c.now().Add(c.ttl)}
+}
+
+// Add stores value under key unless an unexpired entry is already there, and
+// reports whether it did. A nil cache stores nothing but reports true.
+func (c *ttlCache) Add(key string, value interface{}) bool {
+	if c == nil {
+		return true
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	if entry, ok := c.entries[key]; ok && !now.After(entry.expiresAt) {
+		return false
+	}
+	c.entries[key] = ttlEntry{value: value, expiresAt: now.Add(c.ttl)}
+	return true
+}
+
+// Delete drops key.
+func (c *ttlCache) Delete(key string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.entries, key)
+}
+
+// Clear drops every entry.
+func (c *ttlCache) Clear() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = make(map[string]ttlEntry)
+}
+
+// startJanitor sweeps expired entries every interval, so keys that are never
+// read again don't hold memory. Call stop to end it.
+func (c *ttlCache) startJanitor(interval time.Duration) {
+	if c == nil || c.stopJanitor != nil {
+		return
+	}
+	c.stopJanitor = make(chan struct{})
+	c.janitorDone = make(chan struct{})
+
+	go func() {
+		defer close(c.janitorDone)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-c.stopJanitor:
+				return
+			case <-ticker.C:
+				c.sweep()
+			}
+		}
+	}()
+}
+
+// sweep drops every expired entry.
+func (c *ttlCache) sweep() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	for key, entry := range c.entries {
+		if now.After(entry.expiresAt) {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// stop ends the janitor, if any, and waits for it to exit.
+func (c *ttlCache) stop() {
+	if c == nil || c.stopJanitor == nil {
+		return
+	}
+	close(c.stopJanitor)
+	<-c.janitorDone
+}
@@ -0,0 +1,40 @@
+package httpapi
+
+import (
+	"net/http"
+
+	"github.com/dtsong/vgccorner/backend/internal/analysis"
+)
+
+// CompareResponse holds two battles and how they differ.
+type CompareResponse struct {
+	A    *analysis.BattleSummary   `json:"a"`
+	B    *analysis.BattleSummary   `json:"b"`
+	Diff analysis.BattleComparison `json:"diff"`
+}
+
+// handleCompareBattles handles GET /api/battles/compare?a=ID1&b=ID2 requests.
+func (s *Server) handleCompareBattles(w http.ResponseWriter, r *http.Request) {
+	idA := r.URL.Query().Get("a")
+	idB := r.URL.Query().Get("b")
+
+	if idA == "" || idB == "" {
+		writeJSONError(w, http.StatusBadRequest, "INVALID_REQUEST", "a and b battle IDs are required")
+		return
+	}
+
+	a, ok := s.storedSummary(w, r, idA)
+	if !ok {
+		return
+	}
+	b, ok := s.storedSummary(w, r, idB)
+	if !ok {
+		return
+	}
+
+	s.writeJSON(w, http.StatusOK, CompareResponse{
+		A:    a,
+		B:    b,
+		Diff: analysis.CompareBattles(a, b),
+	})
+}
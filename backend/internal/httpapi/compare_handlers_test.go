@@ -0,0 +1,127 @@
+package httpapi
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/dtsong/vgccorner/backend/internal/db"
+	"github.com/dtsong/vgccorner/backend/internal/observability"
+)
+
+const compareSecondLog = `|player|p1|Player1|giovanni|1500
+|player|p2|Player3|cynthia|1450
+|poke|p1|Pikachu, L50, M|
+|poke|p2|Garchomp, L50, M|
+|start
+|switch|p1a: Pikachu|Pikachu, L50, M|100/100
+|switch|p2a: Garchomp|Garchomp, L50, M|100/100
+|turn|1
+|move|p2a: Garchomp|Earthquake|p1a: Pikachu
+|-supereffective|p1a: Pikachu
+|-damage|p1a: Pikachu|0 fnt
+|faint|p1a: Pikachu
+|win|Player3`
+
+// expectStoredBattle queues the queries GetBattle makes for one battle.
+func expectStoredBattle(mock sqlmock.Sqlmock, id, battleLog string) {
+	now := time.Now()
+	mock.ExpectQuery("SELECT id, format, timestamp").
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "format", "timestamp", "duration_sec", "winner", "player1_id", "player2_id", "battle_log", "is_private", "created_at", "updated_at"}).
+			AddRow(id, "[Gen 9] VGC 2025 Reg H (Bo3)", now, 0, "player2", "Player1", "Player2", battleLog, false, now, now))
+	mock.ExpectQuery("SELECT battle_id, total_turns").
+		WithArgs(id).
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery("SELECT turn_number, moment_type").
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"turn_number", "moment_type", "description", "significance"}))
+	mock.ExpectQuery("SELECT player_number, slot, species").
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"player_number", "slot", "species", "ability", "item", "tera_type", "lead"}))
+}
+
+func TestCompareBattles(t *testing.T) {
+	conn, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	expectStoredBattle(mock, "battle-a", sampleShowdownLog())
+	expectStoredBattle(mock, "battle-b", compareSecondLog)
+
+	router := NewRouter(observability.NewLogger(), db.NewDatabaseFromConn(conn))
+	req := httptest.NewRequest("GET", "/api/battles/compare?a=battle-a&b=battle-b", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp CompareResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.A == nil || resp.B == nil {
+		t.Fatal("expected both summaries in the response")
+	}
+
+	diff := resp.Diff
+	if diff.TurnsA != 4 || diff.TurnsB != 1 || diff.TurnDelta != -3 {
+		t.Errorf("expected turns 4 vs 1 (delta -3), got %d vs %d (delta %d)", diff.TurnsA, diff.TurnsB, diff.TurnDelta)
+	}
+	if diff.WinnerA != "player2" || diff.WinnerB != "player2" || !diff.SameWinner {
+		t.Errorf("expected player2 to win both, got %q and %q", diff.WinnerA, diff.WinnerB)
+	}
+	if len(diff.SharedPokemon) != 1 || diff.SharedPokemon[0] != "Pikachu" {
+		t.Errorf("expected Pikachu to be shared, got %v", diff.SharedPokemon)
+	}
+	if diff.DamageDealtB["player2"] <= 0 {
+		t.Errorf("expected player2 to have dealt damage in battle b, got %v", diff.DamageDealtB)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestCompareBattlesErrors(t *testing.T) {
+	conn, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	expectStoredBattle(mock, "battle-a", sampleShowdownLog())
+	mock.ExpectQuery("SELECT id, format, timestamp").
+		WithArgs("missing").
+		WillReturnError(sql.ErrNoRows)
+
+	router := NewRouter(observability.NewLogger(), db.NewDatabaseFromConn(conn))
+
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+	}{
+		{"missing b", "?a=battle-a", http.StatusBadRequest},
+		{"missing both", "", http.StatusBadRequest},
+		{"unknown b", "?a=battle-a&b=missing", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest("GET", "/api/battles/compare"+tt.query, nil))
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+		})
+	}
+}
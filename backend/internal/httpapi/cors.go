@@ -0,0 +1,54 @@
+package httpapi
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultCORSMaxAge is how long browsers may cache a preflight response.
+const defaultCORSMaxAge = 10 * time.Minute
+
+// CORS headers the API accepts from and exposes to browser clients.
+var (
+	corsAllowedMethods = strings.Join([]string{http.MethodGet, http.MethodPost, http.MethodOptions}, ", ")
+	corsAllowedHeaders = strings.Join([]string{"Accept", "Authorization", "Content-Type", idempotencyKeyHeader, requestIDHeader}, ", ")
+	corsExposedHeaders = strings.Join([]string{"Deprecation", "Link", "Retry-After", idempotencyReplayedHeader}, ", ")
+)
+
+// corsPolicy lets browsers on the allowed origins call the API.
+type corsPolicy struct {
+	origins map[string]bool // "*" allows any origin
+	maxAge  time.Duration
+}
+
+// middleware adds CORS headers for allowed origins and answers their
+// preflight requests itself with 204, so preflights never reach a route.
+// Every response varies by Origin, so a shared cache can't hand one origin's
+// CORS headers to another.
+func (p corsPolicy) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Origin")
+
+		origin := r.Header.Get("Origin")
+		if origin == "" || !(p.origins[origin] || p.origins["*"]) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+		if r.Method != http.MethodOptions || r.Header.Get("Access-Control-Request-Method") == "" {
+			w.Header().Set("Access-Control-Expose-Headers", corsExposedHeaders)
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Add("Vary", "Access-Control-Request-Method")
+		w.Header().Add("Vary", "Access-Control-Request-Headers")
+		w.Header().Set("Access-Control-Allow-Methods", corsAllowedMethods)
+		w.Header().Set("Access-Control-Allow-Headers", corsAllowedHeaders)
+		w.Header().Set("Access-Control-Max-Age", strconv.Itoa(int(p.maxAge/time.Second)))
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
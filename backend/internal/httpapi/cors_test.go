@@ -0,0 +1,81 @@
+package httpapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dtsong/vgccorner/backend/internal/observability"
+)
+
+func corsRequest(t *testing.T, router http.Handler, method, origin string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, "/api/v1/showdown/analyze", nil)
+	req.Header.Set("Origin", origin)
+	if method == http.MethodOptions {
+		req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+		req.Header.Set("Access-Control-Request-Headers", "content-type")
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestCORSPreflightIsCacheable(t *testing.T) {
+	router := NewRouter(observability.NewLogger(), nil, WithCORS([]string{"https://vgccorner.example"}, 15*time.Minute))
+	w := corsRequest(t, router, http.MethodOptions, "https://vgccorner.example")
+
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("expected status 204, got %d", w.Code)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "https://vgccorner.example" {
+		t.Errorf("expected the origin to be allowed, got %q", got)
+	}
+	if got := w.Header().Get("Access-Control-Max-Age"); got != "900" {
+		t.Errorf("expected Access-Control-Max-Age 900, got %q", got)
+	}
+	if got := w.Header().Values("Vary"); len(got) == 0 || got[0] != "Origin" {
+		t.Errorf("expected Vary: Origin, got %q", got)
+	}
+	if w.Header().Get("Access-Control-Allow-Methods") == "" || w.Header().Get("Access-Control-Allow-Headers") == "" {
+		t.Error("expected allowed methods and headers on the preflight")
+	}
+}
+
+func TestCORSDefaultMaxAge(t *testing.T) {
+	router := NewRouter(observability.NewLogger(), nil, WithCORS([]string{"*"}, 0))
+	w := corsRequest(t, router, http.MethodOptions, "https://anywhere.example")
+
+	if got := w.Header().Get("Access-Control-Max-Age"); got != "600" {
+		t.Errorf("expected the default Access-Control-Max-Age of 600, got %q", got)
+	}
+}
+
+func TestCORSDisallowedOrigin(t *testing.T) {
+	router := NewRouter(observability.NewLogger(), nil, WithCORS([]string{"https://vgccorner.example"}, 0))
+	w := corsRequest(t, router, http.MethodOptions, "https://evil.example")
+
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Errorf("expected no Access-Control-Allow-Origin, got %q", got)
+	}
+	if got := w.Header().Get("Vary"); got != "Origin" {
+		t.Errorf("expected Vary: Origin even when the origin is refused, got %q", got)
+	}
+}
+
+func TestCORSSimpleRequestReachesHandler(t *testing.T) {
+	router := NewRouter(observability.NewLogger(), nil, WithCORS([]string{"https://vgccorner.example"}, 0))
+	w := corsRequest(t, router, http.MethodPost, "https://vgccorner.example")
+
+	// The empty body fails validation, but the response still carries CORS headers
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected the handler's 400, got %d", w.Code)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "https://vgccorner.example" {
+		t.Errorf("expected the origin to be allowed, got %q", got)
+	}
+	if w.Header().Get("Access-Control-Max-Age") != "" {
+		t.Error("expected Access-Control-Max-Age only on preflights")
+	}
+}
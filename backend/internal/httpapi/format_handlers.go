@@ -0,0 +1,22 @@
+package httpapi
+
+import (
+	"net/http"
+
+	"github.com/dtsong/vgccorner/backend/internal/analysis"
+)
+
+// FormatsResponse is the response for GET /api/formats.
+type FormatsResponse struct {
+	Status string                `json:"status"`
+	Data   []analysis.FormatInfo `json:"data"`
+}
+
+// handleListFormats handles GET /api/formats requests, listing the formats
+// with dedicated ruleset data.
+func (s *Server) handleListFormats(w http.ResponseWriter, r *http.Request) {
+	s.writeJSON(w, http.StatusOK, FormatsResponse{
+		Status: "success",
+		Data:   analysis.SupportedFormats(),
+	})
+}
@@ -0,0 +1,37 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dtsong/vgccorner/backend/internal/observability"
+)
+
+func TestListFormats(t *testing.T) {
+	server := &Server{logger: observability.NewLogger()}
+
+	req := httptest.NewRequest("GET", "/api/formats", nil)
+	w := httptest.NewRecorder()
+	server.handleListFormats(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+
+	var resp FormatsResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	for _, f := range resp.Data {
+		if f.Name == "[Gen 9] VGC 2025 Reg H" {
+			if !f.FullMechanics {
+				t.Error("expected Reg H to have full mechanic support")
+			}
+			return
+		}
+	}
+	t.Errorf("expected Reg H in formats, got %+v", resp.Data)
+}
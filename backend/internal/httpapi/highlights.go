@@ -0,0 +1,149 @@
+package httpapi
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/dtsong/vgccorner/backend/internal/analysis"
+)
+
+const (
+	// viewFull returns the whole BattleSummary; it's the default.
+	viewFull = "full"
+	// viewHighlights drops turns, keeping the header, key moments, and MVP.
+	viewHighlights = "highlights"
+)
+
+// HighlightsResponse is an AnalyzeResponse trimmed for highlight feeds.
+type HighlightsResponse struct {
+	Status   string            `json:"status"`
+	BattleID string            `json:"battleId,omitempty"`
+	Data     *BattleHighlights `json:"data,omitempty"`
+	Metadata *ResponseMetadata `json:"metadata,omitempty"`
+}
+
+// BattleHighlights is the header of a BattleSummary plus its key moments,
+// without the turn-by-turn data.
+type BattleHighlights struct {
+	ID         string               `json:"id"`
+	Format     string               `json:"format"`
+	Timestamp  time.Time            `json:"timestamp"`
+	Duration   int                  `json:"duration"`
+	Player1    HighlightPlayer      `json:"player1"`
+	Player2    HighlightPlayer      `json:"player2"`
+	Winner     string               `json:"winner"`
+	Draw       bool                 `json:"draw"`
+	ScoreLine  string               `json:"scoreLine"`
+	TotalTurns int                  `json:"totalTurns"`
+	KeyMoments []analysis.KeyMoment `json:"keyMoments"`
+	MVP        *HighlightMVP        `json:"mvp,omitempty"` // Absent for draws and when the winner scored no KOs
+}
+
+// HighlightPlayer is the part of a player shown in highlights.
+type HighlightPlayer struct {
+	Name          string   `json:"name"`
+	Rating        int      `json:"rating"`
+	TeamArchetype string   `json:"teamArchetype"`
+	Leads         []string `json:"leads"`
+}
+
+// HighlightMVP is the winning side's top scorer.
+type HighlightMVP struct {
+	Species string `json:"species"`
+	KOs     int    `json:"kos"`
+}
+
+// requestedView returns the ?view= query parameter, writing a 400 and
+// returning false when it isn't a known view.
+func requestedView(w http.ResponseWriter, r *http.Request) (string, bool) {
+	view := r.URL.Query().Get("view")
+	switch view {
+	case "", viewFull:
+		return viewFull, true
+	case viewHighlights:
+		return view, true
+	}
+	writeValidationErrors(w, []FieldError{{Field: "view", Message: "must be one of: full, highlights"}})
+	return "", false
+}
+
+// summaryFormat is how a summary response was asked for: the ?view= and
+// ?naming= query parameters and the encoding negotiated through Accept.
+type summaryFormat struct {
+	view      string
+	mediaType string
+	naming    *keyMapping // nil for camelCase
+}
+
+// requestedSummaryFormat reads the summary format of a request, writing the
+// error response and returning false when any part of it is invalid.
+func (s *Server) requestedSummaryFormat(w http.ResponseWriter, r *http.Request) (summaryFormat, bool) {
+	view, ok := requestedView(w, r)
+	if !ok {
+		return summaryFormat{}, false
+	}
+	naming, ok := s.requestedNaming(w, r)
+	if !ok {
+		return summaryFormat{}, false
+	}
+	mediaType, ok := negotiateEncoding(w, r)
+	if !ok {
+		return summaryFormat{}, false
+	}
+	return summaryFormat{view: view, mediaType: mediaType, naming: naming}, true
+}
+
+// encodeSummary writes resp in the requested format. The summary itself is
+// left untouched, since it may be shared through the summary cache.
+func (s *Server) encodeSummary(w http.ResponseWriter, format summaryFormat, resp AnalyzeResponse) error {
+	var v interface{} = resp
+	switch {
+	case resp.Data == nil:
+	case format.view != viewHighlights:
+		resp.Data = s.limitStatsMaps(resp.Data)
+		v = resp
+	default:
+		v = HighlightsResponse{
+			Status:   resp.Status,
+			BattleID: resp.BattleID,
+			Data:     newBattleHighlights(resp.Data),
+			Metadata: resp.Metadata,
+		}
+	}
+
+	doc, err := format.naming.apply(v)
+	if err != nil {
+		return err
+	}
+	return s.encodeAs(w, format.mediaType, doc)
+}
+
+// newBattleHighlights builds the highlights view of a summary.
+func newBattleHighlights(summary *analysis.BattleSummary) *BattleHighlights {
+	highlights := &BattleHighlights{
+		ID:         summary.ID,
+		Format:     summary.Format,
+		Timestamp:  summary.Timestamp,
+		Duration:   summary.Duration,
+		Player1:    newHighlightPlayer(summary.Player1),
+		Player2:    newHighlightPlayer(summary.Player2),
+		Winner:     summary.Winner,
+		Draw:       summary.Draw,
+		ScoreLine:  summary.ScoreLine,
+		TotalTurns: summary.Stats.TotalTurns,
+		KeyMoments: summary.KeyMoments,
+	}
+	if species, kos, ok := summary.MVP(); ok {
+		highlights.MVP = &HighlightMVP{Species: species, KOs: kos}
+	}
+	return highlights
+}
+
+func newHighlightPlayer(player analysis.Player) HighlightPlayer {
+	return HighlightPlayer{
+		Name:          player.Name,
+		Rating:        player.Rating,
+		TeamArchetype: player.TeamArchetype,
+		Leads:         player.Leads,
+	}
+}
@@ -0,0 +1,60 @@
+package httpapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dtsong/vgccorner/backend/internal/observability"
+)
+
+func TestAnalyzeShowdownHighlightsView(t *testing.T) {
+	router := NewRouter(observability.NewLogger(), nil)
+	analyze := func(query string) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(AnalyzeShowdownRequest{
+			AnalysisType: "rawLog",
+			RawLog:       sampleShowdownLog(),
+		})
+		req := httptest.NewRequest("POST", "/api/v1/showdown/analyze"+query, bytes.NewReader(body))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := analyze("?view=highlights")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp struct {
+		Data map[string]json.RawMessage `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if _, ok := resp.Data["turns"]; ok {
+		t.Error("expected highlights to omit turns")
+	}
+	var moments []json.RawMessage
+	if err := json.Unmarshal(resp.Data["keyMoments"], &moments); err != nil || len(moments) == 0 {
+		t.Errorf("expected key moments in highlights, got %s", resp.Data["keyMoments"])
+	}
+	if _, ok := resp.Data["mvp"]; !ok {
+		t.Error("expected the MVP in highlights")
+	}
+
+	full := analyze("")
+	var fullResp AnalyzeResponse
+	if err := json.NewDecoder(full.Body).Decode(&fullResp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(fullResp.Data.Turns) == 0 {
+		t.Error("expected the default view to include turns")
+	}
+
+	if bad := analyze("?view=compact"); bad.Code != http.StatusBadRequest {
+		t.Errorf("expected an unknown view to return 400, got %d", bad.Code)
+	}
+}
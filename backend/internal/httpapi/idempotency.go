@@ -0,0 +1,79 @@
+package httpapi
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/dtsong/vgccorner/backend/internal/analysis"
+)
+
+const (
+	// idempotencyKeyHeader lets clients retry a storing analyze request
+	// without storing the battle twice.
+	idempotencyKeyHeader = "Idempotency-Key"
+	// idempotencyReplayedHeader marks a response answered from an earlier
+	// request with the same key.
+	idempotencyReplayedHeader = "Idempotent-Replayed"
+	// idempotencyKeyTTL is how long a key is remembered after its first use.
+	idempotencyKeyTTL = 24 * time.Hour
+	// idempotencySweepInterval is how often expired keys are dropped.
+	idempotencySweepInterval = time.Hour
+)
+
+var (
+	// errIdempotencyKeyReused is returned when a key comes back with a
+	// different battle log than the one it was first used for.
+	errIdempotencyKeyReused = errors.New("idempotency key reused with a different battle log")
+	// errIdempotencyKeyInFlight is returned when a retry arrives while the
+	// first request with its key is still storing.
+	errIdempotencyKeyInFlight = errors.New("request with this idempotency key is still in progress")
+)
+
+// idempotentStore is what a key remembers: the battle it stored and a hash of
+// the log, so a reused key can't return another battle's ID. battleID is
+// empty while the first request is still storing.
+type idempotentStore struct {
+	battleID string
+	logHash  string
+}
+
+// storeOnce stores the battle unless an earlier request with the same
+// Idempotency-Key already did, in which case it returns that battle ID and
+// replayed is true. An empty key always stores.
+func (s *Server) storeOnce(ctx context.Context, key string, summary *analysis.BattleSummary, battleLog string, isPrivate bool) (battleID string, replayed bool, err error) {
+	if key == "" {
+		battleID, err = s.storeAnalyzedBattle(ctx, summary, battleLog, isPrivate)
+		return battleID, false, err
+	}
+
+	sum := sha256.Sum256([]byte(battleLog))
+	logHash := hex.EncodeToString(sum[:])
+
+	// Reserve the key before storing so a concurrent retry can't store too.
+	// If the entry blocking the reservation is gone by the time it's read,
+	// because it expired or its store failed, try to reserve it again.
+	for !s.idempotencyKeys.Add(key, idempotentStore{logHash: logHash}) {
+		if cached, ok := s.idempotencyKeys.Get(key); ok {
+			prior := cached.(idempotentStore)
+			switch {
+			case prior.logHash != logHash:
+				return "", false, errIdempotencyKeyReused
+			case prior.battleID == "":
+				return "", false, errIdempotencyKeyInFlight
+			}
+			return prior.battleID, true, nil
+		}
+	}
+
+	battleID, err = s.storeAnalyzedBattle(ctx, summary, battleLog, isPrivate)
+	if err != nil {
+		// Let the client retry the store with the same key
+		s.idempotencyKeys.Delete(key)
+		return "", false, err
+	}
+	s.idempotencyKeys.Set(key, idempotentStore{battleID: battleID, logHash: logHash})
+	return battleID, false, nil
+}
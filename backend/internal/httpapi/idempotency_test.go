@@ -0,0 +1,138 @@
+package httpapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dtsong/vgccorner/backend/internal/analysis"
+	"github.com/dtsong/vgccorner/backend/internal/db"
+	"github.com/dtsong/vgccorner/backend/internal/observability"
+)
+
+func TestAnalyzeShowdownIdempotencyKey(t *testing.T) {
+	store := &fakeBattleStore{returnsID: "stored-123"}
+	server := &Server{
+		logger:          observability.NewLogger(),
+		store:           store,
+		idempotencyKeys: newTTLCache(idempotencyKeyTTL),
+	}
+
+	analyze := func(key, battleLog string) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(AnalyzeShowdownRequest{
+			AnalysisType: "rawLog",
+			RawLog:       battleLog,
+			Store:        true,
+		})
+		req := httptest.NewRequest("POST", "/api/showdown/analyze", bytes.NewReader(body))
+		req.Header.Set(idempotencyKeyHeader, key)
+		w := httptest.NewRecorder()
+		server.handleAnalyzeShowdown(w, req)
+		return w
+	}
+
+	for attempt := 1; attempt <= 2; attempt++ {
+		w := analyze("retry-1", sampleShowdownLog())
+		if w.Code != http.StatusOK {
+			t.Fatalf("attempt %d: expected status 200, got %d: %s", attempt, w.Code, w.Body.String())
+		}
+		var resp AnalyzeResponse
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatalf("attempt %d: failed to decode response: %v", attempt, err)
+		}
+		if resp.BattleID != "stored-123" {
+			t.Errorf("attempt %d: expected battleId stored-123, got %q", attempt, resp.BattleID)
+		}
+		if replayed := w.Header().Get(idempotencyReplayedHeader) == "true"; replayed != (attempt == 2) {
+			t.Errorf("attempt %d: expected replayed=%v, got %v", attempt, attempt == 2, replayed)
+		}
+	}
+	if len(store.battles) != 1 {
+		t.Errorf("expected StoreBattle to be called once, got %d", len(store.battles))
+	}
+
+	// The same key with another log is a client bug, not a retry
+	w := analyze("retry-1", sampleShowdownLog()+"\n|")
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("expected status 422 for a reused key, got %d", w.Code)
+	}
+
+	// Without a key every request stores
+	analyze("", sampleShowdownLog())
+	if len(store.battles) != 2 {
+		t.Errorf("expected a keyless request to store again, got %d stores", len(store.battles))
+	}
+}
+
+func TestStoreOnceReservesExpiredKey(t *testing.T) {
+	keys := newTTLCache(idempotencyKeyTTL)
+	store := &reservationCheckingStore{fakeBattleStore: &fakeBattleStore{returnsID: "stored-123"}, keys: keys, key: "retry-1"}
+	server := &Server{
+		logger:          observability.NewLogger(),
+		store:           store,
+		idempotencyKeys: keys,
+	}
+	keys.Set("retry-1", idempotentStore{logHash: "other"})
+
+	// The entry is live when Add checks it but has expired by the Get, so the
+	// key must be reserved again rather than stored without a reservation
+	start := time.Now()
+	calls := 0
+	keys.now = func() time.Time {
+		calls++
+		if calls == 1 {
+			return start
+		}
+		return start.Add(2 * idempotencyKeyTTL)
+	}
+
+	summary, err := analysis.ParseEnhancedShowdownLog(sampleShowdownLog())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	battleID, replayed, err := server.storeOnce(context.Background(), "retry-1", summary, sampleShowdownLog(), false)
+	if err != nil || replayed || battleID != "stored-123" {
+		t.Fatalf("expected a fresh store, got %q, %v, %v", battleID, replayed, err)
+	}
+	if !store.reserved {
+		t.Error("expected the key to be reserved while the battle was stored")
+	}
+	if cached, ok := keys.Get("retry-1"); !ok || cached.(idempotentStore).battleID != "stored-123" {
+		t.Errorf("expected the key to record the stored battle, got %+v", cached)
+	}
+}
+
+// reservationCheckingStore records whether key was reserved when a battle
+// was stored.
+type reservationCheckingStore struct {
+	*fakeBattleStore
+	keys     *ttlCache
+	key      string
+	reserved bool
+}
+
+func (s *reservationCheckingStore) StoreBattle(ctx context.Context, battle *db.Battle) (string, error) {
+	_, s.reserved = s.keys.Get(s.key)
+	return s.fakeBattleStore.StoreBattle(ctx, battle)
+}
+
+func TestTTLCacheAdd(t *testing.T) {
+	cache := newTTLCache(idempotencyKeyTTL)
+	if !cache.Add("key", 1) {
+		t.Fatal("expected Add to store a new key")
+	}
+	if cache.Add("key", 2) {
+		t.Error("expected Add to keep an existing key")
+	}
+	if v, _ := cache.Get("key"); v != 1 {
+		t.Errorf("expected the first value to remain, got %v", v)
+	}
+	cache.Delete("key")
+	if !cache.Add("key", 3) {
+		t.Error("expected Add to store a deleted key")
+	}
+}
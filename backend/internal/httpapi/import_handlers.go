@@ -0,0 +1,131 @@
+package httpapi
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"runtime/debug"
+	"strings"
+	"sync"
+)
+
+const (
+	// maxImportBytes caps the total size of an import request body.
+	maxImportBytes = 64 << 20
+	// importConcurrency bounds how many lines of one import parse and store at
+	// once. An import holds a single analysis limiter slot for its whole run,
+	// so it can use up to importConcurrency parses' worth of CPU on one slot;
+	// this is intended, since per-line slots would let one large import starve
+	// every other analysis request.
+	importConcurrency = 4
+)
+
+// ImportLine is one line of a JSON Lines import body.
+type ImportLine struct {
+	BattleLog string `json:"battleLog"`
+	IsPrivate bool   `json:"isPrivate"`
+}
+
+// ImportResult reports the outcome of one import line. Results stream back in
+// completion order, so Line (1-based) ties each one to its input.
+type ImportResult struct {
+	Line  int    `json:"line"`
+	ID    string `json:"id,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// handleImportShowdown handles POST /api/showdown/import requests. The body is
+// JSON Lines of ImportLine; each line is parsed and stored independently and
+// a JSON Lines stream of ImportResult is written back as lines finish. A body
+// over maxImportBytes ends the stream with a result for line 0. A line whose
+// log panics the parser gets an error result rather than taking down the server.
+func (s *Server) handleImportShowdown(w http.ResponseWriter, r *http.Request) {
+	if s.store == nil {
+		writeJSONError(w, http.StatusServiceUnavailable, "SERVICE_UNAVAILABLE", "Database not configured; cannot import battles")
+		return
+	}
+
+	// Results are written while the body is still being read
+	rc := http.NewResponseController(w)
+	_ = rc.EnableFullDuplex()
+
+	body := http.MaxBytesReader(w, r.Body, maxImportBytes)
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxImportBytes)
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+
+	results := make(chan ImportResult)
+	go func() {
+		var wg sync.WaitGroup
+		sem := make(chan struct{}, importConcurrency)
+
+		lineNumber := 0
+		for scanner.Scan() {
+			lineNumber++
+			raw := strings.TrimSpace(scanner.Text())
+			if raw == "" {
+				continue
+			}
+
+			sem <- struct{}{}
+			wg.Add(1)
+			go func(line int, raw string) {
+				defer wg.Done()
+				defer func() { <-sem }()
+				// recoverPanics only covers the handler's own goroutine
+				defer func() {
+					if rec := recover(); rec != nil {
+						s.logger.Errorf("panic importing line %d (request %s): %v\n%s", line, requestID(r), rec, debug.Stack())
+						results <- ImportResult{Line: line, Error: "failed to parse battle log"}
+					}
+				}()
+				results <- s.importLine(r.Context(), line, raw)
+			}(lineNumber, raw)
+		}
+		if err := scanner.Err(); err != nil {
+			message := "failed to read request body"
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) || errors.Is(err, bufio.ErrTooLong) {
+				message = "request body too large"
+			}
+			results <- ImportResult{Error: message}
+		}
+
+		wg.Wait()
+		close(results)
+	}()
+
+	encoder := json.NewEncoder(w)
+	for result := range results {
+		_ = encoder.Encode(result)
+		_ = rc.Flush()
+	}
+}
+
+// importLine parses and stores a single import line.
+func (s *Server) importLine(ctx context.Context, line int, raw string) ImportResult {
+	var req ImportLine
+	if err := json.Unmarshal([]byte(raw), &req); err != nil {
+		return ImportResult{Line: line, Error: "invalid JSON: " + err.Error()}
+	}
+	if req.BattleLog == "" {
+		return ImportResult{Line: line, Error: "battleLog is required"}
+	}
+
+	summary, err := s.summaryCache.Parse(req.BattleLog)
+	if err != nil {
+		return ImportResult{Line: line, Error: "failed to parse battle log: " + err.Error()}
+	}
+
+	battleID, err := s.storeAnalyzedBattle(ctx, summary, req.BattleLog, req.IsPrivate)
+	if err != nil {
+		s.logger.Infof("Failed to store imported battle (line %d): %v", line, err)
+		return ImportResult{Line: line, Error: "failed to store battle"}
+	}
+
+	return ImportResult{Line: line, ID: battleID}
+}
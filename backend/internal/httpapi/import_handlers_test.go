@@ -0,0 +1,95 @@
+package httpapi
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dtsong/vgccorner/backend/internal/db"
+	"github.com/dtsong/vgccorner/backend/internal/observability"
+)
+
+func TestImportShowdown(t *testing.T) {
+	store := &fakeBattleStore{returnsID: "imported-1"}
+	server := &Server{logger: observability.NewLogger(), store: store}
+
+	good, _ := json.Marshal(ImportLine{BattleLog: sampleShowdownLog()})
+	body := string(good) + "\n" + `{"battleLog": ` + "\n"
+
+	req := httptest.NewRequest("POST", "/api/showdown/import", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	server.handleImportShowdown(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "application/x-ndjson" {
+		t.Errorf("expected application/x-ndjson, got %q", ct)
+	}
+
+	results := map[int]ImportResult{}
+	scanner := bufio.NewScanner(w.Body)
+	for scanner.Scan() {
+		var result ImportResult
+		if err := json.Unmarshal(scanner.Bytes(), &result); err != nil {
+			t.Fatalf("failed to decode result line %q: %v", scanner.Text(), err)
+		}
+		results[result.Line] = result
+	}
+
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d: %v", len(results), results)
+	}
+	if results[1].ID != "imported-1" || results[1].Error != "" {
+		t.Errorf("expected line 1 to be stored as imported-1, got %+v", results[1])
+	}
+	if results[2].ID != "" || !strings.Contains(results[2].Error, "invalid JSON") {
+		t.Errorf("expected line 2 to fail as invalid JSON, got %+v", results[2])
+	}
+	if len(store.battles) != 1 {
+		t.Errorf("expected 1 stored battle, got %d", len(store.battles))
+	}
+}
+
+// panickingBattleStore panics on store, standing in for a log that crashes
+// the parser.
+type panickingBattleStore struct {
+	fakeBattleStore
+}
+
+func (p *panickingBattleStore) StoreBattle(ctx context.Context, battle *db.Battle) (string, error) {
+	panic("parser exploded")
+}
+
+func TestImportShowdownRecoversPanics(t *testing.T) {
+	server := &Server{logger: observability.NewLogger(), store: &panickingBattleStore{}}
+
+	line, _ := json.Marshal(ImportLine{BattleLog: sampleShowdownLog()})
+	req := httptest.NewRequest("POST", "/api/showdown/import", strings.NewReader(string(line)+"\n"))
+	w := httptest.NewRecorder()
+	server.handleImportShowdown(w, req)
+
+	var result ImportResult
+	if err := json.NewDecoder(w.Body).Decode(&result); err != nil {
+		t.Fatalf("failed to decode result: %v", err)
+	}
+	if result.Line != 1 || result.Error != "failed to parse battle log" {
+		t.Errorf("expected line 1 to fail with a parse error, got %+v", result)
+	}
+}
+
+func TestImportShowdownWithoutDatabase(t *testing.T) {
+	server := &Server{logger: observability.NewLogger()}
+
+	req := httptest.NewRequest("POST", "/api/showdown/import", strings.NewReader("{}\n"))
+	w := httptest.NewRecorder()
+	server.handleImportShowdown(w, req)
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected status 503, got %d", w.Code)
+	}
+}
@@ -0,0 +1,77 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// defaultJSONFloatPlaces is how many decimal places floats keep in
+// responses. HP and damage values are percents, so one place is plenty.
+const defaultJSONFloatPlaces = 1
+
+// encodeJSON writes v as a JSON response body with floats rounded to the
+// server's precision, so 34.99999999 is sent as 35. Values are only rounded
+// on the way out; summaries keep full precision.
+func (s *Server) encodeJSON(w io.Writer, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if s.floatPlaces >= 0 {
+		data = roundJSONFloats(data, s.floatPlaces)
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// roundJSONFloats rounds every non-integer number in a JSON document to
+// places decimal places. Integers and strings are left untouched.
+func roundJSONFloats(data []byte, places int) []byte {
+	out := make([]byte, 0, len(data))
+	inString := false
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		switch {
+		case inString:
+			out = append(out, c)
+			if c == '\\' && i+1 < len(data) {
+				i++
+				out = append(out, data[i])
+			} else if c == '"' {
+				inString = false
+			}
+		case c == '"':
+			inString = true
+			out = append(out, c)
+		case c == '-' || (c >= '0' && c <= '9'):
+			end := i
+			for end < len(data) && strings.IndexByte("+-.eE0123456789", data[end]) >= 0 {
+				end++
+			}
+			out = appendRounded(out, data[i:end], places)
+			i = end - 1
+		default:
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+func appendRounded(out, number []byte, places int) []byte {
+	if !strings.ContainsAny(string(number), ".eE") {
+		return append(out, number...)
+	}
+	f, err := strconv.ParseFloat(string(number), 64)
+	if err != nil {
+		return append(out, number...)
+	}
+	scale := math.Pow10(places)
+	rounded := math.Round(f*scale) / scale
+	if rounded == 0 {
+		rounded = 0 // Drop the sign of -0
+	}
+	return strconv.AppendFloat(out, rounded, 'f', -1, 64)
+}
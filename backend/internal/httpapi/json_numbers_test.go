@@ -0,0 +1,51 @@
+package httpapi
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/dtsong/vgccorner/backend/internal/analysis"
+)
+
+func TestEncodeJSONRoundsPercents(t *testing.T) {
+	server := &Server{floatPlaces: defaultJSONFloatPlaces}
+	stats := analysis.BattleStats{
+		PassiveDamage:    map[string]float64{"player1": 12.500000001},
+		AvgDamagePerTurn: 34.96666666666667,
+		TotalTurns:       3,
+	}
+
+	var buf bytes.Buffer
+	if err := server.encodeJSON(&buf, stats); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	body := buf.String()
+
+	for _, want := range []string{`"passiveDamage":{"player1":12.5}`, `"avgDamagePerTurn":35`, `"totalTurns":3`} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected %s in %s", want, body)
+		}
+	}
+}
+
+func TestRoundJSONFloats(t *testing.T) {
+	tests := []struct {
+		input    string
+		places   int
+		expected string
+	}{
+		{`{"hp":0.3500000001}`, 1, `{"hp":0.4}`},
+		{`{"hp":33.333333}`, 0, `{"hp":33}`},
+		{`{"hp":-0.04}`, 1, `{"hp":0}`},
+		{`{"hp":1.5e-7,"n":[1,2.25]}`, 1, `{"hp":0,"n":[1,2.3]}`},
+		{`{"turns":12}`, 1, `{"turns":12}`},
+		{`{"note":"12.3456 \"34.5678\""}`, 1, `{"note":"12.3456 \"34.5678\""}`},
+	}
+
+	for _, tt := range tests {
+		if got := string(roundJSONFloats([]byte(tt.input), tt.places)); got != tt.expected {
+			t.Errorf("roundJSONFloats(%s, %d): expected %s, got %s", tt.input, tt.places, tt.expected, got)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package httpapi
+
+import (
+	"net/http"
+)
+
+// concurrencyLimiter bounds how many requests run a handler at once. Up to
+// maxQueued further requests wait for a slot; anything beyond that is
+// rejected immediately with 503.
+type concurrencyLimiter struct {
+	slots    chan struct{} // held while the handler runs
+	admitted chan struct{} // held while running or queued
+}
+
+func newConcurrencyLimiter(maxConcurrent, maxQueued int) *concurrencyLimiter {
+	if maxConcurrent < 1 {
+		maxConcurrent = 1
+	}
+	if maxQueued < 0 {
+		maxQueued = 0
+	}
+	return &concurrencyLimiter{
+		slots:    make(chan struct{}, maxConcurrent),
+		admitted: make(chan struct{}, maxConcurrent+maxQueued),
+	}
+}
+
+// Middleware wraps next with the limiter. Slots are released via defer so a
+// panicking handler can't leak them.
+func (l *concurrencyLimiter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case l.admitted <- struct{}{}:
+		default:
+			w.Header().Set("Retry-After", "1")
+			writeJSONError(w, http.StatusServiceUnavailable, "SERVICE_UNAVAILABLE", "Too many analyses in progress, try again shortly")
+			return
+		}
+		defer func() { <-l.admitted }()
+
+		select {
+		case l.slots <- struct{}{}:
+		case <-r.Context().Done():
+			return
+		}
+		defer func() { <-l.slots }()
+
+		next.ServeHTTP(w, r)
+	})
+}
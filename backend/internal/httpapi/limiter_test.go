@@ -0,0 +1,79 @@
+package httpapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestConcurrencyLimiterRejectsWhenQueueFull(t *testing.T) {
+	const maxConcurrent, maxQueued = 2, 1
+
+	release := make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.WriteHeader(http.StatusOK)
+	})
+
+	limiter := newConcurrencyLimiter(maxConcurrent, maxQueued)
+	wrapped := limiter.Middleware(handler)
+
+	// Fill every running slot and the queue
+	var wg sync.WaitGroup
+	codes := make(chan int, maxConcurrent+maxQueued)
+	for i := 0; i < maxConcurrent+maxQueued; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w := httptest.NewRecorder()
+			wrapped.ServeHTTP(w, httptest.NewRequest("POST", "/api/showdown/analyze", nil))
+			codes <- w.Code
+		}()
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for len(limiter.admitted) < maxConcurrent+maxQueued {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for requests to be admitted")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// The next request has nowhere to wait
+	w := httptest.NewRecorder()
+	wrapped.ServeHTTP(w, httptest.NewRequest("POST", "/api/showdown/analyze", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected status 503, got %d", w.Code)
+	}
+	if w.Header().Get("Retry-After") == "" {
+		t.Error("expected Retry-After header on 503")
+	}
+
+	close(release)
+	wg.Wait()
+	close(codes)
+	for code := range codes {
+		if code != http.StatusOK {
+			t.Errorf("expected admitted requests to succeed, got %d", code)
+		}
+	}
+}
+
+func TestConcurrencyLimiterReleasesOnPanic(t *testing.T) {
+	limiter := newConcurrencyLimiter(1, 0)
+	wrapped := limiter.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}))
+
+	func() {
+		defer func() { _ = recover() }()
+		wrapped.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/", nil))
+	}()
+
+	if len(limiter.slots) != 0 || len(limiter.admitted) != 0 {
+		t.Errorf("expected slots to be released after panic, got %d running, %d admitted",
+			len(limiter.slots), len(limiter.admitted))
+	}
+}
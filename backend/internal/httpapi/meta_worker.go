@@ -0,0 +1,120 @@
+package httpapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sort"
+	"time"
+
+	"github.com/dtsong/vgccorner/backend/internal/analysis"
+	"github.com/dtsong/vgccorner/backend/internal/db"
+)
+
+// metaWorker recomputes meta and type effectiveness stats for every stored
+// format on a schedule and saves them to meta_stats and
+// type_effectiveness_stats, so /stats/meta and /stats/effectiveness serve them
+// without re-parsing hundreds of logs per request.
+type metaWorker struct {
+	s      *Server
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// startMetaWorker recomputes meta stats right away and then every interval,
+// until stop is called.
+func (s *Server) startMetaWorker(interval time.Duration) *metaWorker {
+	ctx, cancel := context.WithCancel(context.Background())
+	mw := &metaWorker{s: s, cancel: cancel, done: make(chan struct{})}
+
+	go func() {
+		defer close(mw.done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			// refresh logs its own failures as they happen
+			_ = mw.safeRefresh(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return mw
+}
+
+// refresh recomputes and stores meta and type effectiveness stats for each
+// format with public battles, plus the all-formats aggregate under "". A
+// format that fails is logged and skipped, so one bad format doesn't leave
+// the rest stale; the failures are returned joined.
+func (mw *metaWorker) refresh(ctx context.Context) error {
+	isPrivate := false
+	counts, err := mw.s.db.CountBattlesByFormat(ctx, &db.BattleFilter{IsPrivate: &isPrivate})
+	if err != nil {
+		mw.logf(ctx, "meta worker: failed to list formats: %v", err)
+		return err
+	}
+
+	formats := make([]string, 0, len(counts)+1)
+	for format := range counts {
+		if format != "" {
+			formats = append(formats, format)
+		}
+	}
+	sort.Strings(formats)
+	formats = append([]string{""}, formats...)
+
+	var errs []error
+	for _, format := range formats {
+		if err := mw.refreshFormat(ctx, format); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			err = fmt.Errorf("failed to refresh %q: %w", format, err)
+			mw.logf(ctx, "meta worker: %v", err)
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// refreshFormat recomputes and stores the stats for one format. One parse
+// feeds both aggregations.
+func (mw *metaWorker) refreshFormat(ctx context.Context, format string) error {
+	summaries, err := mw.s.parseMetaBattles(ctx, format)
+	if err != nil {
+		return err
+	}
+	if err := mw.s.db.StoreMetaStats(ctx, analysis.AggregateMeta(format, summaries, metaTopN)); err != nil {
+		return err
+	}
+	return mw.s.db.StoreTypeEffectiveness(ctx, analysis.AggregateTypeEffectiveness(format, summaries))
+}
+
+// logf logs a refresh failure unless the worker is shutting down, when
+// cancelled queries are expected.
+func (mw *metaWorker) logf(ctx context.Context, format string, args ...any) {
+	if ctx.Err() == nil {
+		mw.s.logger.Errorf(format, args...)
+	}
+}
+
+// safeRefresh runs refresh, turning a panic (say, a parser bug hit by one
+// stored log) into an error so the worker and the server keep running.
+func (mw *metaWorker) safeRefresh(ctx context.Context) (err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			err = fmt.Errorf("panic during refresh: %v\n%s", rec, debug.Stack())
+			mw.logf(ctx, "meta worker: %v", err)
+		}
+	}()
+	return mw.refresh(ctx)
+}
+
+// stop cancels any refresh in progress and waits for the worker to exit.
+func (mw *metaWorker) stop() {
+	mw.cancel()
+	<-mw.done
+}
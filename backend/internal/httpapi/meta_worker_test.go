@@ -0,0 +1,241 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/dtsong/vgccorner/backend/internal/db"
+	"github.com/dtsong/vgccorner/backend/internal/observability"
+)
+
+func TestMetaWorkerStoresMetaStatsOnStartup(t *testing.T) {
+	conn, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	server := &Server{
+		logger:    observability.NewLogger(),
+		db:        db.NewDatabaseFromConn(conn),
+		metaCache: newTTLCache(metaCacheTTL),
+	}
+	format := "[Gen 9] VGC 2025 Reg H (Bo3)"
+
+	mock.ExpectQuery("SELECT format, COUNT\\(\\*\\) FROM battles").
+		WithArgs(false).
+		WillReturnRows(sqlmock.NewRows([]string{"format", "count"}).AddRow(format, 1))
+	// The all-formats aggregate comes first, then each format
+	mock.ExpectQuery("SELECT battle_log FROM battles").
+		WithArgs(false, metaBattleLimit).
+		WillReturnRows(sqlmock.NewRows([]string{"battle_log"}).AddRow(sampleShowdownLog()))
+	mock.ExpectExec("INSERT INTO meta_stats").
+		WithArgs("", sqlmock.AnyArg(), 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO type_effectiveness_stats").
+		WithArgs("", sqlmock.AnyArg(), 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT battle_log FROM battles").
+		WithArgs(format, false, metaBattleLimit).
+		WillReturnRows(sqlmock.NewRows([]string{"battle_log"}).AddRow(sampleShowdownLog()))
+	mock.ExpectExec("INSERT INTO meta_stats").
+		WithArgs(format, sqlmock.AnyArg(), 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO type_effectiveness_stats").
+		WithArgs(format, sqlmock.AnyArg(), 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	// The first refresh runs immediately, long before the interval elapses
+	worker := server.startMetaWorker(time.Hour)
+	deadline := time.Now().Add(2 * time.Second)
+	for mock.ExpectationsWereMet() != nil && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	worker.stop()
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("expected meta_stats and type_effectiveness_stats rows to be written on startup: %v", err)
+	}
+}
+
+func TestMetaWorkerRefreshContinuesPastFailingFormat(t *testing.T) {
+	conn, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	server := &Server{
+		logger:    observability.NewLogger(),
+		db:        db.NewDatabaseFromConn(conn),
+		metaCache: newTTLCache(metaCacheTTL),
+	}
+	format := "[Gen 9] VGC 2025 Reg H (Bo3)"
+
+	mock.ExpectQuery("SELECT format, COUNT\\(\\*\\) FROM battles").
+		WithArgs(false).
+		WillReturnRows(sqlmock.NewRows([]string{"format", "count"}).AddRow(format, 1))
+	// The all-formats aggregate fails; the format after it is still stored
+	mock.ExpectQuery("SELECT battle_log FROM battles").
+		WithArgs(false, metaBattleLimit).
+		WillReturnError(errors.New("connection reset"))
+	mock.ExpectQuery("SELECT battle_log FROM battles").
+		WithArgs(format, false, metaBattleLimit).
+		WillReturnRows(sqlmock.NewRows([]string{"battle_log"}).AddRow(sampleShowdownLog()))
+	mock.ExpectExec("INSERT INTO meta_stats").
+		WithArgs(format, sqlmock.AnyArg(), 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO type_effectiveness_stats").
+		WithArgs(format, sqlmock.AnyArg(), 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mw := &metaWorker{s: server}
+	err = mw.refresh(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "connection reset") {
+		t.Errorf("expected the failed format's error to be returned, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("expected the remaining format to be refreshed: %v", err)
+	}
+}
+
+func TestMetaWorkerRecoversPanics(t *testing.T) {
+	// A nil database panics on the first query, standing in for a stored log
+	// that crashes the parser
+	server := &Server{
+		logger:    observability.NewLogger(),
+		metaCache: newTTLCache(metaCacheTTL),
+	}
+
+	// The first refresh runs immediately
+	worker := server.startMetaWorker(time.Hour)
+	time.Sleep(50 * time.Millisecond)
+
+	select {
+	case <-worker.done:
+		t.Fatal("expected the worker to keep running after a panicking refresh")
+	default:
+	}
+	worker.stop()
+}
+
+func TestGetMetaStatsServesPrecomputed(t *testing.T) {
+	conn, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	server := &Server{
+		logger:     observability.NewLogger(),
+		db:         db.NewDatabaseFromConn(conn),
+		metaCache:  newTTLCache(metaCacheTTL),
+		metaWorker: &metaWorker{},
+	}
+
+	mock.ExpectQuery("SELECT stats, computed_at FROM meta_stats").
+		WithArgs("").
+		WillReturnRows(sqlmock.NewRows([]string{"stats", "computed_at"}).
+			AddRow([]byte(`{"format":"","battleCount":42,"topPokemon":[{"name":"Incineroar","count":30}],"topMoves":[],"leads":[]}`), time.Now()))
+	// A format the worker hasn't computed is empty rather than re-parsed
+	mock.ExpectQuery("SELECT stats, computed_at FROM meta_stats").
+		WithArgs("gen9ou").
+		WillReturnRows(sqlmock.NewRows([]string{"stats", "computed_at"}))
+
+	req := httptest.NewRequest("GET", "/api/stats/meta", nil)
+	w := httptest.NewRecorder()
+	server.handleGetMetaStats(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp MetaStatsResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Data.BattleCount != 42 || resp.Metadata == nil || !resp.Metadata.Cached {
+		t.Errorf("expected the precomputed 42-battle stats, got %+v", resp)
+	}
+	expectCount(t, "pokemon", resp.Data.TopPokemon, "Incineroar", 30)
+
+	w = httptest.NewRecorder()
+	server.handleGetMetaStats(w, httptest.NewRequest("GET", "/api/stats/meta?format=gen9ou", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	resp = MetaStatsResponse{}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Data.BattleCount != 0 || len(resp.Data.TopPokemon) != 0 {
+		t.Errorf("expected empty stats for an uncomputed format, got %+v", resp.Data)
+	}
+
+	// No battle logs were re-parsed
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestGetEffectivenessStatsServesPrecomputed(t *testing.T) {
+	conn, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	server := &Server{
+		logger:     observability.NewLogger(),
+		db:         db.NewDatabaseFromConn(conn),
+		metaCache:  newTTLCache(metaCacheTTL),
+		metaWorker: &metaWorker{},
+	}
+
+	mock.ExpectQuery("SELECT stats, computed_at FROM type_effectiveness_stats").
+		WithArgs("").
+		WillReturnRows(sqlmock.NewRows([]string{"stats", "computed_at"}).
+			AddRow([]byte(`{"format":"","battleCount":42,"types":{"Fire":{"superEffective":3,"neutral":1,"resisted":0,"immune":0}}}`), time.Now()))
+	// A format the worker hasn't computed is empty rather than re-parsed
+	mock.ExpectQuery("SELECT stats, computed_at FROM type_effectiveness_stats").
+		WithArgs("gen9ou").
+		WillReturnRows(sqlmock.NewRows([]string{"stats", "computed_at"}))
+
+	get := func(path string) EffectivenessStatsResponse {
+		t.Helper()
+		w := httptest.NewRecorder()
+		server.handleGetEffectivenessStats(w, httptest.NewRequest("GET", path, nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+		var resp EffectivenessStatsResponse
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		return resp
+	}
+
+	resp := get("/api/stats/effectiveness")
+	if resp.Data.BattleCount != 42 || resp.Data.Types["Fire"].SuperEffective != 3 {
+		t.Errorf("expected the precomputed 42-battle stats, got %+v", resp.Data)
+	}
+	if resp.Metadata == nil || !resp.Metadata.Cached {
+		t.Error("expected precomputed stats to be marked cached")
+	}
+
+	resp = get("/api/stats/effectiveness?format=gen9ou")
+	if resp.Data.BattleCount != 0 || len(resp.Data.Types) != 0 {
+		t.Errorf("expected empty stats for an uncomputed format, got %+v", resp.Data)
+	}
+
+	// No battle logs were re-parsed
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
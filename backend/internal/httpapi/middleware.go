@@ -0,0 +1,48 @@
+package httpapi
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/dtsong/vgccorner/backend/internal/observability"
+)
+
+// Middleware wraps an http.Handler with cross-cutting behavior such as
+// logging, CORS, auth, or rate limiting.
+type Middleware func(http.Handler) http.Handler
+
+// Chain composes middlewares into one. They run in registration order: the
+// first middleware sees the request first and the response last.
+func Chain(middlewares ...Middleware) Middleware {
+	return func(next http.Handler) http.Handler {
+		for i := len(middlewares) - 1; i >= 0; i-- {
+			next = middlewares[i](next)
+		}
+		return next
+	}
+}
+
+// middlewareChain returns the router-wide middlewares in the order they run.
+// Built-in middlewares go here ahead of those added with WithMiddleware;
+// panic recovery is outermost so it also covers the other middlewares, and
+// CORS comes next so preflights skip the rest.
+func (c routerConfig) middlewareChain(logger *observability.Logger) []Middleware {
+	chain := make([]Middleware, 0, len(c.middlewares)+2)
+	chain = append(chain, recoverPanics(logger))
+	if len(c.corsOrigins) > 0 {
+		chain = append(chain, c.corsPolicy().middleware)
+	}
+	chain = append(chain, c.middlewares...)
+	return chain
+}
+
+// corsPolicy builds the CORS policy configured by WithCORS.
+func (c routerConfig) corsPolicy() corsPolicy {
+	policy := corsPolicy{origins: make(map[string]bool, len(c.corsOrigins)), maxAge: c.corsMaxAge}
+	for _, origin := range c.corsOrigins {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			policy.origins[origin] = true
+		}
+	}
+	return policy
+}
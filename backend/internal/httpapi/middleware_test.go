@@ -0,0 +1,86 @@
+package httpapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/dtsong/vgccorner/backend/internal/observability"
+)
+
+// recordingMiddleware appends name to calls when a request passes through it.
+func recordingMiddleware(name string, calls *[]string) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			*calls = append(*calls, name)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func TestChainRunsInRegistrationOrder(t *testing.T) {
+	var calls []string
+	handler := Chain(
+		recordingMiddleware("first", &calls),
+		recordingMiddleware("second", &calls),
+		recordingMiddleware("third", &calls),
+	)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, "handler")
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+
+	expected := []string{"first", "second", "third", "handler"}
+	if !reflect.DeepEqual(calls, expected) {
+		t.Errorf("expected %v, got %v", expected, calls)
+	}
+}
+
+func TestChainEmpty(t *testing.T) {
+	called := false
+	handler := Chain()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+
+	if !called {
+		t.Error("expected an empty chain to call the handler")
+	}
+}
+
+func TestNewRouterAppliesMiddleware(t *testing.T) {
+	var calls []string
+	router := NewRouter(observability.NewLogger(), nil, WithMiddleware(
+		recordingMiddleware("outer", &calls),
+		recordingMiddleware("inner", &calls),
+	))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/healthz", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	if !reflect.DeepEqual(calls, []string{"outer", "inner"}) {
+		t.Errorf("expected middlewares in registration order, got %v", calls)
+	}
+}
+
+func TestMiddlewareChainOrder(t *testing.T) {
+	var calls []string
+	cfg := defaultRouterConfig()
+	WithMiddleware(recordingMiddleware("a", &calls))(&cfg)
+	WithMiddleware(recordingMiddleware("b", &calls))(&cfg)
+
+	// Panic recovery is always registered ahead of configured middlewares
+	chain := cfg.middlewareChain(observability.NewLogger())
+	if len(chain) != 3 {
+		t.Fatalf("expected 3 middlewares, got %d", len(chain))
+	}
+	Chain(chain...)(http.NotFoundHandler()).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	if !reflect.DeepEqual(calls, []string{"a", "b"}) {
+		t.Errorf("expected options applied in order, got %v", calls)
+	}
+}
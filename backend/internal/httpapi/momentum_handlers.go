@@ -0,0 +1,28 @@
+package httpapi
+
+import (
+	"net/http"
+
+	"github.com/dtsong/vgccorner/backend/internal/analysis"
+	"github.com/go-chi/chi/v5"
+)
+
+// MomentumResponse is the minimal payload for charting a battle's momentum.
+type MomentumResponse struct {
+	BattleID string                   `json:"battleId"`
+	Momentum []analysis.MomentumPoint `json:"momentum"`
+}
+
+// handleGetBattleMomentum handles GET /api/battles/{id}/momentum requests,
+// returning just the per-turn momentum timeline.
+func (s *Server) handleGetBattleMomentum(w http.ResponseWriter, r *http.Request) {
+	summary, ok := s.storedSummary(w, r, chi.URLParam(r, "id"))
+	if !ok {
+		return
+	}
+
+	s.writeJSON(w, http.StatusOK, MomentumResponse{
+		BattleID: chi.URLParam(r, "id"),
+		Momentum: summary.MomentumTimeline(),
+	})
+}
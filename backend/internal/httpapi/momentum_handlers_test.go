@@ -0,0 +1,88 @@
+package httpapi
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/dtsong/vgccorner/backend/internal/db"
+	"github.com/dtsong/vgccorner/backend/internal/observability"
+)
+
+func TestGetBattleMomentum(t *testing.T) {
+	conn, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	now := time.Now()
+	mock.ExpectQuery("SELECT id, format, timestamp").
+		WithArgs("battle-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "format", "timestamp", "duration_sec", "winner", "player1_id", "player2_id", "battle_log", "is_private", "created_at", "updated_at"}).
+			AddRow("battle-1", "[Gen 9] VGC 2025 Reg H (Bo3)", now, 0, "player2", "Player1", "Player2", sampleShowdownLog(), false, now, now))
+	mock.ExpectQuery("SELECT battle_id, total_turns").
+		WithArgs("battle-1").
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery("SELECT turn_number, moment_type").
+		WithArgs("battle-1").
+		WillReturnRows(sqlmock.NewRows([]string{"turn_number", "moment_type", "description", "significance"}))
+	mock.ExpectQuery("SELECT player_number, slot, species").
+		WithArgs("battle-1").
+		WillReturnRows(sqlmock.NewRows([]string{"player_number", "slot", "species", "ability", "item", "tera_type", "lead"}))
+
+	router := NewRouter(observability.NewLogger(), db.NewDatabaseFromConn(conn))
+	req := httptest.NewRequest("GET", "/api/battles/battle-1/momentum", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp MomentumResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.BattleID != "battle-1" {
+		t.Errorf("expected battleId battle-1, got %q", resp.BattleID)
+	}
+	// sampleShowdownLog has 4 turns
+	if len(resp.Momentum) != 4 {
+		t.Fatalf("expected 4 momentum points, got %d", len(resp.Momentum))
+	}
+	for i, point := range resp.Momentum {
+		if point.Turn != i+1 {
+			t.Errorf("expected point %d to be turn %d, got %d", i, i+1, point.Turn)
+		}
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestGetBattleMomentumNotFound(t *testing.T) {
+	conn, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	mock.ExpectQuery("SELECT id, format, timestamp").
+		WithArgs("missing").
+		WillReturnError(sql.ErrNoRows)
+
+	router := NewRouter(observability.NewLogger(), db.NewDatabaseFromConn(conn))
+	req := httptest.NewRequest("GET", "/api/battles/missing/momentum", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status 404, got %d", w.Code)
+	}
+}
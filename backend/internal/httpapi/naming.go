@@ -0,0 +1,164 @@
+package httpapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Key naming conventions for summary responses. camelCase is what the json
+// tags say; every other convention is mapped from it at encode time, so there
+// is one canonical set of response structs.
+const (
+	namingCamel = "camel"
+	namingSnake = "snake"
+)
+
+// keyMapping renames the keys a response struct encodes to. Only keys that
+// come from struct fields are renamed: map keys such as species, move names,
+// or "player1" are data and pass through unchanged.
+type keyMapping struct {
+	rename func(string) string
+	fields sync.Map // reflect.Type -> map[string]reflect.Type, json key -> field type
+}
+
+// keyMappings holds the conventions other than camelCase, which needs none.
+var keyMappings = map[string]*keyMapping{
+	namingSnake: {rename: snakeCase},
+}
+
+// requestedNaming returns the key mapping for the ?naming= query parameter,
+// falling back to the server default. It writes a 400 and returns false for
+// an unknown convention. A nil mapping means camelCase.
+func (s *Server) requestedNaming(w http.ResponseWriter, r *http.Request) (*keyMapping, bool) {
+	switch naming := r.URL.Query().Get("naming"); naming {
+	case "":
+		return s.naming, true
+	case namingCamel:
+		return nil, true
+	default:
+		if mapping, ok := keyMappings[naming]; ok {
+			return mapping, true
+		}
+	}
+	writeValidationErrors(w, []FieldError{{Field: "naming", Message: "must be one of: camel, snake"}})
+	return nil, false
+}
+
+// apply returns v as a generic document with its struct keys renamed. A nil
+// mapping returns v unchanged.
+func (m *keyMapping) apply(v interface{}) (interface{}, error) {
+	if m == nil {
+		return v, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var doc interface{}
+	if err := dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+	return plainNumbers(m.renameKeys(doc, reflect.TypeOf(v))), nil
+}
+
+// renameKeys walks a decoded document alongside the Go type it was encoded
+// from. Objects from maps keep their keys; objects from structs, or from
+// values whose type isn't known, are renamed.
+func (m *keyMapping) renameKeys(doc interface{}, t reflect.Type) interface{} {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch value := doc.(type) {
+	case map[string]interface{}:
+		if t != nil && t.Kind() == reflect.Map {
+			for key, elem := range value {
+				value[key] = m.renameKeys(elem, t.Elem())
+			}
+			return value
+		}
+		fields := m.fieldTypes(t)
+		renamed := make(map[string]interface{}, len(value))
+		for key, elem := range value {
+			renamed[m.rename(key)] = m.renameKeys(elem, fields[key])
+		}
+		return renamed
+
+	case []interface{}:
+		var elemType reflect.Type
+		if t != nil && (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+			elemType = t.Elem()
+		}
+		for i, elem := range value {
+			value[i] = m.renameKeys(elem, elemType)
+		}
+	}
+	return doc
+}
+
+// fieldTypes maps the json keys of struct type t to their field types,
+// including fields promoted from embedded structs. It returns nil for
+// anything but a struct.
+func (m *keyMapping) fieldTypes(t reflect.Type) map[string]reflect.Type {
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil
+	}
+	if cached, ok := m.fields.Load(t); ok {
+		return cached.(map[string]reflect.Type)
+	}
+
+	fields := make(map[string]reflect.Type)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" || (!field.IsExported() && !field.Anonymous) {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if name == "" && field.Anonymous {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			for key, fieldType := range m.fieldTypes(embedded) {
+				if _, ok := fields[key]; !ok {
+					fields[key] = fieldType
+				}
+			}
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[name] = field.Type
+	}
+
+	m.fields.Store(t, fields)
+	return fields
+}
+
+// snakeCase converts a camelCase key to snake_case: "turnNumber" becomes
+// "turn_number" and "battleId" becomes "battle_id". Digits stay attached to
+// the word before them, so "player1Score" becomes "player1_score".
+func snakeCase(key string) string {
+	runes := []rune(key)
+	var b strings.Builder
+	b.Grow(len(key) + 4)
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (!unicode.IsUpper(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
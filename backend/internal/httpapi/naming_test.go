@@ -0,0 +1,116 @@
+package httpapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dtsong/vgccorner/backend/internal/observability"
+)
+
+func analyzeWithNaming(t *testing.T, query string, opts ...RouterOption) *httptest.ResponseRecorder {
+	t.Helper()
+	body, _ := json.Marshal(AnalyzeShowdownRequest{
+		AnalysisType: "rawLog",
+		RawLog:       sampleShowdownLog(),
+	})
+	router := NewRouter(observability.NewLogger(), nil, opts...)
+	req := httptest.NewRequest("POST", "/api/v1/showdown/analyze"+query, bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestAnalyzeShowdownSnakeCaseKeys(t *testing.T) {
+	w := analyzeWithNaming(t, "?naming=snake")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp struct {
+		Status string `json:"status"`
+		Data   struct {
+			Turns []map[string]json.RawMessage `json:"turns"`
+			Stats struct {
+				MoveFrequency map[string]int `json:"move_frequency"`
+			} `json:"stats"`
+			Player1 map[string]json.RawMessage `json:"player1"`
+		} `json:"data"`
+		Metadata map[string]json.RawMessage `json:"metadata"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if resp.Status != "success" || len(resp.Data.Turns) != 4 {
+		t.Fatalf("expected a successful 4-turn summary, got %s", w.Body.String())
+	}
+	turn := resp.Data.Turns[0]
+	if _, ok := turn["turn_number"]; !ok {
+		t.Errorf("expected turn_number in turns, got keys %v", keysOf(turn))
+	}
+	if _, ok := turn["turnNumber"]; ok {
+		t.Error("expected no camelCase turnNumber alongside turn_number")
+	}
+	if _, ok := resp.Data.Player1["revealed_moves"]; !ok {
+		t.Errorf("expected revealed_moves on player1, got keys %v", keysOf(resp.Data.Player1))
+	}
+	if _, ok := resp.Metadata["parse_time_ms"]; !ok {
+		t.Errorf("expected parse_time_ms in metadata, got keys %v", keysOf(resp.Metadata))
+	}
+
+	// Map keys are data, not field names
+	if resp.Data.Stats.MoveFrequency["thunderbolt"] == 0 {
+		t.Errorf("expected move_frequency to keep its move keys, got %v", resp.Data.Stats.MoveFrequency)
+	}
+}
+
+func TestAnalyzeShowdownNamingDefault(t *testing.T) {
+	w := analyzeWithNaming(t, "", WithResponseNaming("snake"))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if !bytes.Contains(w.Body.Bytes(), []byte(`"turn_number"`)) {
+		t.Error("expected the server default to give snake_case keys")
+	}
+
+	w = analyzeWithNaming(t, "?naming=camel", WithResponseNaming("snake"))
+	if !bytes.Contains(w.Body.Bytes(), []byte(`"turnNumber"`)) || bytes.Contains(w.Body.Bytes(), []byte(`"turn_number"`)) {
+		t.Error("expected ?naming=camel to override the server default")
+	}
+}
+
+func TestAnalyzeShowdownUnknownNaming(t *testing.T) {
+	w := analyzeWithNaming(t, "?naming=kebab")
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestSnakeCase(t *testing.T) {
+	tests := map[string]string{
+		"status":            "status",
+		"turnNumber":        "turn_number",
+		"battleId":          "battle_id",
+		"player1Score":      "player1_score",
+		"parseTimeMs":       "parse_time_ms",
+		"superEffectiveHit": "super_effective_hit",
+		"HTTPStatus":        "http_status",
+	}
+	for key, want := range tests {
+		if got := snakeCase(key); got != want {
+			t.Errorf("snakeCase(%q): expected %q, got %q", key, want, got)
+		}
+	}
+}
+
+func keysOf(m map[string]json.RawMessage) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	return keys
+}
@@ -0,0 +1,122 @@
+package httpapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/vmihailenco/msgpack/v5"
+	"gopkg.in/yaml.v3"
+)
+
+// Response encodings the summary endpoints can negotiate through Accept.
+const (
+	mediaJSON    = "application/json"
+	mediaMsgPack = "application/x-msgpack"
+	mediaYAML    = "application/yaml"
+)
+
+// acceptedMediaTypes maps Accept header media types to the encoding served.
+var acceptedMediaTypes = map[string]string{
+	"*/*":                     mediaJSON,
+	"application/*":           mediaJSON,
+	"application/json":        mediaJSON,
+	"application/x-msgpack":   mediaMsgPack,
+	"application/msgpack":     mediaMsgPack,
+	"application/vnd.msgpack": mediaMsgPack,
+	"application/yaml":        mediaYAML,
+	"application/x-yaml":      mediaYAML,
+	"text/yaml":               mediaYAML,
+}
+
+// negotiateEncoding picks the response encoding from the Accept header,
+// preferring the highest q-value and then the first listed. A missing header
+// means JSON. When nothing acceptable is supported it writes a 406 and
+// returns false.
+func negotiateEncoding(w http.ResponseWriter, r *http.Request) (string, bool) {
+	accept := r.Header.Get("Accept")
+	if strings.TrimSpace(accept) == "" {
+		return mediaJSON, true
+	}
+
+	best, bestQ := "", 0.0
+	for _, entry := range strings.Split(accept, ",") {
+		params := strings.Split(entry, ";")
+		encoding, ok := acceptedMediaTypes[strings.ToLower(strings.TrimSpace(params[0]))]
+		if !ok {
+			continue
+		}
+		q := 1.0
+		for _, param := range params[1:] {
+			if value, ok := strings.CutPrefix(strings.TrimSpace(param), "q="); ok {
+				if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+		if q > bestQ {
+			best, bestQ = encoding, q
+		}
+	}
+
+	if best == "" {
+		writeErrorResponse(w, http.StatusNotAcceptable, ErrorResponse{
+			Error:   "Unsupported Accept type",
+			Code:    "NOT_ACCEPTABLE",
+			Details: []string{mediaJSON, mediaMsgPack, mediaYAML},
+		})
+		return "", false
+	}
+	return best, true
+}
+
+// encodeAs writes v in the negotiated encoding. Every encoding uses the json
+// tags, so field names match across formats. JSON and YAML round floats like
+// encodeJSON; MessagePack carries them at full precision.
+func (s *Server) encodeAs(w io.Writer, mediaType string, v interface{}) error {
+	switch mediaType {
+	case mediaMsgPack:
+		enc := msgpack.NewEncoder(w)
+		enc.SetCustomStructTag("json")
+		return enc.Encode(v)
+
+	case mediaYAML:
+		var buf bytes.Buffer
+		if err := s.encodeJSON(&buf, v); err != nil {
+			return err
+		}
+		dec := json.NewDecoder(&buf)
+		dec.UseNumber()
+		var doc interface{}
+		if err := dec.Decode(&doc); err != nil {
+			return err
+		}
+		return yaml.NewEncoder(w).Encode(plainNumbers(doc))
+	}
+	return s.encodeJSON(w, v)
+}
+
+// plainNumbers replaces the json.Numbers in a decoded document with int64 or
+// float64, so YAML and MessagePack write them as numbers rather than strings.
+func plainNumbers(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		for key, elem := range value {
+			value[key] = plainNumbers(elem)
+		}
+	case []interface{}:
+		for i, elem := range value {
+			value[i] = plainNumbers(elem)
+		}
+	case json.Number:
+		if n, err := value.Int64(); err == nil {
+			return n
+		}
+		f, _ := value.Float64()
+		return f
+	}
+	return v
+}
@@ -0,0 +1,104 @@
+package httpapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dtsong/vgccorner/backend/internal/observability"
+	"github.com/vmihailenco/msgpack/v5"
+	"gopkg.in/yaml.v3"
+)
+
+func analyzeWithAccept(t *testing.T, accept string) *httptest.ResponseRecorder {
+	t.Helper()
+	body, _ := json.Marshal(AnalyzeShowdownRequest{
+		AnalysisType: "rawLog",
+		RawLog:       sampleShowdownLog(),
+	})
+	router := NewRouter(observability.NewLogger(), nil)
+	req := httptest.NewRequest("POST", "/api/v1/showdown/analyze", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", accept)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestAnalyzeShowdownMsgPack(t *testing.T) {
+	w := analyzeWithAccept(t, "application/x-msgpack")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if got := w.Header().Get("Content-Type"); got != mediaMsgPack {
+		t.Errorf("expected Content-Type %s, got %q", mediaMsgPack, got)
+	}
+
+	dec := msgpack.NewDecoder(w.Body)
+	dec.SetCustomStructTag("json")
+	var resp AnalyzeResponse
+	if err := dec.Decode(&resp); err != nil {
+		t.Fatalf("failed to decode msgpack response: %v", err)
+	}
+	if resp.Status != "success" || resp.Data == nil {
+		t.Fatalf("expected a successful summary, got %+v", resp)
+	}
+	if resp.Data.Player1.Name != "Player1" || resp.Data.Winner != "player2" {
+		t.Errorf("expected Player1 vs winner player2, got %q and %q", resp.Data.Player1.Name, resp.Data.Winner)
+	}
+	if len(resp.Data.Turns) != 4 {
+		t.Errorf("expected 4 turns, got %d", len(resp.Data.Turns))
+	}
+}
+
+func TestAnalyzeShowdownYAML(t *testing.T) {
+	w := analyzeWithAccept(t, "application/yaml")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp struct {
+		Status string `yaml:"status"`
+		Data   struct {
+			Winner string `yaml:"winner"`
+			Stats  struct {
+				TotalTurns int `yaml:"totalTurns"`
+			} `yaml:"stats"`
+		} `yaml:"data"`
+	}
+	if err := yaml.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode yaml response: %v", err)
+	}
+	if resp.Status != "success" || resp.Data.Winner != "player2" || resp.Data.Stats.TotalTurns != 4 {
+		t.Errorf("expected the JSON field names and values, got %+v", resp)
+	}
+}
+
+func TestNegotiateEncoding(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   string
+	}{
+		{accept: "", want: mediaJSON},
+		{accept: "*/*", want: mediaJSON},
+		{accept: "application/json, application/x-msgpack", want: mediaJSON},
+		{accept: "application/json;q=0.5, application/x-msgpack", want: mediaMsgPack},
+		{accept: "text/html, application/yaml;q=0.9", want: mediaYAML},
+		{accept: "text/html", want: ""},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("Accept", tt.accept)
+		w := httptest.NewRecorder()
+		got, ok := negotiateEncoding(w, req)
+		if got != tt.want {
+			t.Errorf("Accept %q: expected %q, got %q", tt.accept, tt.want, got)
+		}
+		if !ok && w.Code != http.StatusNotAcceptable {
+			t.Errorf("Accept %q: expected status 406, got %d", tt.accept, w.Code)
+		}
+	}
+}
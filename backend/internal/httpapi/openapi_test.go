@@ -0,0 +1,38 @@
+package httpapi
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// TestOpenAPICoversRoutes keeps openapi.yaml in step with routes: every route
+// is documented under apiPrefix, with a legacy alias referencing it.
+func TestOpenAPICoversRoutes(t *testing.T) {
+	data, err := os.ReadFile("../../openapi.yaml")
+	if err != nil {
+		t.Fatalf("failed to read spec: %v", err)
+	}
+	var spec struct {
+		Paths map[string]map[string]interface{} `yaml:"paths"`
+	}
+	if err := yaml.Unmarshal(data, &spec); err != nil {
+		t.Fatalf("failed to parse spec: %v", err)
+	}
+
+	server := &Server{}
+	for _, rt := range server.routes() {
+		path := apiPrefix + rt.pattern
+		if _, ok := spec.Paths[path][strings.ToLower(rt.method)]; !ok {
+			t.Errorf("%s %s is not documented", rt.method, path)
+		}
+
+		// JSON Pointer escaping of the versioned path
+		want := "#/paths/" + strings.ReplaceAll(path, "/", "~1")
+		if ref := spec.Paths[legacyAPIPrefix+rt.pattern]["$ref"]; ref != want {
+			t.Errorf("expected legacy alias %s to reference %s, got %v", legacyAPIPrefix+rt.pattern, want, ref)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package httpapi
+
+import (
+	"runtime"
+	"time"
+)
+
+// RouterOption customizes the server built by NewRouter.
+type RouterOption func(*routerConfig)
+
+// routerConfig holds tunables for NewRouter; zero values mean "use the default".
+type routerConfig struct {
+	maxConcurrentAnalyses int
+	maxQueuedAnalyses     int
+	summaryCacheSize      int
+	middlewares           []Middleware
+	onClose               []func() error
+	jsonFloatPlaces       int
+	adminToken            string
+	statsMapLimit         int
+	responseNaming        string
+	metaWorkerInterval    time.Duration
+	corsOrigins           []string
+	corsMaxAge            time.Duration
+	webhookURL            string
+	webhookSecret         string
+}
+
+func defaultRouterConfig() routerConfig {
+	return routerConfig{
+		maxConcurrentAnalyses: runtime.NumCPU(),
+		maxQueuedAnalyses:     4 * runtime.NumCPU(),
+		summaryCacheSize:      defaultSummaryCacheSize,
+		jsonFloatPlaces:       defaultJSONFloatPlaces,
+		corsMaxAge:            defaultCORSMaxAge,
+	}
+}
+
+// WithAnalysisConcurrency limits how many parse-heavy requests run at once and
+// how many more may wait for a slot before being rejected with 503.
+func WithAnalysisConcurrency(maxConcurrent, maxQueued int) RouterOption {
+	return func(c *routerConfig) {
+		if maxConcurrent > 0 {
+			c.maxConcurrentAnalyses = maxConcurrent
+		}
+		if maxQueued >= 0 {
+			c.maxQueuedAnalyses = maxQueued
+		}
+	}
+}
+
+// WithMiddleware appends router-wide middlewares. They run in the order given,
+// after any built-in middlewares.
+func WithMiddleware(middlewares ...Middleware) RouterOption {
+	return func(c *routerConfig) {
+		c.middlewares = append(c.middlewares, middlewares...)
+	}
+}
+
+// WithSummaryCacheSize sets how many parsed summaries are cached by log
+// content. A size of 0 disables the cache.
+func WithSummaryCacheSize(size int) RouterOption {
+	return func(c *routerConfig) {
+		if size >= 0 {
+			c.summaryCacheSize = size
+		}
+	}
+}
+
+// WithOnClose registers fn to run when the server is closed, e.g. to stop a
+// change listener. Hooks run in the order given.
+func WithOnClose(fn func() error) RouterOption {
+	return func(c *routerConfig) {
+		c.onClose = append(c.onClose, fn)
+	}
+}
+
+// WithJSONFloatPrecision sets how many decimal places floats keep in
+// responses; 0 rounds to whole numbers and a negative value disables rounding.
+func WithJSONFloatPrecision(places int) RouterOption {
+	return func(c *routerConfig) {
+		c.jsonFloatPlaces = places
+	}
+}
+
+// WithAdminToken enables the admin endpoints for requests carrying
+// "Authorization: Bearer <token>". Without it they respond 404.
+func WithAdminToken(token string) RouterOption {
+	return func(c *routerConfig) {
+		c.adminToken = token
+	}
+}
+
+// WithStatsMapLimit trims MoveFrequency and TypeCoverage in responses to
+// their top entries by count, summing the rest under "other". 0, the
+// default, returns them in full.
+func WithStatsMapLimit(top int) RouterOption {
+	return func(c *routerConfig) {
+		if top >= 0 {
+			c.statsMapLimit = top
+		}
+	}
+}
+
+// WithResponseNaming sets the default key naming of summary responses,
+// "camel" (the default) or "snake". Requests can still pick one with
+// ?naming=. Unknown names leave camelCase in place.
+func WithResponseNaming(naming string) RouterOption {
+	return func(c *routerConfig) {
+		c.responseNaming = naming
+	}
+}
+
+// WithMetaWorker recomputes meta and type effectiveness stats for every format
+// into the meta_stats and type_effectiveness_stats tables once at startup and
+// then every interval, and has /stats/meta and /stats/effectiveness serve
+// those results. It needs a database; 0, the default, disables it.
+func WithMetaWorker(interval time.Duration) RouterOption {
+	return func(c *routerConfig) {
+		if interval >= 0 {
+			c.metaWorkerInterval = interval
+		}
+	}
+}
+
+// WithCORS lets browsers on origins call the API, "*" allowing any origin, and
+// lets them cache preflight responses for maxAge (10 minutes if 0). Without
+// it, or with no origins, no CORS headers are sent.
+func WithCORS(origins []string, maxAge time.Duration) RouterOption {
+	return func(c *routerConfig) {
+		c.corsOrigins = append(c.corsOrigins, origins...)
+		if maxAge > 0 {
+			c.corsMaxAge = maxAge
+		}
+	}
+}
+
+// WithWebhook POSTs a small JSON event to url whenever a battle is stored,
+// in the background with retries. With a secret, each request carries an
+// X-VGCCorner-Signature header of "sha256=" and the hex HMAC-SHA256 of the
+// body. An empty url, the default, disables it.
+func WithWebhook(url, secret string) RouterOption {
+	return func(c *routerConfig) {
+		c.webhookURL = url
+		c.webhookSecret = secret
+	}
+}
@@ -0,0 +1,41 @@
+package httpapi
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// paginationLinks builds an RFC 5988 Link header value with rel="next" and
+// rel="prev" entries for a limit/offset listing. All other query parameters on
+// the request URL are preserved. It returns "" when there is only one page.
+func paginationLinks(u *url.URL, limit, offset, total int) string {
+	if limit <= 0 {
+		return ""
+	}
+
+	var links []string
+
+	if offset+limit < total {
+		links = append(links, pageLink(u, limit, offset+limit, "next"))
+	}
+
+	if offset > 0 {
+		prev := offset - limit
+		if prev < 0 {
+			prev = 0
+		}
+		links = append(links, pageLink(u, limit, prev, "prev"))
+	}
+
+	return strings.Join(links, ", ")
+}
+
+func pageLink(u *url.URL, limit, offset int, rel string) string {
+	query := u.Query()
+	query.Set("limit", strconv.Itoa(limit))
+	query.Set("offset", strconv.Itoa(offset))
+
+	page := url.URL{Path: u.Path, RawQuery: query.Encode()}
+	return "<" + page.String() + `>; rel="` + rel + `"`
+}
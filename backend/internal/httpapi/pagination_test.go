@@ -0,0 +1,154 @@
+package httpapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/dtsong/vgccorner/backend/internal/db"
+	"github.com/dtsong/vgccorner/backend/internal/observability"
+)
+
+func TestPaginationLinks(t *testing.T) {
+	tests := []struct {
+		name     string
+		rawURL   string
+		limit    int
+		offset   int
+		total    int
+		expected string
+	}{
+		{
+			name:     "first page has only next",
+			rawURL:   "/api/showdown/replays?format=vgc&limit=10",
+			limit:    10,
+			offset:   0,
+			total:    25,
+			expected: `</api/showdown/replays?format=vgc&limit=10&offset=10>; rel="next"`,
+		},
+		{
+			name:     "middle page has next and prev",
+			rawURL:   "/api/showdown/replays?limit=10&offset=10",
+			limit:    10,
+			offset:   10,
+			total:    25,
+			expected: `</api/showdown/replays?limit=10&offset=20>; rel="next", </api/showdown/replays?limit=10&offset=0>; rel="prev"`,
+		},
+		{
+			name:     "last page has only prev",
+			rawURL:   "/api/showdown/replays?limit=10&offset=20",
+			limit:    10,
+			offset:   20,
+			total:    25,
+			expected: `</api/showdown/replays?limit=10&offset=10>; rel="prev"`,
+		},
+		{
+			name:     "prev offset is clamped to zero",
+			rawURL:   "/api/showdown/replays?offset=5",
+			limit:    10,
+			offset:   5,
+			total:    8,
+			expected: `</api/showdown/replays?limit=10&offset=0>; rel="prev"`,
+		},
+		{
+			name:     "single page has no links",
+			rawURL:   "/api/showdown/replays",
+			limit:    10,
+			offset:   0,
+			total:    3,
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, _ := url.Parse(tt.rawURL)
+			got := paginationLinks(u, tt.limit, tt.offset, tt.total)
+			if got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestListShowdownReplaysLinkHeader(t *testing.T) {
+	conn, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	logger := observability.NewLogger()
+	server := &Server{logger: logger, db: db.NewDatabaseFromConn(conn)}
+
+	mock.ExpectQuery("SELECT COUNT").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(5))
+	mock.ExpectQuery("SELECT (.+) FROM battles").
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "format", "timestamp", "duration_sec", "winner",
+			"player1_id", "player2_id", "is_private",
+		}).
+			AddRow("id1", "vgc", time.Now(), 300, "player1", "Alice", "Bob", false).
+			AddRow("id2", "vgc", time.Now(), 300, "player2", "Carol", "Dave", false))
+
+	req := httptest.NewRequest("GET", "/api/showdown/replays?format=vgc&limit=2", nil)
+	w := httptest.NewRecorder()
+
+	server.handleListShowdownReplays(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+
+	expected := `</api/showdown/replays?format=vgc&limit=2&offset=2>; rel="next"`
+	if got := w.Header().Get("Link"); got != expected {
+		t.Errorf("expected Link header %q, got %q", expected, got)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestListShowdownReplaysLegacyLinkHeaders(t *testing.T) {
+	conn, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	router := NewRouter(observability.NewLogger(), db.NewDatabaseFromConn(conn))
+
+	mock.ExpectQuery("SELECT COUNT").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(5))
+	mock.ExpectQuery("SELECT (.+) FROM battles").
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "format", "timestamp", "duration_sec", "winner",
+			"player1_id", "player2_id", "is_private",
+		}).
+			AddRow("id1", "vgc", time.Now(), 300, "player1", "Alice", "Bob", false).
+			AddRow("id2", "vgc", time.Now(), 300, "player2", "Carol", "Dave", false))
+
+	req := httptest.NewRequest("GET", "/api/showdown/replays?format=vgc&limit=2", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	links := strings.Join(w.Header().Values("Link"), ", ")
+	for _, rel := range []string{`rel="successor-version"`, `rel="next"`} {
+		if !strings.Contains(links, rel) {
+			t.Errorf("expected Link headers to include %s, got %q", rel, links)
+		}
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
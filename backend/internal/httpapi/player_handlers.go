@@ -0,0 +1,81 @@
+package httpapi
+
+import (
+	"net/http"
+
+	"github.com/dtsong/vgccorner/backend/internal/analysis"
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	// openingsBattleLimit bounds how many recent battles are re-parsed per
+	// openings request.
+	openingsBattleLimit = 200
+	// openingsTopN caps each list in an openings response.
+	openingsTopN = 10
+)
+
+// PlayerOpeningsResponse is how a player tends to open their battles.
+type PlayerOpeningsResponse struct {
+	PlayerID       string              `json:"playerId"`
+	GamesPlayed    int                 `json:"gamesPlayed"`
+	Leads          []LeadUsageResponse `json:"leads"`          // Most common first
+	FirstTurnMoves []MoveUsageResponse `json:"firstTurnMoves"` // Most common first
+}
+
+// LeadUsageResponse is a lead the player opened with and how often.
+type LeadUsageResponse struct {
+	Pokemon []string `json:"pokemon"`
+	Count   int      `json:"count"`
+}
+
+// MoveUsageResponse is a move the player used on turn 1 and in how many battles.
+type MoveUsageResponse struct {
+	Move  string `json:"move"`
+	Count int    `json:"count"`
+}
+
+// handleGetPlayerOpenings handles GET /api/players/{id}/openings requests. An
+// unknown player gets empty lists, not a 404, since a player only exists
+// through their stored battles. Only public battles are counted.
+func (s *Server) handleGetPlayerOpenings(w http.ResponseWriter, r *http.Request) {
+	playerID := chi.URLParam(r, "id")
+	logs, err := s.db.PlayerBattleLogs(r.Context(), playerID, openingsBattleLimit)
+	if err != nil {
+		s.logger.Infof("Failed to retrieve player battles: %v", err)
+		writeJSONError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Internal server error")
+		return
+	}
+
+	// Neither leads nor turn-1 moves are persisted, so read them back from the logs
+	battles := make([]analysis.PlayerBattle, 0, len(logs))
+	for _, stored := range logs {
+		battle := analysis.PlayerBattle{Slot: stored.Slot}
+		summary, err := analysis.ParseShowdownLog(stored.BattleLog)
+		if err != nil {
+			s.logger.Infof("Skipping unparseable battle log: %v", err)
+		} else {
+			battle.Summary = summary
+		}
+		battles = append(battles, battle)
+	}
+
+	s.writeJSON(w, http.StatusOK, convertPlayerOpenings(analysis.AggregateOpenings(playerID, battles, openingsTopN)))
+}
+
+// convertPlayerOpenings converts aggregated openings to their API format.
+func convertPlayerOpenings(openings analysis.PlayerOpenings) PlayerOpeningsResponse {
+	resp := PlayerOpeningsResponse{
+		PlayerID:       openings.PlayerID,
+		GamesPlayed:    openings.GamesPlayed,
+		Leads:          make([]LeadUsageResponse, 0, len(openings.Leads)),
+		FirstTurnMoves: make([]MoveUsageResponse, 0, len(openings.FirstTurnMoves)),
+	}
+	for _, lead := range openings.Leads {
+		resp.Leads = append(resp.Leads, LeadUsageResponse{Pokemon: lead.Pokemon, Count: lead.Count})
+	}
+	for _, move := range openings.FirstTurnMoves {
+		resp.FirstTurnMoves = append(resp.FirstTurnMoves, MoveUsageResponse{Move: move.Name, Count: move.Count})
+	}
+	return resp
+}
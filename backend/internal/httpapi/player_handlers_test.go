@@ -0,0 +1,107 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/dtsong/vgccorner/backend/internal/db"
+	"github.com/dtsong/vgccorner/backend/internal/observability"
+)
+
+func openingsLog(lead, move string) string {
+	return `|player|p1|Alice|1|
+|player|p2|Bob|2|
+|gametype|doubles
+|start
+|switch|p1a: ` + lead + `|` + lead + `, L50|100/100
+|switch|p1b: Incineroar|Incineroar, L50|100/100
+|switch|p2a: Rillaboom|Rillaboom, L50|100/100
+|switch|p2b: Urshifu|Urshifu, L50|100/100
+|turn|1
+|move|p1b: Incineroar|Fake Out|p2a: Rillaboom
+|move|p1a: ` + lead + `|` + move + `|p2b: Urshifu
+|upkeep
+|turn|2
+`
+}
+
+func TestGetPlayerOpenings(t *testing.T) {
+	conn, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	mock.ExpectQuery("SELECT player1_id, battle_log FROM battles").
+		WithArgs("Alice", openingsBattleLimit).
+		WillReturnRows(sqlmock.NewRows([]string{"player1_id", "battle_log"}).
+			AddRow("Alice", openingsLog("Amoonguss", "Spore")).
+			AddRow("Alice", openingsLog("Flutter Mane", "Moonblast")).
+			AddRow("Alice", openingsLog("Amoonguss", "Spore")))
+
+	router := NewRouter(observability.NewLogger(), db.NewDatabaseFromConn(conn))
+	req := httptest.NewRequest("GET", "/api/v1/players/Alice/openings", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp PlayerOpeningsResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.PlayerID != "Alice" || resp.GamesPlayed != 3 {
+		t.Errorf("expected 3 games for Alice, got %+v", resp)
+	}
+	if len(resp.Leads) != 2 {
+		t.Fatalf("expected 2 distinct leads, got %+v", resp.Leads)
+	}
+	if top := resp.Leads[0]; top.Count != 2 || len(top.Pokemon) != 2 || top.Pokemon[0] != "Amoonguss" || top.Pokemon[1] != "Incineroar" {
+		t.Errorf("expected Amoonguss + Incineroar as the top lead, got %+v", top)
+	}
+	want := []MoveUsageResponse{{Move: "Fake Out", Count: 3}, {Move: "Spore", Count: 2}, {Move: "Moonblast", Count: 1}}
+	if len(resp.FirstTurnMoves) != len(want) {
+		t.Fatalf("expected turn-1 moves %+v, got %+v", want, resp.FirstTurnMoves)
+	}
+	for i := range want {
+		if resp.FirstTurnMoves[i] != want[i] {
+			t.Errorf("expected turn-1 moves %+v, got %+v", want, resp.FirstTurnMoves)
+			break
+		}
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestGetPlayerOpeningsUnknownPlayer(t *testing.T) {
+	conn, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	mock.ExpectQuery("SELECT player1_id, battle_log FROM battles").
+		WillReturnRows(sqlmock.NewRows([]string{"player1_id", "battle_log"}))
+
+	router := NewRouter(observability.NewLogger(), db.NewDatabaseFromConn(conn))
+	req := httptest.NewRequest("GET", "/api/v1/players/Nobody/openings", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp map[string]json.RawMessage
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if string(resp["leads"]) != "[]" || string(resp["firstTurnMoves"]) != "[]" {
+		t.Errorf("expected empty arrays, got %s", w.Body.String())
+	}
+}
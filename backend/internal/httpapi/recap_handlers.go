@@ -0,0 +1,52 @@
+package httpapi
+
+import (
+	"net/http"
+
+	"github.com/dtsong/vgccorner/backend/internal/analysis"
+	"github.com/go-chi/chi/v5"
+)
+
+// handleGetBattleRecap handles GET /api/battles/{id}/recap requests. The recap
+// is plain text for pasting into Discord or Twitter; errors are still JSON.
+func (s *Server) handleGetBattleRecap(w http.ResponseWriter, r *http.Request) {
+	summary, ok := s.storedSummary(w, r, chi.URLParam(r, "id"))
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(analysis.Recap(summary)))
+}
+
+// storedSummary loads a stored battle and parses its log. On failure it
+// writes a JSON error response and returns false.
+func (s *Server) storedSummary(w http.ResponseWriter, r *http.Request, battleID string) (*analysis.BattleSummary, bool) {
+	if battleID == "" {
+		writeJSONError(w, http.StatusBadRequest, "INVALID_REQUEST", "id is required")
+		return nil, false
+	}
+
+	battle, err := s.db.GetBattle(r.Context(), battleID)
+	if err != nil {
+		s.logger.Infof("Failed to retrieve battle: %v", err)
+		writeJSONError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Internal server error")
+		return nil, false
+	}
+
+	if battle == nil {
+		writeJSONError(w, http.StatusNotFound, "NOT_FOUND", "Battle not found")
+		return nil, false
+	}
+
+	// Turn data and KOs aren't persisted, so re-parse the stored log
+	summary, err := s.summaryCache.Parse(battle.BattleLog)
+	if err != nil {
+		s.logger.Infof("Failed to parse battle log: %v", err)
+		writeJSONError(w, http.StatusInternalServerError, "PARSE_ERROR", "Failed to parse battle log")
+		return nil, false
+	}
+
+	return summary, true
+}
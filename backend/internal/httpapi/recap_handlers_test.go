@@ -0,0 +1,72 @@
+package httpapi
+
+import (
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/dtsong/vgccorner/backend/internal/db"
+	"github.com/dtsong/vgccorner/backend/internal/observability"
+)
+
+func TestGetBattleRecap(t *testing.T) {
+	conn, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	now := time.Now()
+	mock.ExpectQuery("SELECT id, format, timestamp").
+		WithArgs("battle-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "format", "timestamp", "duration_sec", "winner", "player1_id", "player2_id", "battle_log", "is_private", "created_at", "updated_at"}).
+			AddRow("battle-1", "[Gen 9] VGC 2025 Reg H (Bo3)", now, 0, "player2", "Player1", "Player2", sampleShowdownLog(), false, now, now))
+	mock.ExpectQuery("SELECT battle_id, total_turns").
+		WithArgs("battle-1").
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery("SELECT turn_number, moment_type").
+		WithArgs("battle-1").
+		WillReturnRows(sqlmock.NewRows([]string{"turn_number", "moment_type", "description", "significance"}))
+	mock.ExpectQuery("SELECT player_number, slot, species").
+		WithArgs("battle-1").
+		WillReturnRows(sqlmock.NewRows([]string{"player_number", "slot", "species", "ability", "item", "tera_type", "lead"}))
+
+	router := NewRouter(observability.NewLogger(), db.NewDatabaseFromConn(conn))
+	req := httptest.NewRequest("GET", "/api/battles/battle-1/recap", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+		t.Errorf("expected text/plain content type, got %q", ct)
+	}
+
+	recap := w.Body.String()
+	if !strings.Contains(recap, "Winner: Player2") {
+		t.Errorf("expected recap to name the winner, got:\n%s", recap)
+	}
+	if !strings.Contains(recap, "Turns: 4") {
+		t.Errorf("expected recap to include the turn count, got:\n%s", recap)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestGetBattleRecapNoDatabase(t *testing.T) {
+	router := NewRouter(observability.NewLogger(), nil)
+	req := httptest.NewRequest("GET", "/api/battles/battle-1/recap", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected status 503, got %d", w.Code)
+	}
+}
@@ -0,0 +1,52 @@
+package httpapi
+
+import (
+	"errors"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/dtsong/vgccorner/backend/internal/observability"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// requestIDHeader carries a caller- or proxy-assigned request ID.
+const requestIDHeader = "X-Request-Id"
+
+// recoverPanics turns a panicking handler into a 500 INTERNAL_ERROR response
+// and logs the panic with its stack trace, so one bad log can't take down
+// the server. http.ErrAbortHandler is re-panicked: it's how handlers ask
+// net/http to abort the response, and the server already handles it quietly.
+func recoverPanics(logger *observability.Logger) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				rec := recover()
+				if rec == nil {
+					return
+				}
+				if err, ok := rec.(error); ok && errors.Is(err, http.ErrAbortHandler) {
+					panic(rec)
+				}
+
+				logger.Errorf("panic serving %s %s (request %s): %v\n%s",
+					r.Method, r.URL.Path, requestID(r), rec, debug.Stack())
+
+				writeJSONError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Internal server error")
+			}()
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// requestID returns the ID chi's RequestID middleware assigned, falling back
+// to the X-Request-Id header, or "-" when the request has neither.
+func requestID(r *http.Request) string {
+	if id := middleware.GetReqID(r.Context()); id != "" {
+		return id
+	}
+	if id := r.Header.Get(requestIDHeader); id != "" {
+		return id
+	}
+	return "-"
+}
@@ -0,0 +1,59 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dtsong/vgccorner/backend/internal/observability"
+)
+
+func panickingMiddleware(value interface{}) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/healthz" && r.URL.Query().Get("panic") != "" {
+				panic(value)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func TestRecoverPanicsReturnsInternalError(t *testing.T) {
+	router := NewRouter(observability.NewLogger(), nil, WithMiddleware(panickingMiddleware("parser exploded")))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/healthz?panic=1", nil))
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("expected status 500, got %d", w.Code)
+	}
+	var resp ErrorResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Code != "INTERNAL_ERROR" {
+		t.Errorf("expected INTERNAL_ERROR, got %q", resp.Code)
+	}
+
+	// The router keeps serving after a panic
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/healthz", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status 200 after recovering, got %d", w.Code)
+	}
+}
+
+func TestRecoverPanicsRepanicsAbortHandler(t *testing.T) {
+	handler := recoverPanics(observability.NewLogger())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	}))
+
+	defer func() {
+		if rec := recover(); rec != http.ErrAbortHandler {
+			t.Errorf("expected http.ErrAbortHandler to propagate, got %v", rec)
+		}
+	}()
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+}
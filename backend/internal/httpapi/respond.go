@@ -0,0 +1,29 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// writeJSON writes v as a JSON response with the given status, rounding
+// floats as configured by WithJSONFloatPrecision. Encode errors are ignored: the
+// status is already sent and the client has gone or will see a short body.
+func (s *Server) writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", mediaJSON)
+	w.WriteHeader(status)
+	_ = s.encodeJSON(w, v)
+}
+
+// writeJSONError writes an ErrorResponse with the given status, machine-
+// readable code (e.g. "NOT_FOUND"), and human-readable message.
+func writeJSONError(w http.ResponseWriter, status int, code, message string) {
+	writeErrorResponse(w, status, ErrorResponse{Error: message, Code: code})
+}
+
+// writeErrorResponse writes an ErrorResponse carrying more than a code and
+// message, such as per-field validation errors.
+func writeErrorResponse(w http.ResponseWriter, status int, resp ErrorResponse) {
+	w.Header().Set("Content-Type", mediaJSON)
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,46 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dtsong/vgccorner/backend/internal/observability"
+)
+
+func TestWriteJSONError(t *testing.T) {
+	w := httptest.NewRecorder()
+	writeJSONError(w, http.StatusNotFound, "NOT_FOUND", "Battle not found")
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status 404, got %d", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("expected application/json, got %q", ct)
+	}
+
+	var body map[string]json.RawMessage
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode body: %v", err)
+	}
+	if len(body) != 2 || string(body["error"]) != `"Battle not found"` || string(body["code"]) != `"NOT_FOUND"` {
+		t.Errorf("expected only error and code, got %s", w.Body.String())
+	}
+}
+
+func TestWriteJSONRoundsFloats(t *testing.T) {
+	s := NewServer(observability.NewLogger(), nil, WithJSONFloatPrecision(2))
+	w := httptest.NewRecorder()
+	s.writeJSON(w, http.StatusCreated, map[string]float64{"winRate": 2.0 / 3.0})
+
+	if w.Code != http.StatusCreated {
+		t.Errorf("expected status 201, got %d", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("expected application/json, got %q", ct)
+	}
+	if got := w.Body.String(); got != "{\"winRate\":0.67}\n" {
+		t.Errorf("expected the float rounded to 2 places, got %q", got)
+	}
+}
@@ -1,36 +1,132 @@
 package httpapi
 
 import (
+	"context"
+	"errors"
 	"net/http"
+	"sync"
 
+	"github.com/dtsong/vgccorner/backend/internal/analysis"
 	"github.com/dtsong/vgccorner/backend/internal/db"
 	"github.com/dtsong/vgccorner/backend/internal/observability"
 	"github.com/go-chi/chi/v5"
 )
 
 type Server struct {
-	logger *observability.Logger
-	db     *db.Database
+	logger          *observability.Logger
+	db              *db.Database
+	store           battleStore // nil when no database is configured
+	metaCache       *ttlCache
+	idempotencyKeys *ttlCache // Idempotency-Key -> idempotentStore for storing analyze requests
+	summaryCache    *summaryCache
+	floatPlaces     int              // Decimal places kept by encodeJSON; negative disables rounding
+	adminToken      string           // Bearer token for admin endpoints; empty disables them
+	statsLimit      int              // Entries kept per growing stats map; 0 keeps all
+	naming          *keyMapping      // Default key naming for summary responses; nil for camelCase
+	metaWorker      *metaWorker      // Precomputes meta stats; nil when disabled
+	webhook         *webhookNotifier // Notified of stored battles; nil when disabled
+
+	handler   http.Handler
+	onClose   []func() error
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// battleStore persists analyzed battles; *db.Database implements it.
+type battleStore interface {
+	StoreBattle(ctx context.Context, battle *db.Battle) (string, error)
+	StoreTurnData(ctx context.Context, battleID string, summary *analysis.BattleSummary) error
 }
 
-func NewRouter(logger *observability.Logger, database *db.Database) http.Handler {
-	s := &Server{logger: logger, db: database}
+// NewRouter builds the API handler. Use NewServer instead when the caller
+// needs to release the server's background resources on shutdown.
+func NewRouter(logger *observability.Logger, database *db.Database, opts ...RouterOption) http.Handler {
+	return NewServer(logger, database, opts...)
+}
+
+// NewServer builds the API server. It serves HTTP directly; call Close once
+// it no longer receives requests.
+func NewServer(logger *observability.Logger, database *db.Database, opts ...RouterOption) *Server {
+	cfg := defaultRouterConfig()
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	s := &Server{
+		logger:          logger,
+		db:              database,
+		metaCache:       newTTLCache(metaCacheTTL),
+		idempotencyKeys: newTTLCache(idempotencyKeyTTL),
+		summaryCache:    newSummaryCache(cfg.summaryCacheSize),
+		onClose:         cfg.onClose,
+		floatPlaces:     cfg.jsonFloatPlaces,
+		adminToken:      cfg.adminToken,
+		statsLimit:      cfg.statsMapLimit,
+		naming:          keyMappings[cfg.responseNaming],
+	}
+	if cfg.webhookURL != "" {
+		s.webhook = newWebhookNotifier(cfg.webhookURL, cfg.webhookSecret, logger)
+	}
+	s.metaCache.startJanitor(metaCacheTTL)
+	s.idempotencyKeys.startJanitor(idempotencySweepInterval)
+
+	// Battles stored by other instances make cached aggregates stale. Parsed
+	// summaries are keyed by log content and never go stale.
+	if database != nil {
+		s.store = database
+		database.OnBattlesChanged(func(string) { s.invalidateCaches() })
+		if cfg.metaWorkerInterval > 0 {
+			s.metaWorker = s.startMetaWorker(cfg.metaWorkerInterval)
+		}
+	}
 
 	r := chi.NewRouter()
+	r.Use(Chain(cfg.middlewareChain(logger)...))
 
 	// Health check endpoint
 	r.Get("/healthz", s.handleHealth)
 
-	// Showdown analysis endpoints
-	r.Post("/api/showdown/analyze", s.handleAnalyzeShowdown)
-	r.Get("/api/showdown/replays", s.handleListShowdownReplays)
-	r.Get("/api/showdown/replays/{replayId}", s.handleGetShowdownReplay)
-	r.Get("/api/showdown/replays/{replayId}/turns", s.handleGetTurnAnalysis)
+	// Parse-heavy endpoints share a concurrency limit
+	analyzeLimiter := newConcurrencyLimiter(cfg.maxConcurrentAnalyses, cfg.maxQueuedAnalyses)
 
-	// TCG Live endpoint (planned)
-	r.Post("/api/tcglive/analyze", s.handleAnalyzeTCGLive)
+	routes := s.routes()
+	mountRoutes(r, apiPrefix, routes, analyzeLimiter.Middleware)
+	mountRoutes(r.With(deprecatedPrefix), legacyAPIPrefix, routes, analyzeLimiter.Middleware)
+
+	s.handler = r
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.handler.ServeHTTP(w, r)
+}
+
+// Close stops the server's background goroutines and runs any WithOnClose
+// hooks. It does not close the database, which the caller owns. Calling Close
+// more than once returns the first result.
+func (s *Server) Close() error {
+	s.closeOnce.Do(func() {
+		s.metaCache.stop()
+		s.idempotencyKeys.stop()
+		if s.metaWorker != nil {
+			s.metaWorker.stop()
+		}
+		s.webhook.stop()
+
+		var errs []error
+		for _, fn := range s.onClose {
+			if err := fn(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		s.closeErr = errors.Join(errs...)
+	})
+	return s.closeErr
+}
 
-	return r
+// invalidateCaches drops cached data derived from the battles table.
+func (s *Server) invalidateCaches() {
+	s.metaCache.Clear()
 }
 
 func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
@@ -3,7 +3,9 @@ package httpapi
 import (
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/dtsong/vgccorner/backend/internal/observability"
 )
@@ -66,8 +68,13 @@ func TestRouterEndpointsExist(t *testing.T) {
 	}{
 		{"healthz GET", "GET", "/healthz", false, false},
 		{"showdown analyze POST", "POST", "/api/showdown/analyze", false, false},
+		{"showdown import POST", "POST", "/api/showdown/import", false, false},
+		{"showdown validate POST", "POST", "/api/showdown/validate", false, false},
 		{"showdown list GET", "GET", "/api/showdown/replays", false, true},       // Requires DB
 		{"showdown get GET", "GET", "/api/showdown/replays/test-id", true, true}, // Requires DB
+		{"formats GET", "GET", "/api/formats", false, false},
+		{"stats meta GET", "GET", "/api/stats/meta", false, false},
+		{"admin tables GET", "GET", "/api/admin/tables", true, false}, // 404 without an admin token
 		{"tcglive analyze POST", "POST", "/api/tcglive/analyze", false, false},
 	}
 
@@ -89,6 +96,39 @@ func TestRouterEndpointsExist(t *testing.T) {
 	}
 }
 
+func TestVersionedAndLegacyRoutes(t *testing.T) {
+	router := NewRouter(observability.NewLogger(), nil)
+
+	serve := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", path, strings.NewReader(`{"analysisType":"logText"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	versioned := serve("/api/v1/showdown/analyze")
+	legacy := serve("/api/showdown/analyze")
+
+	if versioned.Code == http.StatusNotFound {
+		t.Fatal("expected /api/v1/showdown/analyze to be routed")
+	}
+	if legacy.Code != versioned.Code || legacy.Body.String() != versioned.Body.String() {
+		t.Errorf("expected the legacy path to match the versioned one, got %d %q vs %d %q",
+			legacy.Code, legacy.Body.String(), versioned.Code, versioned.Body.String())
+	}
+
+	if versioned.Header().Get("Deprecation") != "" {
+		t.Error("expected no Deprecation header on the versioned path")
+	}
+	if legacy.Header().Get("Deprecation") != "true" {
+		t.Error("expected a Deprecation header on the legacy path")
+	}
+	if link := legacy.Header().Get("Link"); link != `</api/v1/showdown/analyze>; rel="successor-version"` {
+		t.Errorf("expected a successor-version link, got %q", link)
+	}
+}
+
 func TestHTTPMethodsNotAllowed(t *testing.T) {
 	logger := observability.NewLogger()
 	router := NewRouter(logger, nil)
@@ -136,3 +176,54 @@ func TestRouterResponseHeaders(t *testing.T) {
 		t.Logf("content type: %q", contentType)
 	}
 }
+
+func TestServerCloseStopsCacheJanitor(t *testing.T) {
+	hooks := 0
+	server := NewServer(observability.NewLogger(), nil, WithOnClose(func() error {
+		hooks++
+		return nil
+	}))
+
+	// Swap in a fast janitor so the test can watch it tick
+	server.metaCache.stop()
+	server.metaCache = newTTLCache(time.Millisecond)
+	server.metaCache.startJanitor(time.Millisecond)
+
+	expired := ttlEntry{value: 1, expiresAt: time.Now().Add(-time.Minute)}
+	server.metaCache.mu.Lock()
+	server.metaCache.entries["stale"] = expired
+	server.metaCache.mu.Unlock()
+
+	deadline := time.Now().Add(time.Second)
+	for cacheLen(server.metaCache) != 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("expected the janitor to sweep the expired entry")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if err := server.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := server.Close(); err != nil {
+		t.Fatalf("unexpected error on second close: %v", err)
+	}
+	if hooks != 1 {
+		t.Errorf("expected the close hook to run once, got %d", hooks)
+	}
+
+	// No further ticks: an expired entry now stays until it's read
+	server.metaCache.mu.Lock()
+	server.metaCache.entries["stale"] = expired
+	server.metaCache.mu.Unlock()
+	time.Sleep(20 * time.Millisecond)
+	if cacheLen(server.metaCache) != 1 {
+		t.Error("expected the janitor to be stopped after Close")
+	}
+}
+
+func cacheLen(c *ttlCache) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
@@ -0,0 +1,97 @@
+package httpapi
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	// apiPrefix is where the current API version is served.
+	apiPrefix = "/api/v1"
+
+	// legacyAPIPrefix serves the same routes unversioned until clients move
+	// to apiPrefix. Responses carry a Deprecation header.
+	legacyAPIPrefix = "/api"
+)
+
+// route is one API endpoint, declared once relative to the version prefix.
+type route struct {
+	method  string
+	pattern string // e.g. "/showdown/analyze"
+	handler http.HandlerFunc
+	limited bool // Parse-heavy; shares the analysis concurrency limit
+}
+
+// routes lists every versioned API endpoint.
+func (s *Server) routes() []route {
+	return []route{
+		// Showdown analysis endpoints
+		{method: http.MethodPost, pattern: "/showdown/analyze", handler: s.handleAnalyzeShowdown, limited: true},
+		{method: http.MethodPost, pattern: "/showdown/import", handler: s.handleImportShowdown, limited: true},
+		{method: http.MethodPost, pattern: "/showdown/validate", handler: s.handleValidateShowdown, limited: true},
+		{method: http.MethodGet, pattern: "/showdown/replays", handler: s.requireDatabase(s.handleListShowdownReplays)},
+		{method: http.MethodGet, pattern: "/showdown/replays/{replayId}", handler: s.requireDatabase(s.handleGetShowdownReplay)},
+		{method: http.MethodGet, pattern: "/showdown/replays/{replayId}/turns", handler: s.requireDatabase(s.handleGetTurnAnalysis)},
+
+		// Formats with dedicated ruleset data
+		{method: http.MethodGet, pattern: "/formats", handler: s.handleListFormats},
+
+		// Shareable battle views
+		{method: http.MethodGet, pattern: "/battles/{id}/recap", handler: s.requireDatabase(s.handleGetBattleRecap)},
+		{method: http.MethodGet, pattern: "/battles/{id}/momentum", handler: s.requireDatabase(s.handleGetBattleMomentum)},
+		{method: http.MethodGet, pattern: "/battles/{id}/stats", handler: s.requireDatabase(s.handleGetBattleStats)},
+		{method: http.MethodGet, pattern: "/battles/{id}/turns/{n}", handler: s.requireDatabase(s.handleGetBattleTurn)},
+		{method: http.MethodGet, pattern: "/battles/compare", handler: s.requireDatabase(s.handleCompareBattles)},
+
+		// Player tendencies across stored battles
+		{method: http.MethodGet, pattern: "/players/{id}/openings", handler: s.requireDatabase(s.handleGetPlayerOpenings), limited: true},
+
+		// Aggregate statistics endpoints
+		{method: http.MethodGet, pattern: "/stats/meta", handler: s.handleGetMetaStats, limited: true},
+		{method: http.MethodGet, pattern: "/stats/effectiveness", handler: s.handleGetEffectivenessStats, limited: true},
+
+		// Operator endpoints; only served when an admin token is configured
+		{method: http.MethodGet, pattern: "/admin/tables", handler: s.requireAdmin(s.requireDatabase(s.handleGetTableStats))},
+		{method: http.MethodGet, pattern: "/admin/pool", handler: s.requireAdmin(s.requireDatabase(s.handleGetPoolStats))},
+
+		// TCG Live endpoint (planned)
+		{method: http.MethodPost, pattern: "/tcglive/analyze", handler: s.handleAnalyzeTCGLive, limited: true},
+	}
+}
+
+// requireDatabase answers 503 for endpoints that read stored battles when the
+// server runs without a database, instead of letting the handler hit a nil
+// *db.Database. Parse-only endpoints don't use it and keep working.
+func (s *Server) requireDatabase(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.db == nil {
+			writeJSONError(w, http.StatusServiceUnavailable, "SERVICE_UNAVAILABLE", "Database not configured")
+			return
+		}
+		next(w, r)
+	}
+}
+
+// mountRoutes registers routes under prefix. limit wraps the parse-heavy ones
+// and, being shared, caps them across every prefix together.
+func mountRoutes(r chi.Router, prefix string, routes []route, limit Middleware) {
+	for _, rt := range routes {
+		var handler http.Handler = rt.handler
+		if rt.limited {
+			handler = limit(handler)
+		}
+		r.Method(rt.method, prefix+rt.pattern, handler)
+	}
+}
+
+// deprecatedPrefix marks responses from the unversioned aliases and points
+// clients at the versioned path. The Link is added rather than set so handlers
+// can add their own relations, such as pagination, alongside it.
+func deprecatedPrefix(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Deprecation", "true")
+		w.Header().Add("Link", "<"+apiPrefix+r.URL.Path[len(legacyAPIPrefix):]+`>; rel="successor-version"`)
+		next.ServeHTTP(w, r)
+	})
+}
@@ -1,8 +1,11 @@
 package httpapi
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
+	"fmt"
 	"net/http"
+	"slices"
 	"strconv"
 	"time"
 
@@ -27,8 +30,11 @@ type AnalyzeShowdownRequest struct {
 	// For rawLog analysis
 	RawLog string `json:"rawLog,omitempty"`
 
-	// Common field
+	// Common fields
 	IsPrivate bool `json:"isPrivate"`
+	// Store requires the battle to be persisted, failing with 503 when no
+	// database is configured. Without it, battles are stored only if one is.
+	Store bool `json:"store,omitempty"`
 }
 
 // AnalyzeResponse is the response for analyze requests.
@@ -48,9 +54,10 @@ type ResponseMetadata struct {
 
 // ErrorResponse is the response for errors.
 type ErrorResponse struct {
-	Error   string      `json:"error"`
-	Code    string      `json:"code"`
-	Details interface{} `json:"details,omitempty"`
+	Error   string       `json:"error"`
+	Code    string       `json:"code"`
+	Details interface{}  `json:"details,omitempty"`
+	Errors  []FieldError `json:"errors,omitempty"` // Per-field validation failures
 }
 
 // ListReplaysRequest represents query parameters for listing replays.
@@ -64,131 +71,79 @@ type ListReplaysRequest struct {
 
 // handleAnalyzeShowdown handles POST /api/showdown/analyze requests.
 func (s *Server) handleAnalyzeShowdown(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
 	start := time.Now()
 
 	var req AnalyzeShowdownRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := decodeJSONBody(r, &req); err != nil {
 		s.logger.Infof("Failed to decode request body: %v", err)
-		w.WriteHeader(http.StatusBadRequest)
-		_ = json.NewEncoder(w).Encode(ErrorResponse{
-			Error: "Invalid request body",
-			Code:  "INVALID_REQUEST",
-		})
+		writeJSONError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+
+	if errs := req.Validate(); len(errs) > 0 {
+		writeValidationErrors(w, errs)
+		return
+	}
+	format, ok := s.requestedSummaryFormat(w, r)
+	if !ok {
 		return
 	}
 
-	// Validate request based on analysis type
 	var battleSummary *analysis.BattleSummary
 	var battlelLog string
+	var cached bool
 	var err error
 
 	switch req.AnalysisType {
 	case "replayId":
-		if req.ReplayID == "" {
-			w.WriteHeader(http.StatusBadRequest)
-			_ = json.NewEncoder(w).Encode(ErrorResponse{
-				Error: "replayId is required for replayId analysis",
-				Code:  "INVALID_REQUEST",
-			})
-			return
-		}
 		// TODO: Fetch replay from Showdown API or cache
-		w.WriteHeader(http.StatusNotImplemented)
-		_ = json.NewEncoder(w).Encode(ErrorResponse{
-			Error: "Replay ID analysis not yet implemented",
-			Code:  "NOT_IMPLEMENTED",
-		})
+		writeJSONError(w, http.StatusNotImplemented, "NOT_IMPLEMENTED", "Replay ID analysis not yet implemented")
 		return
 
 	case "username":
-		if req.Username == "" || req.Format == "" {
-			w.WriteHeader(http.StatusBadRequest)
-			_ = json.NewEncoder(w).Encode(ErrorResponse{
-				Error: "username and format are required for username analysis",
-				Code:  "INVALID_REQUEST",
-			})
-			return
-		}
 		// TODO: Fetch recent battles by username
-		w.WriteHeader(http.StatusNotImplemented)
-		_ = json.NewEncoder(w).Encode(ErrorResponse{
-			Error: "Username analysis not yet implemented",
-			Code:  "NOT_IMPLEMENTED",
-		})
+		writeJSONError(w, http.StatusNotImplemented, "NOT_IMPLEMENTED", "Username analysis not yet implemented")
 		return
 
 	case "rawLog":
-		if req.RawLog == "" {
-			w.WriteHeader(http.StatusBadRequest)
-			_ = json.NewEncoder(w).Encode(ErrorResponse{
-				Error: "rawLog is required for rawLog analysis",
-				Code:  "INVALID_REQUEST",
-			})
-			return
-		}
 		battlelLog = req.RawLog
-
-	default:
-		w.WriteHeader(http.StatusBadRequest)
-		_ = json.NewEncoder(w).Encode(ErrorResponse{
-			Error: "analysisType must be one of: replayId, username, rawLog",
-			Code:  "INVALID_REQUEST",
-		})
-		return
 	}
 
 	// Parse battle log with enhanced turn tracking
 	parseStart := time.Now()
-	battleSummary, err = analysis.ParseEnhancedShowdownLog(battlelLog)
+	battleSummary, cached, err = s.summaryCache.ParseCached(battlelLog)
 	parseTime := time.Since(parseStart).Milliseconds()
 
 	if err != nil {
 		s.logger.Infof("Failed to parse battle log: %v", err)
-		w.WriteHeader(http.StatusBadRequest)
-		_ = json.NewEncoder(w).Encode(ErrorResponse{
-			Error: "Failed to parse battle log: " + err.Error(),
-			Code:  "PARSE_ERROR",
-		})
+		writeJSONError(w, http.StatusBadRequest, "PARSE_ERROR", "Failed to parse battle log: "+err.Error())
 		return
 	}
 
 	// Store battle in database (if database is configured)
 	battleID := battleSummary.ID
-	if s.db != nil {
-		ctx := r.Context()
-		battleRecord := &db.Battle{
-			ID:          battleSummary.ID,
-			Format:      battleSummary.Format,
-			Timestamp:   battleSummary.Timestamp,
-			DurationSec: battleSummary.Duration,
-			Winner:      battleSummary.Winner,
-			Player1ID:   battleSummary.Player1.Name,
-			Player2ID:   battleSummary.Player2.Name,
-			BattleLog:   battlelLog,
-			IsPrivate:   req.IsPrivate,
-			Analysis:    convertBattleStats(battleSummary),
-			KeyMoments:  convertKeyMoments(battleSummary),
-		}
-
-		// Store battle and basic analysis
-		storedID, err := s.db.StoreBattle(ctx, battleRecord)
-		if err != nil {
+	if req.Store && s.store == nil {
+		writeJSONError(w, http.StatusServiceUnavailable, "SERVICE_UNAVAILABLE", "Database not configured; cannot store battle")
+		return
+	}
+	if s.store != nil {
+		storedID, replayed, err := s.storeOnce(r.Context(), r.Header.Get(idempotencyKeyHeader), battleSummary, battlelLog, req.IsPrivate)
+		switch {
+		case errors.Is(err, errIdempotencyKeyReused):
+			writeJSONError(w, http.StatusUnprocessableEntity, "IDEMPOTENCY_KEY_REUSED", "Idempotency-Key was already used for a different battle log")
+			return
+		case errors.Is(err, errIdempotencyKeyInFlight):
+			writeJSONError(w, http.StatusConflict, "IDEMPOTENCY_KEY_IN_FLIGHT", "A request with this Idempotency-Key is still in progress")
+			return
+		case err != nil:
 			s.logger.Infof("Failed to store battle: %v", err)
-			w.WriteHeader(http.StatusInternalServerError)
-			_ = json.NewEncoder(w).Encode(ErrorResponse{
-				Error: "Failed to store battle",
-				Code:  "INTERNAL_ERROR",
-			})
+			writeJSONError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to store battle")
 			return
 		}
-		battleID = storedID
-
-		// Store detailed turn-by-turn data
-		if err := s.db.StoreTurnData(ctx, battleID, battleSummary); err != nil {
-			s.logger.Infof("Failed to store turn data: %v", err)
-			// Don't fail the request, just log the error
+		if replayed {
+			w.Header().Set(idempotencyReplayedHeader, "true")
 		}
+		battleID = storedID
 	}
 
 	analysisTime := time.Since(start).Milliseconds()
@@ -196,19 +151,60 @@ func (s *Server) handleAnalyzeShowdown(w http.ResponseWriter, r *http.Request) {
 	s.logger.Infof("Successfully analyzed Showdown battle: %s (Player1: %s, Player2: %s)",
 		battleSummary.ID, battleSummary.Player1.TeamArchetype, battleSummary.Player2.TeamArchetype)
 
+	w.Header().Set("Content-Type", format.mediaType)
 	w.WriteHeader(http.StatusOK)
-	_ = json.NewEncoder(w).Encode(AnalyzeResponse{
+	_ = s.encodeSummary(w, format, AnalyzeResponse{
 		Status:   "success",
 		BattleID: battleID,
 		Data:     battleSummary,
 		Metadata: &ResponseMetadata{
 			ParseTimeMs:    int(parseTime),
 			AnalysisTimeMs: int(analysisTime),
-			Cached:         false,
+			Cached:         cached,
 		},
 	})
 }
 
+// storeAnalyzedBattle persists a parsed battle with its analysis, key moments,
+// teams, and turn data, returning the stored battle ID.
+func (s *Server) storeAnalyzedBattle(ctx context.Context, summary *analysis.BattleSummary, battleLog string, isPrivate bool) (string, error) {
+	battleRecord := &db.Battle{
+		ID:          summary.ID,
+		Format:      summary.Format,
+		Timestamp:   summary.Timestamp,
+		DurationSec: summary.Duration,
+		Winner:      storedWinner(summary),
+		Player1ID:   summary.Player1.Name,
+		Player2ID:   summary.Player2.Name,
+		BattleLog:   battleLog,
+		IsPrivate:   isPrivate,
+		Analysis:    convertBattleStats(summary),
+		KeyMoments:  convertKeyMoments(summary),
+		Teams:       convertTeams(summary),
+	}
+
+	// Store battle and basic analysis
+	battleID, err := s.store.StoreBattle(ctx, battleRecord)
+	if err != nil {
+		return "", err
+	}
+
+	// Store detailed turn-by-turn data
+	if err := s.store.StoreTurnData(ctx, battleID, summary); err != nil {
+		s.logger.Infof("Failed to store turn data: %v", err)
+		// Don't fail the request, just log the error
+	}
+
+	s.webhook.notify(WebhookEvent{
+		Event:    battleStoredEvent,
+		BattleID: battleID,
+		Format:   summary.Format,
+		Winner:   storedWinner(summary),
+	})
+
+	return battleID, nil
+}
+
 // convertBattleStats converts analysis stats to database format
 func convertBattleStats(summary *analysis.BattleSummary) *db.BattleAnalysis {
 	return &db.BattleAnalysis{
@@ -243,67 +239,74 @@ func convertKeyMoments(summary *analysis.BattleSummary) []*db.KeyMoment {
 	return moments
 }
 
+// convertTeams flattens both players' revealed teams for storage, marking
+// the Pokémon each led with.
+func convertTeams(summary *analysis.BattleSummary) []*db.BattlePokemon {
+	var teams []*db.BattlePokemon
+	for player, side := range []*analysis.Player{&summary.Player1, &summary.Player2} {
+		for slot, poke := range side.Team {
+			teams = append(teams, &db.BattlePokemon{
+				Player:   fmt.Sprintf("player%d", player+1),
+				Slot:     slot,
+				Species:  poke.Name,
+				Ability:  poke.Ability,
+				Item:     poke.Item,
+				TeraType: poke.TeraType,
+				Lead:     slices.Contains(side.Leads, poke.Name),
+			})
+		}
+	}
+	return teams
+}
+
+// storedWinner maps a summary's result to the battles.winner column, which
+// records ties as "draw".
+func storedWinner(summary *analysis.BattleSummary) string {
+	if summary.Draw {
+		return "draw"
+	}
+	return summary.Winner
+}
+
 // handleGetShowdownReplay handles GET /api/showdown/replays/{replayId} requests.
 func (s *Server) handleGetShowdownReplay(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
 	battleID := chi.URLParam(r, "replayId")
 
 	if battleID == "" {
-		w.WriteHeader(http.StatusBadRequest)
-		_ = json.NewEncoder(w).Encode(ErrorResponse{
-			Error: "replayId is required",
-			Code:  "INVALID_REQUEST",
-		})
+		writeJSONError(w, http.StatusBadRequest, "INVALID_REQUEST", "replayId is required")
 		return
 	}
-
-	s.logger.Infof("Retrieving replay: %s", battleID)
-
-	// Database required for this endpoint
-	if s.db == nil {
-		w.WriteHeader(http.StatusServiceUnavailable)
-		_ = json.NewEncoder(w).Encode(ErrorResponse{
-			Error: "Database not configured",
-			Code:  "SERVICE_UNAVAILABLE",
-		})
+	format, ok := s.requestedSummaryFormat(w, r)
+	if !ok {
 		return
 	}
 
+	s.logger.Infof("Retrieving replay: %s", battleID)
+
 	ctx := r.Context()
 	battle, err := s.db.GetBattle(ctx, battleID)
 	if err != nil {
 		s.logger.Infof("Failed to retrieve battle: %v", err)
-		w.WriteHeader(http.StatusInternalServerError)
-		_ = json.NewEncoder(w).Encode(ErrorResponse{
-			Error: "Internal server error",
-			Code:  "INTERNAL_ERROR",
-		})
+		writeJSONError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Internal server error")
 		return
 	}
 
 	if battle == nil {
-		w.WriteHeader(http.StatusNotFound)
-		_ = json.NewEncoder(w).Encode(ErrorResponse{
-			Error: "Replay not found",
-			Code:  "NOT_FOUND",
-		})
+		writeJSONError(w, http.StatusNotFound, "NOT_FOUND", "Replay not found")
 		return
 	}
 
 	// Parse the battle log to get full summary
-	summary, err := analysis.ParseEnhancedShowdownLog(battle.BattleLog)
+	summary, err := s.summaryCache.Parse(battle.BattleLog)
 	if err != nil {
 		s.logger.Infof("Failed to parse battle log: %v", err)
-		w.WriteHeader(http.StatusInternalServerError)
-		_ = json.NewEncoder(w).Encode(ErrorResponse{
-			Error: "Failed to parse battle log",
-			Code:  "PARSE_ERROR",
-		})
+		writeJSONError(w, http.StatusInternalServerError, "PARSE_ERROR", "Failed to parse battle log")
 		return
 	}
 
+	w.Header().Set("Content-Type", format.mediaType)
 	w.WriteHeader(http.StatusOK)
-	_ = json.NewEncoder(w).Encode(AnalyzeResponse{
+	_ = s.encodeSummary(w, format, AnalyzeResponse{
 		Status:   "success",
 		BattleID: battle.ID,
 		Data:     summary,
@@ -312,8 +315,6 @@ func (s *Server) handleGetShowdownReplay(w http.ResponseWriter, r *http.Request)
 
 // handleListShowdownReplays handles GET /api/showdown/replays requests.
 func (s *Server) handleListShowdownReplays(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-
 	// Parse query parameters
 	username := r.URL.Query().Get("username")
 	format := r.URL.Query().Get("format")
@@ -343,21 +344,6 @@ func (s *Server) handleListShowdownReplays(w http.ResponseWriter, r *http.Reques
 
 	s.logger.Infof("Listing replays: username=%s format=%s isPrivate=%v limit=%d offset=%d", username, format, isPrivate, limit, offset)
 
-	// Database required for this endpoint
-	if s.db == nil {
-		w.WriteHeader(http.StatusOK)
-		_ = json.NewEncoder(w).Encode(map[string]interface{}{
-			"status": "success",
-			"data":   []interface{}{},
-			"pagination": map[string]int{
-				"limit":  limit,
-				"offset": offset,
-				"total":  0,
-			},
-		})
-		return
-	}
-
 	ctx := r.Context()
 	filter := &db.BattleFilter{
 		Format:    format,
@@ -366,16 +352,16 @@ func (s *Server) handleListShowdownReplays(w http.ResponseWriter, r *http.Reques
 	battles, total, err := s.db.ListBattles(ctx, filter, limit, offset)
 	if err != nil {
 		s.logger.Infof("Failed to list battles: %v", err)
-		w.WriteHeader(http.StatusInternalServerError)
-		_ = json.NewEncoder(w).Encode(ErrorResponse{
-			Error: "Internal server error",
-			Code:  "INTERNAL_ERROR",
-		})
+		writeJSONError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Internal server error")
 		return
 	}
 
-	w.WriteHeader(http.StatusOK)
-	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+	if links := paginationLinks(r.URL, limit, offset, total); links != "" {
+		// Added, not set: legacy aliases already carry a successor-version link
+		w.Header().Add("Link", links)
+	}
+
+	s.writeJSON(w, http.StatusOK, map[string]interface{}{
 		"status": "success",
 		"data":   battles,
 		"pagination": map[string]int{
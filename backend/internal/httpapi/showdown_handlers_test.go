@@ -2,12 +2,17 @@ package httpapi
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"sync"
 	"testing"
 
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/dtsong/vgccorner/backend/internal/analysis"
+	"github.com/dtsong/vgccorner/backend/internal/db"
 	"github.com/dtsong/vgccorner/backend/internal/observability"
 )
 
@@ -554,36 +559,77 @@ func TestAnalyzeShowdownResponseContentType(t *testing.T) {
 }
 
 func TestListReplaysLimitBounds(t *testing.T) {
-	logger := observability.NewLogger()
-	server := &Server{logger: logger, db: nil}
-
 	tests := []struct {
-		name     string
-		limit    string
-		expectOK bool
+		name      string
+		limit     string
+		wantLimit int
 	}{
-		{"limit 0", "?limit=0", true},
-		{"limit 50", "?limit=50", true},
-		{"limit 100", "?limit=100", true},
-		{"limit 200", "?limit=200", true}, // Should cap at 100
-		{"limit -1", "?limit=-1", true},   // Should use default
-		{"limit abc", "?limit=abc", true}, // Should use default
+		{"limit 0", "?limit=0", 10},
+		{"limit 50", "?limit=50", 50},
+		{"limit 100", "?limit=100", 100},
+		{"limit 200", "?limit=200", 10}, // Out of range uses default
+		{"limit -1", "?limit=-1", 10},   // Should use default
+		{"limit abc", "?limit=abc", 10}, // Should use default
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			conn, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("failed to create mock: %v", err)
+			}
+			defer func() { _ = conn.Close() }()
+			server := &Server{logger: observability.NewLogger(), db: db.NewDatabaseFromConn(conn)}
+
+			mock.ExpectQuery("SELECT COUNT").
+				WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+			mock.ExpectQuery("SELECT (.+) FROM battles").
+				WithArgs(tt.wantLimit, 0).
+				WillReturnRows(sqlmock.NewRows([]string{
+					"id", "format", "timestamp", "duration_sec", "winner",
+					"player1_id", "player2_id", "is_private",
+				}))
+
 			req := httptest.NewRequest("GET", "/api/showdown/replays"+tt.limit, nil)
 			w := httptest.NewRecorder()
 
 			server.handleListShowdownReplays(w, req)
 
-			if tt.expectOK && w.Code != http.StatusOK {
+			if w.Code != http.StatusOK {
 				t.Errorf("expected status 200, got %d", w.Code)
 			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unmet expectations: %v", err)
+			}
 		})
 	}
 }
 
+func TestEndpointsWithoutDatabase(t *testing.T) {
+	router := NewRouter(observability.NewLogger(), nil)
+
+	list := httptest.NewRecorder()
+	router.ServeHTTP(list, httptest.NewRequest("GET", "/api/v1/showdown/replays", nil))
+	if list.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected listing replays to return 503, got %d", list.Code)
+	}
+	var resp ErrorResponse
+	_ = json.NewDecoder(list.Body).Decode(&resp)
+	if resp.Code != "SERVICE_UNAVAILABLE" {
+		t.Errorf("expected SERVICE_UNAVAILABLE, got %q", resp.Code)
+	}
+
+	body, _ := json.Marshal(AnalyzeShowdownRequest{
+		AnalysisType: "rawLog",
+		RawLog:       sampleShowdownLog(),
+	})
+	analyze := httptest.NewRecorder()
+	router.ServeHTTP(analyze, httptest.NewRequest("POST", "/api/v1/showdown/analyze", bytes.NewReader(body)))
+	if analyze.Code != http.StatusOK {
+		t.Errorf("expected analyzing without storing to return 200, got %d: %s", analyze.Code, analyze.Body.String())
+	}
+}
+
 func TestAnalyzeShowdownAllAnalysisTypesValidation(t *testing.T) {
 	logger := observability.NewLogger()
 	server := &Server{logger: logger}
@@ -701,3 +747,121 @@ func generateLongLog() string {
 	log += `|win|Player1`
 	return log
 }
+
+// fakeBattleStore records stored battles in memory.
+type fakeBattleStore struct {
+	mu        sync.Mutex
+	battles   []*db.Battle
+	turnsFor  []string
+	returnsID string
+}
+
+func (f *fakeBattleStore) StoreBattle(ctx context.Context, battle *db.Battle) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.battles = append(f.battles, battle)
+	return f.returnsID, nil
+}
+
+func (f *fakeBattleStore) StoreTurnData(ctx context.Context, battleID string, summary *analysis.BattleSummary) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.turnsFor = append(f.turnsFor, battleID)
+	return nil
+}
+
+func TestAnalyzeShowdownStore(t *testing.T) {
+	store := &fakeBattleStore{returnsID: "stored-123"}
+	server := &Server{logger: observability.NewLogger(), store: store}
+
+	body, _ := json.Marshal(AnalyzeShowdownRequest{
+		AnalysisType: "rawLog",
+		RawLog:       sampleShowdownLog(),
+		Store:        true,
+	})
+	req := httptest.NewRequest("POST", "/api/showdown/analyze", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+	server.handleAnalyzeShowdown(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp AnalyzeResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.BattleID != "stored-123" {
+		t.Errorf("expected battleId stored-123, got %q", resp.BattleID)
+	}
+
+	if len(store.battles) != 1 {
+		t.Fatalf("expected StoreBattle to be called once, got %d", len(store.battles))
+	}
+	stored := store.battles[0]
+	if stored.BattleLog != sampleShowdownLog() {
+		t.Error("expected the raw log to be stored")
+	}
+	if stored.Analysis == nil {
+		t.Error("expected analysis to be stored")
+	}
+	if len(stored.Teams) == 0 {
+		t.Error("expected teams to be stored")
+	}
+	leads := map[string]int{}
+	for _, poke := range stored.Teams {
+		if poke.Lead {
+			leads[poke.Player]++
+		}
+	}
+	if leads["player1"] == 0 || leads["player2"] == 0 {
+		t.Errorf("expected each side's leads to be marked, got %v", leads)
+	}
+	if len(store.turnsFor) != 1 || store.turnsFor[0] != "stored-123" {
+		t.Errorf("expected turn data stored for stored-123, got %v", store.turnsFor)
+	}
+}
+
+func TestAnalyzeShowdownStoreWithoutDatabase(t *testing.T) {
+	server := &Server{logger: observability.NewLogger()}
+
+	body, _ := json.Marshal(AnalyzeShowdownRequest{
+		AnalysisType: "rawLog",
+		RawLog:       sampleShowdownLog(),
+		Store:        true,
+	})
+	req := httptest.NewRequest("POST", "/api/showdown/analyze", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+	server.handleAnalyzeShowdown(w, req)
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected status 503, got %d", w.Code)
+	}
+	var resp ErrorResponse
+	_ = json.NewDecoder(w.Body).Decode(&resp)
+	if resp.Code != "SERVICE_UNAVAILABLE" {
+		t.Errorf("expected SERVICE_UNAVAILABLE, got %q", resp.Code)
+	}
+}
+
+func TestAnalyzeShowdownReportsCacheHits(t *testing.T) {
+	server := &Server{logger: observability.NewLogger(), summaryCache: newSummaryCache(4)}
+	body, _ := json.Marshal(AnalyzeShowdownRequest{AnalysisType: "rawLog", RawLog: sampleShowdownLog()})
+
+	for i, wantCached := range []bool{false, true} {
+		req := httptest.NewRequest("POST", "/api/showdown/analyze", bytes.NewReader(body))
+		w := httptest.NewRecorder()
+		server.handleAnalyzeShowdown(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("request %d: expected status 200, got %d: %s", i+1, w.Code, w.Body.String())
+		}
+		var resp AnalyzeResponse
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatalf("request %d: failed to decode response: %v", i+1, err)
+		}
+		if resp.Metadata == nil || resp.Metadata.Cached != wantCached {
+			t.Errorf("request %d: expected cached %v, got %+v", i+1, wantCached, resp.Metadata)
+		}
+	}
+}
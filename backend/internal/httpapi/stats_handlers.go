@@ -0,0 +1,200 @@
+package httpapi
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/dtsong/vgccorner/backend/internal/analysis"
+	"github.com/dtsong/vgccorner/backend/internal/db"
+)
+
+const (
+	// metaBattleLimit bounds how many recent battles are re-parsed per aggregation.
+	metaBattleLimit = 500
+	// metaTopN is the number of entries returned for each usage list.
+	metaTopN = 20
+	// metaCacheTTL is how long an aggregation is served before being recomputed.
+	metaCacheTTL = 5 * time.Minute
+	// effectivenessCacheKey prefixes the format in metaCache keys for type
+	// effectiveness aggregations, which share the meta stats cache.
+	effectivenessCacheKey = "effectiveness:"
+)
+
+// MetaStatsResponse is the response for meta statistics requests.
+type MetaStatsResponse struct {
+	Status   string             `json:"status"`
+	Data     analysis.MetaStats `json:"data"`
+	Metadata *ResponseMetadata  `json:"metadata,omitempty"`
+}
+
+// EffectivenessStatsResponse is the response for type effectiveness requests.
+type EffectivenessStatsResponse struct {
+	Status   string                    `json:"status"`
+	Data     analysis.TypeMatchupStats `json:"data"`
+	Metadata *ResponseMetadata         `json:"metadata,omitempty"`
+}
+
+// handleGetMetaStats handles GET /api/stats/meta requests.
+func (s *Server) handleGetMetaStats(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+
+	format := r.URL.Query().Get("format")
+
+	if cached, ok := s.metaCache.Get(format); ok {
+		s.writeJSON(w, http.StatusOK, MetaStatsResponse{
+			Status:   "success",
+			Data:     cached.(analysis.MetaStats),
+			Metadata: &ResponseMetadata{Cached: true},
+		})
+		return
+	}
+
+	// Without a database there is nothing to aggregate
+	if s.db == nil {
+		s.writeJSON(w, http.StatusOK, MetaStatsResponse{
+			Status: "success",
+			Data:   analysis.AggregateMeta(format, nil, metaTopN),
+		})
+		return
+	}
+
+	// The meta worker keeps meta_stats current; when it's running, serve only
+	// its results. A format it hasn't computed has no public battles yet (or
+	// the first refresh is still running), so it gets empty stats rather
+	// than an inline re-parse.
+	if s.metaWorker != nil {
+		stored, err := s.db.GetMetaStats(r.Context(), format)
+		if err != nil {
+			s.logger.Infof("Failed to read precomputed meta stats: %v", err)
+			writeJSONError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Internal server error")
+			return
+		}
+		data := analysis.AggregateMeta(format, nil, metaTopN)
+		if stored != nil {
+			data = stored.Stats
+		}
+		s.writeJSON(w, http.StatusOK, MetaStatsResponse{
+			Status:   "success",
+			Data:     data,
+			Metadata: &ResponseMetadata{Cached: true},
+		})
+		return
+	}
+
+	meta, err := s.aggregateMeta(r.Context(), format)
+	if err != nil {
+		s.logger.Infof("Failed to list battle logs: %v", err)
+		writeJSONError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Internal server error")
+		return
+	}
+	s.metaCache.Set(format, meta)
+
+	s.writeJSON(w, http.StatusOK, MetaStatsResponse{
+		Status: "success",
+		Data:   meta,
+		Metadata: &ResponseMetadata{
+			AnalysisTimeMs: int(time.Since(start).Milliseconds()),
+		},
+	})
+}
+
+// handleGetEffectivenessStats handles GET /api/stats/effectiveness requests.
+func (s *Server) handleGetEffectivenessStats(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+
+	format := r.URL.Query().Get("format")
+	cacheKey := effectivenessCacheKey + format
+
+	if cached, ok := s.metaCache.Get(cacheKey); ok {
+		s.writeJSON(w, http.StatusOK, EffectivenessStatsResponse{
+			Status:   "success",
+			Data:     cached.(analysis.TypeMatchupStats),
+			Metadata: &ResponseMetadata{Cached: true},
+		})
+		return
+	}
+
+	// Without a database there is nothing to aggregate
+	if s.db == nil {
+		s.writeJSON(w, http.StatusOK, EffectivenessStatsResponse{
+			Status: "success",
+			Data:   analysis.AggregateTypeEffectiveness(format, nil),
+		})
+		return
+	}
+
+	// The meta worker keeps type_effectiveness_stats current; when it's
+	// running, serve only its results. A format it hasn't computed gets empty
+	// stats rather than an inline re-parse.
+	if s.metaWorker != nil {
+		stored, err := s.db.GetTypeEffectiveness(r.Context(), format)
+		if err != nil {
+			s.logger.Infof("Failed to read precomputed type effectiveness stats: %v", err)
+			writeJSONError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Internal server error")
+			return
+		}
+		data := analysis.AggregateTypeEffectiveness(format, nil)
+		if stored != nil {
+			data = stored.Stats
+		}
+		s.writeJSON(w, http.StatusOK, EffectivenessStatsResponse{
+			Status:   "success",
+			Data:     data,
+			Metadata: &ResponseMetadata{Cached: true},
+		})
+		return
+	}
+
+	summaries, err := s.parseMetaBattles(r.Context(), format)
+	if err != nil {
+		s.logger.Infof("Failed to list battle logs: %v", err)
+		writeJSONError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Internal server error")
+		return
+	}
+	stats := analysis.AggregateTypeEffectiveness(format, summaries)
+	s.metaCache.Set(cacheKey, stats)
+
+	s.writeJSON(w, http.StatusOK, EffectivenessStatsResponse{
+		Status: "success",
+		Data:   stats,
+		Metadata: &ResponseMetadata{
+			AnalysisTimeMs: int(time.Since(start).Milliseconds()),
+		},
+	})
+}
+
+// aggregateMeta aggregates the meta stats of the most recent public battles of
+// a format, or of every format when it's empty.
+func (s *Server) aggregateMeta(ctx context.Context, format string) (analysis.MetaStats, error) {
+	summaries, err := s.parseMetaBattles(ctx, format)
+	if err != nil {
+		return analysis.MetaStats{}, err
+	}
+	return analysis.AggregateMeta(format, summaries, metaTopN), nil
+}
+
+// parseMetaBattles re-parses the most recent public battles of a format, or of
+// every format when it's empty, skipping logs that fail to parse.
+func (s *Server) parseMetaBattles(ctx context.Context, format string) ([]*analysis.BattleSummary, error) {
+	isPrivate := false
+	filter := &db.BattleFilter{
+		Format:    format,
+		IsPrivate: &isPrivate,
+	}
+	logs, err := s.db.ListBattleLogs(ctx, filter, metaBattleLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]*analysis.BattleSummary, 0, len(logs))
+	for _, battleLog := range logs {
+		summary, err := analysis.ParseShowdownLog(battleLog)
+		if err != nil {
+			s.logger.Infof("Skipping unparseable battle log: %v", err)
+			continue
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries, nil
+}
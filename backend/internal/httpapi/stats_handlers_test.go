@@ -0,0 +1,239 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/dtsong/vgccorner/backend/internal/analysis"
+	"github.com/dtsong/vgccorner/backend/internal/db"
+	"github.com/dtsong/vgccorner/backend/internal/observability"
+)
+
+func TestGetMetaStats(t *testing.T) {
+	conn, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	logger := observability.NewLogger()
+	server := &Server{
+		logger:    logger,
+		db:        db.NewDatabaseFromConn(conn),
+		metaCache: newTTLCache(metaCacheTTL),
+	}
+
+	secondLog := `|player|p1|Player3|test|1500
+|player|p2|Player4|test|1500
+|tier|[Gen 9] VGC 2025 Reg H (Bo3)
+|poke|p1|Pikachu, L50, M|
+|poke|p1|Gengar, L50, M|
+|poke|p2|Blastoise, L50, M|
+|poke|p2|Snorlax, L50, M|
+|start
+|switch|p1a: Gengar|Gengar, L50, M|100/100
+|switch|p2a: Blastoise|Blastoise, L50, M|100/100
+|turn|1
+|move|p1a: Gengar|Shadow Ball|p2a: Blastoise
+|-damage|p2a: Blastoise|50/100
+|move|p2a: Blastoise|Protect|p2a: Blastoise
+|upkeep
+|win|Player3`
+
+	mock.ExpectQuery("SELECT battle_log FROM battles").
+		WithArgs("[Gen 9] VGC 2025 Reg H (Bo3)", false, metaBattleLimit).
+		WillReturnRows(sqlmock.NewRows([]string{"battle_log"}).
+			AddRow(sampleShowdownLog()).
+			AddRow(secondLog))
+
+	query := url.Values{"format": {"[Gen 9] VGC 2025 Reg H (Bo3)"}}
+	req := httptest.NewRequest("GET", "/api/stats/meta?"+query.Encode(), nil)
+	w := httptest.NewRecorder()
+	server.handleGetMetaStats(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+
+	var resp MetaStatsResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if resp.Data.BattleCount != 2 {
+		t.Errorf("expected 2 battles, got %d", resp.Data.BattleCount)
+	}
+
+	expectCount(t, "pokemon", resp.Data.TopPokemon, "Pikachu", 2)
+	expectCount(t, "pokemon", resp.Data.TopPokemon, "Blastoise", 2)
+	expectCount(t, "pokemon", resp.Data.TopPokemon, "Charizard", 1)
+	expectCount(t, "moves", resp.Data.TopMoves, "Protect", 2)
+	expectCount(t, "moves", resp.Data.TopMoves, "Shadow Ball", 1)
+	expectCount(t, "leads", resp.Data.Leads, "Blastoise", 2)
+	expectCount(t, "leads", resp.Data.Leads, "Pikachu", 1)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+
+	// A second request is served from the cache without touching the database
+	w = httptest.NewRecorder()
+	server.handleGetMetaStats(w, req)
+
+	var cached MetaStatsResponse
+	_ = json.NewDecoder(w.Body).Decode(&cached)
+	if cached.Metadata == nil || !cached.Metadata.Cached {
+		t.Error("expected second response to be served from cache")
+	}
+	if cached.Data.BattleCount != 2 {
+		t.Errorf("expected cached battle count 2, got %d", cached.Data.BattleCount)
+	}
+}
+
+func TestGetMetaStatsEmpty(t *testing.T) {
+	logger := observability.NewLogger()
+	server := &Server{logger: logger, db: nil}
+
+	req := httptest.NewRequest("GET", "/api/stats/meta?format=unknown", nil)
+	w := httptest.NewRecorder()
+	server.handleGetMetaStats(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+
+	var resp map[string]interface{}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	data, ok := resp["data"].(map[string]interface{})
+	if !ok {
+		t.Fatal("expected data object in response")
+	}
+	for _, field := range []string{"topPokemon", "topMoves", "leads"} {
+		list, ok := data[field].([]interface{})
+		if !ok {
+			t.Errorf("expected %s to be an array, got %v", field, data[field])
+			continue
+		}
+		if len(list) != 0 {
+			t.Errorf("expected %s to be empty, got %v", field, list)
+		}
+	}
+}
+
+func TestGetEffectivenessStats(t *testing.T) {
+	conn, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	server := &Server{
+		logger:    observability.NewLogger(),
+		db:        db.NewDatabaseFromConn(conn),
+		metaCache: newTTLCache(metaCacheTTL),
+	}
+
+	// Thunderbolt is super effective on Blastoise in the sample battle
+	mock.ExpectQuery("SELECT battle_log FROM battles").
+		WithArgs("[Gen 9] VGC 2025 Reg H (Bo3)", false, metaBattleLimit).
+		WillReturnRows(sqlmock.NewRows([]string{"battle_log"}).
+			AddRow(sampleShowdownLog()).
+			AddRow(sampleShowdownLog()))
+
+	query := url.Values{"format": {"[Gen 9] VGC 2025 Reg H (Bo3)"}}
+	req := httptest.NewRequest("GET", "/api/stats/effectiveness?"+query.Encode(), nil)
+	w := httptest.NewRecorder()
+	server.handleGetEffectivenessStats(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp EffectivenessStatsResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if resp.Data.BattleCount != 2 {
+		t.Errorf("expected 2 battles, got %d", resp.Data.BattleCount)
+	}
+	if electric := resp.Data.Types["Electric"]; electric.SuperEffective != 2 {
+		t.Errorf("expected 2 super-effective Electric hits, got %+v", electric)
+	}
+	if fire := resp.Data.Types["Fire"]; fire.Resisted != 2 || fire.SuperEffective != 0 {
+		t.Errorf("expected 2 resisted Fire hits, got %+v", fire)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+
+	// A second request is served from the cache without touching the database,
+	// and doesn't collide with the meta stats cached for the same format
+	w = httptest.NewRecorder()
+	server.handleGetEffectivenessStats(w, req)
+
+	var cached EffectivenessStatsResponse
+	_ = json.NewDecoder(w.Body).Decode(&cached)
+	if cached.Metadata == nil || !cached.Metadata.Cached {
+		t.Error("expected second response to be served from cache")
+	}
+	if _, ok := server.metaCache.Get("[Gen 9] VGC 2025 Reg H (Bo3)"); ok {
+		t.Error("expected effectiveness stats not to be cached under the meta stats key")
+	}
+}
+
+func TestGetEffectivenessStatsEmpty(t *testing.T) {
+	server := &Server{logger: observability.NewLogger(), db: nil}
+
+	req := httptest.NewRequest("GET", "/api/stats/effectiveness", nil)
+	w := httptest.NewRecorder()
+	server.handleGetEffectivenessStats(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+
+	var resp map[string]interface{}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	data, ok := resp["data"].(map[string]interface{})
+	if !ok {
+		t.Fatal("expected data object in response")
+	}
+	if types, ok := data["types"].(map[string]interface{}); !ok || len(types) != 0 {
+		t.Errorf("expected an empty types object, got %v", data["types"])
+	}
+}
+
+func expectCount(t *testing.T, list string, usage []analysis.UsageCount, name string, count int) {
+	t.Helper()
+	for _, u := range usage {
+		if u.Name == name {
+			if u.Count != count {
+				t.Errorf("%s: expected %s count %d, got %d", list, name, count, u.Count)
+			}
+			return
+		}
+	}
+	t.Errorf("%s: expected %s to be present in %v", list, name, usage)
+}
+
+func TestInvalidateCachesClearsMetaStats(t *testing.T) {
+	server := &Server{logger: observability.NewLogger(), metaCache: newTTLCache(metaCacheTTL)}
+	server.metaCache.Set("", analysis.MetaStats{BattleCount: 3})
+
+	server.invalidateCaches()
+
+	if _, ok := server.metaCache.Get(""); ok {
+		t.Error("expected meta stats cache to be cleared")
+	}
+}
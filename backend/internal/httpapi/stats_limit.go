@@ -0,0 +1,16 @@
+package httpapi
+
+import "github.com/dtsong/vgccorner/backend/internal/analysis"
+
+// limitStatsMaps returns summary with the stats maps that grow with move
+// variety cut down to the server's limit. The summary may be shared through
+// the summary cache, so a trimmed copy is returned rather than editing it.
+func (s *Server) limitStatsMaps(summary *analysis.BattleSummary) *analysis.BattleSummary {
+	if s.statsLimit <= 0 {
+		return summary
+	}
+	trimmed := *summary
+	trimmed.Stats.MoveFrequency = analysis.TopCounts(summary.Stats.MoveFrequency, s.statsLimit)
+	trimmed.Stats.TypeCoverage = analysis.TopCounts(summary.Stats.TypeCoverage, s.statsLimit)
+	return &trimmed
+}
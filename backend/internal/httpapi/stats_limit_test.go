@@ -0,0 +1,55 @@
+package httpapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dtsong/vgccorner/backend/internal/analysis"
+	"github.com/dtsong/vgccorner/backend/internal/observability"
+)
+
+func TestAnalyzeShowdownStatsMapLimit(t *testing.T) {
+	full, err := analysis.ParseEnhancedShowdownLog(sampleShowdownLog())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(full.Stats.MoveFrequency) <= 2 {
+		t.Fatalf("fixture needs more than 2 distinct moves, got %v", full.Stats.MoveFrequency)
+	}
+
+	router := NewRouter(observability.NewLogger(), nil, WithStatsMapLimit(2))
+	body, _ := json.Marshal(AnalyzeShowdownRequest{
+		AnalysisType: "rawLog",
+		RawLog:       sampleShowdownLog(),
+	})
+	req := httptest.NewRequest("POST", "/api/v1/showdown/analyze", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp AnalyzeResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	moves := resp.Data.Stats.MoveFrequency
+	if len(moves) != 3 {
+		t.Fatalf("expected 2 moves plus other, got %v", moves)
+	}
+	total := 0
+	for _, count := range full.Stats.MoveFrequency {
+		total += count
+	}
+	sum := 0
+	for _, count := range moves {
+		sum += count
+	}
+	if moves[analysis.OtherCount] == 0 || sum != total {
+		t.Errorf("expected the remaining %d uses under other, got %v", total, moves)
+	}
+}
@@ -0,0 +1,103 @@
+package httpapi
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+
+	"github.com/dtsong/vgccorner/backend/internal/analysis"
+)
+
+// defaultSummaryCacheSize is how many parsed summaries are kept by default.
+const defaultSummaryCacheSize = 128
+
+// summaryCache is a bounded LRU of parsed summaries keyed by the SHA-256 of
+// the raw log. A nil *summaryCache is valid and never caches anything.
+//
+// Cached summaries are shared between requests and must not be modified.
+type summaryCache struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List // front = most recently used
+	entries  map[string]*list.Element
+	hits     int
+	parses   int
+}
+
+type summaryEntry struct {
+	key     string
+	summary *analysis.BattleSummary
+}
+
+// newSummaryCache returns a cache holding up to capacity summaries, or nil
+// (caching disabled) when capacity <= 0.
+func newSummaryCache(capacity int) *summaryCache {
+	if capacity <= 0 {
+		return nil
+	}
+	return &summaryCache{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+// Parse returns the cached summary for battleLog, parsing and caching it on a
+// miss. Parse errors are not cached.
+func (c *summaryCache) Parse(battleLog string) (*analysis.BattleSummary, error) {
+	summary, _, err := c.ParseCached(battleLog)
+	return summary, err
+}
+
+// ParseCached is Parse, also reporting whether the summary came from the
+// cache rather than being parsed for this call.
+func (c *summaryCache) ParseCached(battleLog string) (*analysis.BattleSummary, bool, error) {
+	if c == nil {
+		summary, err := analysis.ParseEnhancedShowdownLog(battleLog)
+		return summary, false, err
+	}
+
+	sum := sha256.Sum256([]byte(battleLog))
+	key := hex.EncodeToString(sum[:])
+
+	c.mu.Lock()
+	if elem, ok := c.entries[key]; ok {
+		c.order.MoveToFront(elem)
+		c.hits++
+		c.mu.Unlock()
+		return elem.Value.(*summaryEntry).summary, true, nil
+	}
+	c.parses++
+	c.mu.Unlock()
+
+	// Parse outside the lock; concurrent misses on one log may both parse
+	summary, err := analysis.ParseEnhancedShowdownLog(battleLog)
+	if err != nil {
+		return nil, false, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.entries[key]; ok {
+		c.order.MoveToFront(elem)
+		return elem.Value.(*summaryEntry).summary, false, nil
+	}
+	c.entries[key] = c.order.PushFront(&summaryEntry{key: key, summary: summary})
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*summaryEntry).key)
+	}
+	return summary, false, nil
+}
+
+// Stats reports cache hits and parses (misses) so far.
+func (c *summaryCache) Stats() (hits, parses int) {
+	if c == nil {
+		return 0, 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.hits, c.parses
+}
@@ -0,0 +1,71 @@
+package httpapi
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSummaryCacheHit(t *testing.T) {
+	cache := newSummaryCache(4)
+
+	first, cached, err := cache.ParseCached(sampleShowdownLog())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cached {
+		t.Error("expected the first parse to be reported as a miss")
+	}
+	second, cached, err := cache.ParseCached(sampleShowdownLog())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cached {
+		t.Error("expected the second parse to be reported as a hit")
+	}
+
+	hits, parses := cache.Stats()
+	if parses != 1 || hits != 1 {
+		t.Errorf("expected 1 parse and 1 hit, got %d parses and %d hits", parses, hits)
+	}
+	if first != second {
+		t.Error("expected the cached summary to be returned on a hit")
+	}
+}
+
+func TestSummaryCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	cache := newSummaryCache(2)
+	logs := make([]string, 3)
+	for i := range logs {
+		logs[i] = fmt.Sprintf("|player|p1|Player%d|\n|start\n|turn|1\n", i)
+	}
+
+	_, _ = cache.Parse(logs[0])
+	_, _ = cache.Parse(logs[1])
+	_, _ = cache.Parse(logs[0]) // logs[1] is now least recently used
+	_, _ = cache.Parse(logs[2]) // evicts logs[1]
+	_, _ = cache.Parse(logs[0])
+
+	if _, parses := cache.Stats(); parses != 3 {
+		t.Fatalf("expected 3 parses before re-requesting an evicted log, got %d", parses)
+	}
+
+	_, _ = cache.Parse(logs[1])
+	if _, parses := cache.Stats(); parses != 4 {
+		t.Errorf("expected the evicted log to be re-parsed, got %d parses", parses)
+	}
+}
+
+func TestSummaryCacheDisabled(t *testing.T) {
+	cache := newSummaryCache(0)
+	if cache != nil {
+		t.Fatal("expected a zero capacity to disable the cache")
+	}
+
+	summary, err := cache.Parse(sampleShowdownLog())
+	if err != nil || summary == nil {
+		t.Fatalf("expected a disabled cache to still parse, got %v, %v", summary, err)
+	}
+	if hits, parses := cache.Stats(); hits != 0 || parses != 0 {
+		t.Errorf("expected no stats from a disabled cache, got %d hits and %d parses", hits, parses)
+	}
+}
@@ -1,7 +1,6 @@
 package httpapi
 
 import (
-	"encoding/json"
 	"net/http"
 )
 
@@ -19,32 +18,18 @@ type AnalyzeTCGLiveResponse struct {
 
 // handleAnalyzeTCGLive handles POST /api/tcglive/analyze requests.
 func (s *Server) handleAnalyzeTCGLive(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-
 	var req AnalyzeTCGLiveRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := decodeJSONBody(r, &req); err != nil {
 		s.logger.Infof("Failed to decode request body: %v", err)
-		w.WriteHeader(http.StatusBadRequest)
-		_ = json.NewEncoder(w).Encode(ErrorResponse{
-			Error: "Invalid request body",
-			Code:  "INVALID_REQUEST",
-		})
+		writeJSONError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
 		return
 	}
 
-	if req.GameExport == "" {
-		w.WriteHeader(http.StatusBadRequest)
-		_ = json.NewEncoder(w).Encode(ErrorResponse{
-			Error: "gameExport is required",
-			Code:  "INVALID_REQUEST",
-		})
+	if errs := req.Validate(); len(errs) > 0 {
+		writeValidationErrors(w, errs)
 		return
 	}
 
 	// TCG Live analysis is not yet implemented
-	w.WriteHeader(http.StatusNotImplemented)
-	_ = json.NewEncoder(w).Encode(ErrorResponse{
-		Error: "TCG Live analysis is planned for a future release",
-		Code:  "NOT_IMPLEMENTED",
-	})
+	writeJSONError(w, http.StatusNotImplemented, "NOT_IMPLEMENTED", "TCG Live analysis is planned for a future release")
 }
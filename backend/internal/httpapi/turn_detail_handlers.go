@@ -0,0 +1,44 @@
+package httpapi
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/dtsong/vgccorner/backend/internal/analysis"
+	"github.com/go-chi/chi/v5"
+)
+
+// SingleTurnResponse carries one turn of a stored battle, so a turn scrubber
+// can load turns lazily instead of fetching the whole summary.
+type SingleTurnResponse struct {
+	BattleID   string        `json:"battleId"`
+	TotalTurns int           `json:"totalTurns"`
+	Turn       analysis.Turn `json:"turn"`
+}
+
+// handleGetBattleTurn handles GET /api/battles/{id}/turns/{n} requests,
+// returning turn n (1-indexed) with its actions and the weather and field
+// state after it.
+func (s *Server) handleGetBattleTurn(w http.ResponseWriter, r *http.Request) {
+	n, err := strconv.Atoi(chi.URLParam(r, "n"))
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, "INVALID_REQUEST", "turn must be a number")
+		return
+	}
+
+	summary, ok := s.storedSummary(w, r, chi.URLParam(r, "id"))
+	if !ok {
+		return
+	}
+
+	if n < 1 || n > len(summary.Turns) {
+		writeJSONError(w, http.StatusNotFound, "NOT_FOUND", "Turn not found")
+		return
+	}
+
+	s.writeJSON(w, http.StatusOK, SingleTurnResponse{
+		BattleID:   chi.URLParam(r, "id"),
+		TotalTurns: len(summary.Turns),
+		Turn:       summary.Turns[n-1],
+	})
+}
@@ -0,0 +1,90 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/dtsong/vgccorner/backend/internal/db"
+	"github.com/dtsong/vgccorner/backend/internal/observability"
+)
+
+func TestGetBattleTurn(t *testing.T) {
+	conn, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	expectStoredBattle(mock, "battle-1", sampleShowdownLog())
+
+	router := NewRouter(observability.NewLogger(), db.NewDatabaseFromConn(conn))
+	req := httptest.NewRequest("GET", "/api/v1/battles/battle-1/turns/1", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp SingleTurnResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.BattleID != "battle-1" || resp.TotalTurns != 4 {
+		t.Errorf("expected battle-1 with 4 turns, got %q with %d", resp.BattleID, resp.TotalTurns)
+	}
+	if resp.Turn.TurnNumber != 1 {
+		t.Fatalf("expected turn 1, got %d", resp.Turn.TurnNumber)
+	}
+	if len(resp.Turn.Actions) != 2 {
+		t.Fatalf("expected 2 actions, got %d", len(resp.Turn.Actions))
+	}
+	for i, want := range []string{"Thunderbolt", "Hydro Pump"} {
+		action := resp.Turn.Actions[i]
+		if action.Move == nil || action.Move.Name != want {
+			t.Errorf("expected action %d to be %s, got %+v", i, want, action.Move)
+		}
+	}
+}
+
+func TestGetBattleTurnErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		turn       string
+		stored     bool
+		wantStatus int
+	}{
+		{name: "non-numeric", turn: "first", wantStatus: http.StatusBadRequest},
+		{name: "zero", turn: "0", stored: true, wantStatus: http.StatusNotFound},
+		{name: "past the end", turn: "5", stored: true, wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("failed to create mock: %v", err)
+			}
+			defer func() { _ = conn.Close() }()
+
+			if tt.stored {
+				expectStoredBattle(mock, "battle-1", sampleShowdownLog())
+			}
+
+			router := NewRouter(observability.NewLogger(), db.NewDatabaseFromConn(conn))
+			req := httptest.NewRequest("GET", "/api/v1/battles/battle-1/turns/"+tt.turn, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unmet expectations: %v", err)
+			}
+		})
+	}
+}
@@ -1,7 +1,6 @@
 package httpapi
 
 import (
-	"encoding/json"
 	"net/http"
 
 	"github.com/dtsong/vgccorner/backend/internal/analysis"
@@ -71,58 +70,34 @@ type PlayerArchetype struct {
 
 // handleGetTurnAnalysis handles GET /api/showdown/replays/{replayId}/turns requests
 func (s *Server) handleGetTurnAnalysis(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
 	replayID := chi.URLParam(r, "replayId")
 
 	if replayID == "" {
-		w.WriteHeader(http.StatusBadRequest)
-		_ = json.NewEncoder(w).Encode(ErrorResponse{
-			Error: "replayId is required",
-			Code:  "INVALID_REQUEST",
-		})
+		writeJSONError(w, http.StatusBadRequest, "INVALID_REQUEST", "replayId is required")
 		return
 	}
 
 	s.logger.Infof("Retrieving turn analysis for replay: %s", replayID)
 
-	// Database required for this endpoint
-	if s.db == nil {
-		w.WriteHeader(http.StatusServiceUnavailable)
-		_ = json.NewEncoder(w).Encode(ErrorResponse{
-			Error: "Database not configured",
-			Code:  "SERVICE_UNAVAILABLE",
-		})
-		return
-	}
-
 	ctx := r.Context()
 
 	// Retrieve turn data from database
 	turnData, err := s.db.GetTurnData(ctx, replayID)
 	if err != nil {
 		s.logger.Infof("Failed to retrieve turn data: %v", err)
-		w.WriteHeader(http.StatusInternalServerError)
-		_ = json.NewEncoder(w).Encode(ErrorResponse{
-			Error: "Internal server error",
-			Code:  "INTERNAL_ERROR",
-		})
+		writeJSONError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Internal server error")
 		return
 	}
 
 	if turnData == nil {
-		w.WriteHeader(http.StatusNotFound)
-		_ = json.NewEncoder(w).Encode(ErrorResponse{
-			Error: "Battle not found",
-			Code:  "NOT_FOUND",
-		})
+		writeJSONError(w, http.StatusNotFound, "NOT_FOUND", "Battle not found")
 		return
 	}
 
 	// Convert to API response format
 	response := convertTurnDataToResponse(turnData)
 
-	w.WriteHeader(http.StatusOK)
-	_ = json.NewEncoder(w).Encode(response)
+	s.writeJSON(w, http.StatusOK, response)
 }
 
 // convertTurnDataToResponse converts database TurnAnalysisData to API response
@@ -0,0 +1,49 @@
+package httpapi
+
+import (
+	"net/http"
+
+	"github.com/dtsong/vgccorner/backend/internal/analysis"
+)
+
+// ValidateShowdownRequest is the body of a dry-run validation.
+type ValidateShowdownRequest struct {
+	RawLog string `json:"rawLog"`
+}
+
+// ValidateResponse reports whether a log is fit to store. Warnings (a
+// truncated log, a bring count mismatch) don't make a log invalid;
+// violations (format legality, an unparseable log) do.
+type ValidateResponse struct {
+	Valid      bool     `json:"valid"`
+	Warnings   []string `json:"warnings"`
+	Violations []string `json:"violations"`
+}
+
+// handleValidateShowdown handles POST /api/showdown/validate requests. It
+// parses the log and checks format legality without storing anything.
+func (s *Server) handleValidateShowdown(w http.ResponseWriter, r *http.Request) {
+	var req ValidateShowdownRequest
+	if err := decodeJSONBody(r, &req); err != nil {
+		s.logger.Infof("Failed to decode request body: %v", err)
+		writeJSONError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+
+	if errs := req.Validate(); len(errs) > 0 {
+		writeValidationErrors(w, errs)
+		return
+	}
+
+	resp := ValidateResponse{Warnings: []string{}, Violations: []string{}}
+	summary, err := s.summaryCache.Parse(req.RawLog)
+	if err != nil {
+		resp.Violations = append(resp.Violations, "Failed to parse battle log: "+err.Error())
+	} else {
+		resp.Warnings = append(resp.Warnings, summary.Warnings...)
+		resp.Violations = append(resp.Violations, analysis.CheckLegality(summary)...)
+	}
+	resp.Valid = len(resp.Violations) == 0
+
+	s.writeJSON(w, http.StatusOK, resp)
+}
@@ -0,0 +1,79 @@
+package httpapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/dtsong/vgccorner/backend/internal/db"
+	"github.com/dtsong/vgccorner/backend/internal/observability"
+)
+
+func validateLog(t *testing.T, log string) ValidateResponse {
+	t.Helper()
+
+	// No queries are expected: validation never touches the database
+	conn, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	body, _ := json.Marshal(ValidateShowdownRequest{RawLog: log})
+	router := NewRouter(observability.NewLogger(), db.NewDatabaseFromConn(conn))
+	req := httptest.NewRequest("POST", "/api/v1/showdown/validate", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unexpected database use: %v", err)
+	}
+
+	var resp ValidateResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	return resp
+}
+
+func TestValidateShowdownClean(t *testing.T) {
+	resp := validateLog(t, sampleShowdownLog())
+
+	if !resp.Valid {
+		t.Errorf("expected a clean log to be valid, got violations %v", resp.Violations)
+	}
+	if resp.Warnings == nil || resp.Violations == nil {
+		t.Error("expected warnings and violations to be empty arrays, not null")
+	}
+}
+
+func TestValidateShowdownDuplicateSpecies(t *testing.T) {
+	log := strings.Replace(sampleShowdownLog(), "|start",
+		"|rule|Species Clause: Limit one of each Pokémon\n|poke|p1|Pikachu, L50, F|\n|start", 1)
+	resp := validateLog(t, log)
+
+	if resp.Valid {
+		t.Error("expected a duplicate species to be invalid")
+	}
+	if len(resp.Violations) != 1 || !strings.Contains(resp.Violations[0], "Pikachu") {
+		t.Errorf("expected one Species Clause violation for Pikachu, got %v", resp.Violations)
+	}
+}
+
+func TestValidateShowdownMissingLog(t *testing.T) {
+	router := NewRouter(observability.NewLogger(), nil)
+	req := httptest.NewRequest("POST", "/api/v1/showdown/validate", strings.NewReader(`{}`))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", w.Code)
+	}
+}
@@ -0,0 +1,111 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// FieldError describes a single invalid field in a request body.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// fieldErrors accumulates every problem with a request so they can be reported at once.
+type fieldErrors []FieldError
+
+func (e *fieldErrors) add(field, message string) {
+	*e = append(*e, FieldError{Field: field, Message: message})
+}
+
+// Validate checks an AnalyzeShowdownRequest and returns all field errors.
+func (req *AnalyzeShowdownRequest) Validate() []FieldError {
+	var errs fieldErrors
+
+	switch req.AnalysisType {
+	case "replayId":
+		if req.ReplayID == "" {
+			errs.add("replayId", "required for replayId analysis")
+		}
+	case "username":
+		if req.Username == "" {
+			errs.add("username", "required for username analysis")
+		}
+		if req.Format == "" {
+			errs.add("format", "required for username analysis")
+		}
+	case "rawLog":
+		if req.RawLog == "" {
+			errs.add("rawLog", "required for rawLog analysis")
+		}
+	case "":
+		errs.add("analysisType", "required")
+		// Without a discriminator we can't tell which input was intended,
+		// so flag every input when none was given.
+		if req.ReplayID == "" && req.Username == "" && req.RawLog == "" {
+			for _, field := range []string{"replayId", "username", "rawLog"} {
+				errs.add(field, "one of replayId, username, or rawLog is required")
+			}
+		}
+	default:
+		errs.add("analysisType", "must be one of: replayId, username, rawLog")
+	}
+
+	if req.Limit < 0 || req.Limit > 100 {
+		errs.add("limit", "must be between 0 and 100")
+	}
+
+	return errs
+}
+
+// Validate checks a ValidateShowdownRequest and returns all field errors.
+func (req *ValidateShowdownRequest) Validate() []FieldError {
+	var errs fieldErrors
+
+	if strings.TrimSpace(req.RawLog) == "" {
+		errs.add("rawLog", "required")
+	}
+
+	return errs
+}
+
+// Validate checks an AnalyzeTCGLiveRequest and returns all field errors.
+func (req *AnalyzeTCGLiveRequest) Validate() []FieldError {
+	var errs fieldErrors
+
+	if strings.TrimSpace(req.GameExport) == "" {
+		errs.add("gameExport", "required")
+	}
+
+	return errs
+}
+
+// decodeJSONBody decodes the request body into dst. An empty body decodes as
+// an empty object so that it is reported through field validation.
+func decodeJSONBody(r *http.Request, dst interface{}) error {
+	if r.Body == nil {
+		return nil
+	}
+	err := json.NewDecoder(r.Body).Decode(dst)
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+	return err
+}
+
+// writeValidationErrors writes a 400 response listing every field error.
+func writeValidationErrors(w http.ResponseWriter, errs []FieldError) {
+	messages := make([]string, 0, len(errs))
+	for _, e := range errs {
+		messages = append(messages, e.Field+" "+e.Message)
+	}
+
+	writeErrorResponse(w, http.StatusBadRequest, ErrorResponse{
+		Error:  "Invalid request: " + strings.Join(messages, "; "),
+		Code:   "INVALID_REQUEST",
+		Errors: errs,
+	})
+}
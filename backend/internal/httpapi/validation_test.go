@@ -0,0 +1,107 @@
+package httpapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dtsong/vgccorner/backend/internal/observability"
+)
+
+func TestAnalyzeShowdownEmptyBodyFieldErrors(t *testing.T) {
+	logger := observability.NewLogger()
+	server := &Server{logger: logger, db: nil}
+
+	req := httptest.NewRequest("POST", "/api/showdown/analyze", bytes.NewReader(nil))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	server.handleAnalyzeShowdown(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+
+	var resp ErrorResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if resp.Code != "INVALID_REQUEST" {
+		t.Errorf("expected code INVALID_REQUEST, got %q", resp.Code)
+	}
+	if len(resp.Errors) < 2 {
+		t.Fatalf("expected multiple field errors, got %v", resp.Errors)
+	}
+
+	fields := make(map[string]string)
+	for _, e := range resp.Errors {
+		fields[e.Field] = e.Message
+	}
+	if fields["analysisType"] != "required" {
+		t.Errorf("expected analysisType to be required, got %q", fields["analysisType"])
+	}
+	if _, ok := fields["rawLog"]; !ok {
+		t.Error("expected an error for rawLog")
+	}
+}
+
+func TestAnalyzeShowdownRequestValidate(t *testing.T) {
+	tests := []struct {
+		name           string
+		request        AnalyzeShowdownRequest
+		expectedFields []string
+	}{
+		{
+			name:           "valid raw log",
+			request:        AnalyzeShowdownRequest{AnalysisType: "rawLog", RawLog: "|start"},
+			expectedFields: nil,
+		},
+		{
+			name:           "username missing both fields",
+			request:        AnalyzeShowdownRequest{AnalysisType: "username"},
+			expectedFields: []string{"username", "format"},
+		},
+		{
+			name:           "invalid type and limit",
+			request:        AnalyzeShowdownRequest{AnalysisType: "bogus", Limit: -1},
+			expectedFields: []string{"analysisType", "limit"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := tt.request.Validate()
+			if len(errs) != len(tt.expectedFields) {
+				t.Fatalf("expected %d errors, got %v", len(tt.expectedFields), errs)
+			}
+			for i, field := range tt.expectedFields {
+				if errs[i].Field != field {
+					t.Errorf("error %d: expected field %q, got %q", i, field, errs[i].Field)
+				}
+			}
+		})
+	}
+}
+
+func TestAnalyzeTCGLiveEmptyBody(t *testing.T) {
+	logger := observability.NewLogger()
+	server := &Server{logger: logger, db: nil}
+
+	req := httptest.NewRequest("POST", "/api/tcglive/analyze", bytes.NewReader(nil))
+	w := httptest.NewRecorder()
+
+	server.handleAnalyzeTCGLive(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+
+	var resp ErrorResponse
+	_ = json.NewDecoder(w.Body).Decode(&resp)
+	if len(resp.Errors) != 1 || resp.Errors[0].Field != "gameExport" {
+		t.Errorf("expected a gameExport field error, got %v", resp.Errors)
+	}
+}
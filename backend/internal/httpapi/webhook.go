@@ -0,0 +1,152 @@
+package httpapi
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/dtsong/vgccorner/backend/internal/observability"
+)
+
+const (
+	// webhookTimeout bounds each delivery attempt.
+	webhookTimeout = 5 * time.Second
+	// webhookAttempts is how many times a delivery is tried before giving up.
+	webhookAttempts = 3
+	// webhookRetryDelay is the wait before the first retry; it doubles after each.
+	webhookRetryDelay = time.Second
+	// webhookSignatureHeader carries "sha256=<hex HMAC of the body>" when a
+	// secret is configured.
+	webhookSignatureHeader = "X-VGCCorner-Signature"
+	// battleStoredEvent is the Event of webhook payloads sent on battle store.
+	battleStoredEvent = "battle.stored"
+)
+
+// WebhookEvent is the JSON payload POSTed to the configured webhook URL.
+type WebhookEvent struct {
+	Event    string `json:"event"` // "battle.stored"
+	BattleID string `json:"battleId"`
+	Format   string `json:"format"`
+	Winner   string `json:"winner"` // "player1", "player2", "draw", or "" if unfinished
+}
+
+// webhookNotifier delivers events to a webhook URL in the background,
+// retrying failed attempts. A nil *webhookNotifier is valid and sends nothing.
+type webhookNotifier struct {
+	url        string
+	secret     []byte
+	client     *http.Client
+	logger     *observability.Logger
+	retryDelay time.Duration
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func newWebhookNotifier(url, secret string, logger *observability.Logger) *webhookNotifier {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &webhookNotifier{
+		url:        url,
+		secret:     []byte(secret),
+		client:     &http.Client{Timeout: webhookTimeout},
+		logger:     logger,
+		retryDelay: webhookRetryDelay,
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+}
+
+// notify sends event without blocking the caller. Failures are logged.
+func (n *webhookNotifier) notify(event WebhookEvent) {
+	if n == nil {
+		return
+	}
+	body, err := json.Marshal(event)
+	if err != nil {
+		n.logger.Errorf("webhook: failed to encode %s event: %v", event.Event, err)
+		return
+	}
+
+	n.wg.Add(1)
+	go func() {
+		defer n.wg.Done()
+		if err := n.deliver(body); err != nil && n.ctx.Err() == nil {
+			n.logger.Errorf("webhook: %s event for %s not delivered: %v", event.Event, event.BattleID, err)
+		}
+	}()
+}
+
+// deliver POSTs body until the endpoint accepts it, the attempts run out, or
+// the notifier is stopped. Client errors other than 429 aren't retried.
+func (n *webhookNotifier) deliver(body []byte) error {
+	delay := n.retryDelay
+	var err error
+	for attempt := 1; attempt <= webhookAttempts; attempt++ {
+		var retry bool
+		if retry, err = n.post(body); err == nil || !retry {
+			return err
+		}
+		if attempt == webhookAttempts {
+			break
+		}
+		select {
+		case <-n.ctx.Done():
+			return n.ctx.Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+	return fmt.Errorf("gave up after %d attempts: %w", webhookAttempts, err)
+}
+
+// post makes one delivery attempt and reports whether a failure is worth retrying.
+func (n *webhookNotifier) post(body []byte) (retry bool, err error) {
+	req, err := http.NewRequestWithContext(n.ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if len(n.secret) > 0 {
+		req.Header.Set(webhookSignatureHeader, signWebhook(n.secret, body))
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	_ = resp.Body.Close()
+
+	switch {
+	case resp.StatusCode < 300:
+		return false, nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return true, fmt.Errorf("webhook responded %s", resp.Status)
+	default:
+		return false, fmt.Errorf("webhook responded %s", resp.Status)
+	}
+}
+
+// stop abandons pending retries and waits for in-flight deliveries to finish.
+func (n *webhookNotifier) stop() {
+	if n == nil {
+		return
+	}
+	n.cancel()
+	n.wg.Wait()
+}
+
+// signWebhook returns the signature header value for body: "sha256=" and the
+// hex HMAC-SHA256 of body keyed by secret.
+func signWebhook(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,113 @@
+package httpapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dtsong/vgccorner/backend/internal/observability"
+)
+
+func TestWebhookFiresOnStore(t *testing.T) {
+	type delivery struct {
+		body      []byte
+		signature string
+	}
+	deliveries := make(chan delivery, 1)
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		deliveries <- delivery{body: body, signature: r.Header.Get(webhookSignatureHeader)}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer hook.Close()
+
+	logger := observability.NewLogger()
+	server := &Server{
+		logger:  logger,
+		store:   &fakeBattleStore{returnsID: "stored-123"},
+		webhook: newWebhookNotifier(hook.URL, "shh", logger),
+	}
+	defer server.webhook.stop()
+
+	body, _ := json.Marshal(AnalyzeShowdownRequest{
+		AnalysisType: "rawLog",
+		RawLog:       sampleShowdownLog(),
+		Store:        true,
+	})
+	req := httptest.NewRequest("POST", "/api/showdown/analyze", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+	server.handleAnalyzeShowdown(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var got delivery
+	select {
+	case got = <-deliveries:
+	case <-time.After(2 * time.Second):
+		t.Fatal("webhook was not called")
+	}
+
+	var event WebhookEvent
+	if err := json.Unmarshal(got.body, &event); err != nil {
+		t.Fatalf("failed to decode webhook payload: %v", err)
+	}
+	want := WebhookEvent{
+		Event:    battleStoredEvent,
+		BattleID: "stored-123",
+		Format:   "[Gen 9] VGC 2025 Reg H (Bo3)",
+		Winner:   "player2",
+	}
+	if event != want {
+		t.Errorf("expected payload %+v, got %+v", want, event)
+	}
+	if got.signature != signWebhook([]byte("shh"), got.body) {
+		t.Errorf("signature %q doesn't match the body", got.signature)
+	}
+}
+
+func TestWebhookRetries(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []int
+		attempts int32
+	}{
+		{"server error then success", []int{http.StatusInternalServerError, http.StatusOK}, 2},
+		{"rate limited throughout", []int{http.StatusTooManyRequests}, webhookAttempts},
+		{"client error", []int{http.StatusBadRequest}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts atomic.Int32
+			hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := int(attempts.Add(1))
+				w.WriteHeader(tt.statuses[min(n, len(tt.statuses))-1])
+			}))
+			defer hook.Close()
+
+			notifier := newWebhookNotifier(hook.URL, "", observability.NewLogger())
+			notifier.retryDelay = time.Millisecond
+			notifier.notify(WebhookEvent{Event: battleStoredEvent, BattleID: "b1"})
+			notifier.wg.Wait()
+			notifier.stop()
+
+			if got := attempts.Load(); got != tt.attempts {
+				t.Errorf("expected %d attempts, got %d", tt.attempts, got)
+			}
+		})
+	}
+}
+
+func TestWebhookDisabled(t *testing.T) {
+	// Servers without a webhook URL have a nil notifier
+	var notifier *webhookNotifier
+	notifier.notify(WebhookEvent{Event: battleStoredEvent, BattleID: "b1"})
+	notifier.stop()
+}
@@ -17,6 +17,10 @@ func (l *Logger) Infof(format string, args ...any) {
 	l.Printf("[INFO] "+format, args...)
 }
 
+func (l *Logger) Warnf(format string, args ...any) {
+	l.Printf("[WARN] "+format, args...)
+}
+
 func (l *Logger) Errorf(format string, args ...any) {
 	l.Printf("[ERROR] "+format, args...)
 }
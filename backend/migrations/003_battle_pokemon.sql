@@ -0,0 +1,19 @@
+-- Migration: Persist revealed teams with abilities and items
+-- Version: 003_battle_pokemon.sql
+
+-- Team members as revealed in a battle. Unlike pokemon, this doesn't require
+-- pokemon_species reference data; species are stored by name as logged.
+CREATE TABLE IF NOT EXISTS battle_pokemon (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    battle_id UUID NOT NULL REFERENCES battles(id) ON DELETE CASCADE,
+    player_number INT NOT NULL,
+    slot INT NOT NULL,
+    species VARCHAR(100) NOT NULL,
+    ability VARCHAR(100),
+    item VARCHAR(100),
+    tera_type VARCHAR(50),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(battle_id, player_number, slot)
+);
+
+CREATE INDEX IF NOT EXISTS idx_battle_pokemon_battle ON battle_pokemon(battle_id);
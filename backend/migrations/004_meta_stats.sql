@@ -0,0 +1,11 @@
+-- Migration: Precomputed meta statistics
+-- Version: 004_meta_stats.sql
+
+-- Meta stats per format, recomputed on a schedule by the API's meta worker.
+-- An empty format holds the aggregate across all formats.
+CREATE TABLE IF NOT EXISTS meta_stats (
+    format VARCHAR(100) PRIMARY KEY,
+    stats JSONB NOT NULL,
+    battle_count INT NOT NULL DEFAULT 0,
+    computed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,11 @@
+-- Migration: Precomputed type effectiveness statistics
+-- Version: 005_type_effectiveness_stats.sql
+
+-- Type effectiveness per format, recomputed alongside meta_stats by the API's
+-- meta worker. An empty format holds the aggregate across all formats.
+CREATE TABLE IF NOT EXISTS type_effectiveness_stats (
+    format VARCHAR(100) PRIMARY KEY,
+    stats JSONB NOT NULL,
+    battle_count INT NOT NULL DEFAULT 0,
+    computed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,7 @@
+-- Migration: Mark the Pokémon each player led with
+-- Version: 006_battle_pokemon_leads.sql
+
+-- Whether a team member was on the field when the battle started, so player
+-- stats can find favorite leads without re-parsing battle logs. Battles
+-- stored before this migration have no leads marked.
+ALTER TABLE battle_pokemon ADD COLUMN IF NOT EXISTS lead BOOLEAN NOT NULL DEFAULT FALSE;